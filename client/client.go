@@ -0,0 +1,220 @@
+// Package client calls the JSON API of a reverse-ats server. Its types are
+// generated from the server's models, so code using them breaks at compile
+// time when a field is renamed.
+package client
+
+//go:generate go run ../cmd/apigen -o models.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Client calls the API of one server
+type Client struct {
+	BaseURL    string       // Server address, e.g. http://localhost:5627
+	Token      string       // The server's REVERSE_ATS_API_TOKEN
+	HTTPClient *http.Client // http.DefaultClient when nil
+}
+
+func New(baseURL, token string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/"), Token: token}
+}
+
+// Error returns the message of an API error
+func (e *APIError) Error() string {
+	if len(e.Fields) == 0 {
+		return fmt.Sprintf("%d: %s", e.Status, e.Message)
+	}
+	fields := make([]string, 0, len(e.Fields))
+	for name, problem := range e.Fields {
+		fields = append(fields, name+": "+problem)
+	}
+	return fmt.Sprintf("%d: %s (%s)", e.Status, e.Message, strings.Join(fields, ", "))
+}
+
+// ListOptions narrow down and order a list. Filters are the query params of
+// the list, e.g. {"status": {"APPLIED", "INTERVIEWING"}}.
+type ListOptions struct {
+	Filters url.Values
+	Sort    string // Comma-separated fields, descending with a leading '-'
+	Page    int    // From 1
+	PerPage int
+}
+
+func (o ListOptions) query() url.Values {
+	query := url.Values{}
+	for param, values := range o.Filters {
+		query[param] = values
+	}
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	if o.Page > 0 {
+		query.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PerPage > 0 {
+		query.Set("per_page", strconv.Itoa(o.PerPage))
+	}
+	return query
+}
+
+// Resource calls the operations of one kind of record
+type Resource[T any] struct {
+	client *Client
+	path   string
+}
+
+func (c *Client) Companies() Resource[Company] {
+	return Resource[Company]{client: c, path: "/api/v1/companies"}
+}
+
+func (c *Client) Roles() Resource[Role] {
+	return Resource[Role]{client: c, path: "/api/v1/roles"}
+}
+
+func (c *Client) Contacts() Resource[Contact] {
+	return Resource[Contact]{client: c, path: "/api/v1/contacts"}
+}
+
+func (c *Client) Interviews() Resource[Interview] {
+	return Resource[Interview]{client: c, path: "/api/v1/interviews"}
+}
+
+// List fetches one page of records
+func (r Resource[T]) List(ctx context.Context, options ListOptions) (*APIList[T], error) {
+	var list APIList[T]
+	path := r.path
+	if query := options.query().Encode(); query != "" {
+		path += "?" + query
+	}
+	if err := r.client.do(ctx, http.MethodGet, path, nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// All fetches every record matching the options, page by page
+func (r Resource[T]) All(ctx context.Context, options ListOptions) ([]T, error) {
+	var items []T
+	for options.Page = 1; ; options.Page++ {
+		list, err := r.List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, list.Items...)
+		if list.Page >= list.TotalPages {
+			return items, nil
+		}
+	}
+}
+
+// Get fetches one record
+func (r Resource[T]) Get(ctx context.Context, id string) (*T, error) {
+	var item T
+	if err := r.client.do(ctx, http.MethodGet, r.path+"/"+url.PathEscape(id), nil, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Create creates a record and returns it as saved
+func (r Resource[T]) Create(ctx context.Context, item T) (*T, error) {
+	var created T
+	if err := r.client.do(ctx, http.MethodPost, r.path, item, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// Update saves every field of a record, as fetched and changed
+func (r Resource[T]) Update(ctx context.Context, id string, item T) (*T, error) {
+	var updated T
+	if err := r.client.do(ctx, http.MethodPut, r.path+"/"+url.PathEscape(id), item, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// Patch changes only the given fields of a record, e.g. {"Status": "OFFER"}
+func (r Resource[T]) Patch(ctx context.Context, id string, fields map[string]any) (*T, error) {
+	var updated T
+	if err := r.client.do(ctx, http.MethodPatch, r.path+"/"+url.PathEscape(id), fields, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// Delete deletes a record
+func (r Resource[T]) Delete(ctx context.Context, id string) error {
+	return r.client.do(ctx, http.MethodDelete, r.path+"/"+url.PathEscape(id), nil, nil)
+}
+
+// Stats fetches the stats page metrics over a range: "7", "30", "90", "180",
+// "365" days or "all". Use StatsBetween for other dates.
+func (c *Client) Stats(ctx context.Context, dateRange string) (*Stats, error) {
+	return c.stats(ctx, url.Values{"range": {dateRange}})
+}
+
+// StatsBetween fetches the stats page metrics between two YYYY-MM-DD days
+func (c *Client) StatsBetween(ctx context.Context, startDate, endDate string) (*Stats, error) {
+	return c.stats(ctx, url.Values{"range": {"custom"}, "start_date": {startDate}, "end_date": {endDate}})
+}
+
+func (c *Client) stats(ctx context.Context, query url.Values) (*Stats, error) {
+	var stats Stats
+	if err := c.do(ctx, http.MethodGet, "/api/v1/stats?"+query.Encode(), nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// do sends a request with an optional JSON body and decodes the JSON
+// response into out. Error responses are returned as *APIError.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		apiErr := &APIError{Status: resp.StatusCode, Message: resp.Status}
+		json.NewDecoder(resp.Body).Decode(apiErr)
+		return apiErr
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
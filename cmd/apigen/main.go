@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"log"
+	"os"
+	"reflect"
+
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/openapi"
+)
+
+// apigen writes the types of the API client from the models the API sends.
+// Run it with go generate ./client after changing a model.
+func main() {
+	out := flag.String("o", "models.go", "file to write")
+	pkg := flag.String("package", "client", "package of the file")
+	flag.Parse()
+
+	var src bytes.Buffer
+	err := openapi.WriteGoTypes(&src, *pkg, "cmd/apigen",
+		reflect.TypeFor[models.APIList[openapi.TypeParam]](),
+		reflect.TypeFor[models.APIError](),
+		reflect.TypeFor[models.Company](),
+		reflect.TypeFor[models.Role](),
+		reflect.TypeFor[models.Contact](),
+		reflect.TypeFor[models.Interview](),
+		reflect.TypeFor[models.Stats](),
+	)
+	if err != nil {
+		log.Fatalf("Failed to generate types: %v", err)
+	}
+
+	if err := os.WriteFile(*out, src.Bytes(), 0644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *out, err)
+	}
+}
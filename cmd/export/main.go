@@ -1,34 +1,93 @@
-package main
-
-import (
-	"fmt"
-	"log"
-
-	"github.com/pocketbase/pocketbase"
-
-	"reverse-ats/internal/exporter"
-)
-
-func main() {
-	// Fixed paths
-	exportDir := "./export"
-	dbPath := "./pb_data"
-
-	fmt.Printf("Exporting data to: %s\n", exportDir)
-	fmt.Printf("PocketBase data directory: %s\n\n", dbPath)
-
-	// Initialize PocketBase
-	app := pocketbase.NewWithConfig(pocketbase.Config{
-		DefaultDataDir: dbPath,
-	})
-
-	// Bootstrap PocketBase (loads collections schema)
-	if err := app.Bootstrap(); err != nil {
-		log.Fatalf("Failed to bootstrap PocketBase: %v", err)
-	}
-
-	// Export all tables
-	if err := exporter.ExportAll(app, exportDir); err != nil {
-		log.Fatalf("Export failed: %v", err)
-	}
-}
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pocketbase/pocketbase"
+
+	"reverse-ats/internal/exporter"
+	"reverse-ats/internal/util"
+	_ "reverse-ats/pb_migrations"
+)
+
+func main() {
+	dataDir := flag.String("data-dir", util.Getenv(util.EnvDataDir, "./pb_data"), "PocketBase data directory (env "+util.EnvDataDir+")")
+	dir := flag.String("dir", util.Getenv(util.EnvExportDir, "./export"), "directory to export to (env "+util.EnvExportDir+")")
+	file := flag.String("file", "", "export to a single file instead of a directory: a .zip of the CSV files, or a .json backup")
+	format := flag.String("format", "", "export format: csv or json (default csv, or the extension of -file)")
+	collections := flag.String("collections", "", "comma-separated CSV files to export: "+strings.Join(exporter.CSVCollections, ", ")+" (default all)")
+	status := flag.String("status", "", "comma-separated role statuses to export, e.g. APPLIED,INTERVIEWING")
+	appliedFrom := flag.String("applied-from", "", "export roles applied on or after this date (YYYY-MM-DD)")
+	appliedTo := flag.String("applied-to", "", "export roles applied on or before this date (YYYY-MM-DD)")
+	flag.Parse()
+
+	ext := strings.ToLower(filepath.Ext(*file))
+	if *format == "" {
+		*format = "csv"
+		if ext == ".json" {
+			*format = "json"
+		}
+	}
+	if *format != "csv" && *format != "json" {
+		log.Fatalf("Unknown format %q, expected csv or json", *format)
+	}
+	if *file != "" && *format == "csv" && ext != ".zip" {
+		log.Fatalf("A CSV export to a single file is a zip, name it .zip")
+	}
+
+	filter, err := exporter.ParseFilter([]string{*collections}, []string{*status}, *appliedFrom, *appliedTo)
+	if err != nil {
+		log.Fatalf("Invalid filter: %v", err)
+	}
+	if *format == "json" && !filter.IsZero() {
+		log.Fatalf("Filters only apply to the CSV export; the JSON export is a full backup")
+	}
+
+	// Exporting from a missing data directory would only create an empty one
+	if _, err := os.Stat(*dataDir); os.IsNotExist(err) {
+		log.Fatalf("PocketBase data directory does not exist: %s", *dataDir)
+	}
+
+	target := *file
+	if target == "" {
+		target = *dir
+	}
+	fmt.Printf("Exporting data to: %s\n", target)
+	fmt.Printf("PocketBase data directory: %s\n\n", *dataDir)
+
+	// Initialize PocketBase
+	app := pocketbase.NewWithConfig(pocketbase.Config{
+		DefaultDataDir: *dataDir,
+	})
+
+	// Bootstrap PocketBase (loads collections schema)
+	if err := app.Bootstrap(); err != nil {
+		log.Fatalf("Failed to bootstrap PocketBase: %v", err)
+	}
+
+	// Apply pending migrations, as the server does, so a new data directory
+	// gets the schema and an older one is brought up to date
+	if err := app.RunAllMigrations(); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	// Export all tables, or the filtered subset
+	switch {
+	case *format == "json" && *file != "":
+		err = exporter.ExportJSONFile(app, *file)
+	case *format == "json":
+		err = exporter.ExportJSON(app, *dir)
+	case *file != "":
+		err = exporter.ExportZipFile(app, *file, filter)
+	default:
+		err = exporter.ExportToDir(app, *dir, filter)
+	}
+	if err != nil {
+		log.Fatalf("Export failed: %v", err)
+	}
+}
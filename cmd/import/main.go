@@ -1,40 +1,116 @@
-package main
-
-import (
-	"fmt"
-	"log"
-	"os"
-
-	"github.com/pocketbase/pocketbase"
-
-	"reverse-ats/internal/importer"
-)
-
-func main() {
-	// Fixed paths
-	csvDir := "./import"
-	dbPath := "./pb_data"
-
-	// Check if directory exists
-	if _, err := os.Stat(csvDir); os.IsNotExist(err) {
-		log.Fatalf("Directory does not exist: %s", csvDir)
-	}
-
-	fmt.Printf("Importing CSV files from: %s\n", csvDir)
-	fmt.Printf("PocketBase data directory: %s\n\n", dbPath)
-
-	// Initialize PocketBase
-	app := pocketbase.NewWithConfig(pocketbase.Config{
-		DefaultDataDir: dbPath,
-	})
-
-	// Bootstrap PocketBase (loads collections schema)
-	if err := app.Bootstrap(); err != nil {
-		log.Fatalf("Failed to bootstrap PocketBase: %v", err)
-	}
-
-	// Import all CSV files
-	if err := importer.ImportAll(app, csvDir); err != nil {
-		log.Fatalf("Import failed: %v", err)
-	}
-}
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pocketbase/pocketbase"
+
+	"reverse-ats/internal/exporter"
+	"reverse-ats/internal/importer"
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/util"
+	_ "reverse-ats/pb_migrations"
+)
+
+func main() {
+	dataDir := flag.String("data-dir", util.Getenv(util.EnvDataDir, "./pb_data"), "PocketBase data directory (env "+util.EnvDataDir+")")
+	dir := flag.String("dir", util.Getenv(util.EnvImportDir, "./import"), "directory to import from (env "+util.EnvImportDir+")")
+	file := flag.String("file", "", "import a single file instead of a directory: a JSON export, or one of the CSV files")
+	format := flag.String("format", "", "import format: csv or json (default csv, or the extension of -file)")
+	dryRun := flag.Bool("dry-run", false, "check the files and report what would change without saving anything")
+	flag.Parse()
+
+	if *format == "" {
+		*format = "csv"
+		if strings.EqualFold(filepath.Ext(*file), ".json") {
+			*format = "json"
+		}
+	}
+	if *format != "csv" && *format != "json" {
+		log.Fatalf("Unknown format %q, expected csv or json", *format)
+	}
+
+	// The file to read, or the directory to read the CSV files from
+	source := *file
+	if source == "" && *format == "json" {
+		source = filepath.Join(*dir, exporter.JSONFilename)
+	}
+	if source == "" {
+		source = *dir
+	}
+
+	// Check if the file or directory exists
+	if _, err := os.Stat(source); os.IsNotExist(err) {
+		log.Fatalf("Not found: %s", source)
+	}
+
+	fmt.Printf("Importing %s from: %s\n", *format, source)
+	fmt.Printf("PocketBase data directory: %s\n", *dataDir)
+	if *dryRun {
+		fmt.Println("Dry run: nothing will be saved")
+	}
+	fmt.Println()
+
+	// Initialize PocketBase
+	app := pocketbase.NewWithConfig(pocketbase.Config{
+		DefaultDataDir: *dataDir,
+	})
+
+	// Bootstrap PocketBase (loads collections schema)
+	if err := app.Bootstrap(); err != nil {
+		log.Fatalf("Failed to bootstrap PocketBase: %v", err)
+	}
+
+	// Apply pending migrations, as the server does, so a new data directory
+	// gets the schema and an older one is brought up to date
+	if err := app.RunAllMigrations(); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	var report *models.ImportReport
+	var err error
+	switch {
+	case *format == "json":
+		// Restore a JSON export
+		report, err = importer.ImportJSON(app, source, *dryRun)
+	case *file != "":
+		report, err = importer.ImportFile(app, source, *dryRun)
+	default:
+		// Import all CSV files
+		report, err = importer.ImportDir(app, source, *dryRun)
+	}
+
+	if report != nil && *dryRun {
+		printProblems(*report)
+	}
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+	if *dryRun && report.HasProblems() {
+		log.Fatalf("Dry run found problems, nothing was saved")
+	}
+
+	if *dryRun {
+		fmt.Printf("\n✅ All %d rows checked, no problems found\n", report.Rows())
+	} else {
+		fmt.Println("\n✅ All data imported successfully!")
+	}
+}
+
+// printProblems lists the invalid rows a dry run found
+func printProblems(report models.ImportReport) {
+	for _, file := range report.Files {
+		for _, problem := range file.Problems {
+			if problem.Column != "" {
+				fmt.Printf("%s line %d, %s: %s\n", file.Step, problem.Line, problem.Column, problem.Problem)
+			} else {
+				fmt.Printf("%s line %d: %s\n", file.Step, problem.Line, problem.Problem)
+			}
+		}
+	}
+}
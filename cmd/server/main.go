@@ -2,25 +2,44 @@ package main
 
 import (
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/apis"
 	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/plugins/migratecmd"
+	"github.com/pocketbase/pocketbase/tools/osutils"
 
 	"reverse-ats/internal/handlers"
+	"reverse-ats/internal/util"
+	"reverse-ats/internal/webhooks"
 	_ "reverse-ats/pb_migrations"
 )
 
 func main() {
-	// Initialize PocketBase
-	app := pocketbase.New()
+	// Initialize PocketBase, with the data directory from the environment.
+	// PocketBase's --dir flag still overrides it.
+	app := pocketbase.NewWithConfig(pocketbase.Config{
+		DefaultDataDir: os.Getenv(util.EnvDataDir),
+		DefaultDev:     osutils.IsProbablyGoRun(),
+	})
 
-	// Get port from environment
-	port := os.Getenv("REVERSE_ATS_PORT")
-	if port == "" {
-		port = "5627"
-	}
+	// Get the address to serve on from the environment
+	addr := util.Getenv(util.EnvAddr, "0.0.0.0:"+util.Getenv(util.EnvPort, "5627"))
+
+	// PocketBase's migrate command, writing new migrations to ./pb_migrations
+	migratecmd.MustRegister(app, app.RootCmd, migratecmd.Config{
+		Dir: "pb_migrations",
+	})
+
+	// Send webhooks when roles, interviews and contacts change, retrying failed deliveries
+	dispatcher := webhooks.NewDispatcher(app)
+	handlers.WatchWebhookEvents(dispatcher)
+	dispatcher.Start()
 
 	// Hook into the serve event to add custom routes
 	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
@@ -29,9 +48,16 @@ func main() {
 		rolesHandler := handlers.NewRolesHandler(app)
 		contactsHandler := handlers.NewContactsHandler(app)
 		interviewsHandler := handlers.NewInterviewsHandler(app)
+		offersHandler := handlers.NewOffersHandler(app)
+		tasksHandler := handlers.NewTasksHandler(app)
+		documentsHandler := handlers.NewDocumentsHandler(app)
 		statsHandler := handlers.NewStatsHandler(app)
 		exportHandler := handlers.NewExportHandler(app)
 		importHandler := handlers.NewImportHandler(app)
+		calendarHandler := handlers.NewCalendarHandler(app, os.Getenv(util.EnvCalendarToken))
+		apiHandler := handlers.NewAPIHandler(app, os.Getenv(util.EnvAPIToken))
+		captureHandler := handlers.NewCaptureHandler(app, os.Getenv(util.EnvCaptureToken))
+		webhooksHandler := handlers.NewWebhooksHandler(app, dispatcher)
 
 		// Static files - serve from ./static directory
 		se.Router.GET("/static/{path...}", func(e *core.RequestEvent) error {
@@ -54,6 +80,9 @@ func main() {
 		se.Router.POST("/companies", func(e *core.RequestEvent) error {
 			return companiesHandler.Create(e.Response, e.Request)
 		})
+		se.Router.POST("/companies/bulk", func(e *core.RequestEvent) error {
+			return companiesHandler.Bulk(e.Response, e.Request)
+		})
 		se.Router.GET("/companies/new", func(e *core.RequestEvent) error {
 			return companiesHandler.New(e.Response, e.Request)
 		})
@@ -77,12 +106,18 @@ func main() {
 		se.Router.POST("/roles", func(e *core.RequestEvent) error {
 			return rolesHandler.Create(e.Response, e.Request)
 		})
+		se.Router.POST("/roles/bulk", func(e *core.RequestEvent) error {
+			return rolesHandler.Bulk(e.Response, e.Request)
+		})
 		se.Router.GET("/roles/new", func(e *core.RequestEvent) error {
 			return rolesHandler.New(e.Response, e.Request)
 		})
 		se.Router.GET("/roles/{id}/edit", func(e *core.RequestEvent) error {
 			return rolesHandler.Edit(e.Response, e.Request)
 		})
+		se.Router.GET("/roles/{id}/files/{field}", func(e *core.RequestEvent) error {
+			return rolesHandler.DownloadFile(e.Response, e.Request)
+		})
 		se.Router.POST("/roles/{id}", func(e *core.RequestEvent) error {
 			return rolesHandler.Update(e.Response, e.Request)
 		})
@@ -100,6 +135,9 @@ func main() {
 		se.Router.POST("/contacts", func(e *core.RequestEvent) error {
 			return contactsHandler.Create(e.Response, e.Request)
 		})
+		se.Router.POST("/contacts/bulk", func(e *core.RequestEvent) error {
+			return contactsHandler.Bulk(e.Response, e.Request)
+		})
 		se.Router.GET("/contacts/new", func(e *core.RequestEvent) error {
 			return contactsHandler.New(e.Response, e.Request)
 		})
@@ -115,6 +153,15 @@ func main() {
 		se.Router.DELETE("/contacts/{id}", func(e *core.RequestEvent) error {
 			return contactsHandler.Delete(e.Response, e.Request)
 		})
+		se.Router.GET("/contacts/{id}/contact.vcf", func(e *core.RequestEvent) error {
+			return contactsHandler.ExportVCard(e.Response, e.Request)
+		})
+		se.Router.POST("/contacts/vcard", func(e *core.RequestEvent) error {
+			return contactsHandler.ImportVCard(e.Response, e.Request)
+		})
+		se.Router.GET("/companies/{id}/contacts.vcf", func(e *core.RequestEvent) error {
+			return contactsHandler.ExportCompanyVCards(e.Response, e.Request)
+		})
 
 		// Interviews routes
 		se.Router.GET("/interviews", func(e *core.RequestEvent) error {
@@ -123,9 +170,15 @@ func main() {
 		se.Router.POST("/interviews", func(e *core.RequestEvent) error {
 			return interviewsHandler.Create(e.Response, e.Request)
 		})
+		se.Router.POST("/interviews/bulk", func(e *core.RequestEvent) error {
+			return interviewsHandler.Bulk(e.Response, e.Request)
+		})
 		se.Router.GET("/interviews/new", func(e *core.RequestEvent) error {
 			return interviewsHandler.New(e.Response, e.Request)
 		})
+		se.Router.POST("/interviews/new/invite", func(e *core.RequestEvent) error {
+			return interviewsHandler.NewFromInvite(e.Response, e.Request)
+		})
 		se.Router.GET("/interviews/{id}/edit", func(e *core.RequestEvent) error {
 			return interviewsHandler.Edit(e.Response, e.Request)
 		})
@@ -138,11 +191,132 @@ func main() {
 		se.Router.DELETE("/interviews/{id}", func(e *core.RequestEvent) error {
 			return interviewsHandler.Delete(e.Response, e.Request)
 		})
+		se.Router.GET("/interviews/{id}/calendar.ics", func(e *core.RequestEvent) error {
+			return interviewsHandler.DownloadICS(e.Response, e.Request)
+		})
+
+		// Calendar feed, for subscribing from a calendar app
+		se.Router.GET("/calendar/interviews.ics", func(e *core.RequestEvent) error {
+			return calendarHandler.Interviews(e.Response, e.Request)
+		})
+
+		// Offers routes
+		se.Router.GET("/offers", func(e *core.RequestEvent) error {
+			return offersHandler.List(e.Response, e.Request)
+		})
+		se.Router.POST("/offers", func(e *core.RequestEvent) error {
+			return offersHandler.Create(e.Response, e.Request)
+		})
+		se.Router.GET("/offers/new", func(e *core.RequestEvent) error {
+			return offersHandler.New(e.Response, e.Request)
+		})
+		se.Router.GET("/offers/compare", func(e *core.RequestEvent) error {
+			return offersHandler.Compare(e.Response, e.Request)
+		})
+		se.Router.GET("/offers/{id}/edit", func(e *core.RequestEvent) error {
+			return offersHandler.Edit(e.Response, e.Request)
+		})
+		se.Router.POST("/offers/{id}", func(e *core.RequestEvent) error {
+			return offersHandler.Update(e.Response, e.Request)
+		})
+		se.Router.PUT("/offers/{id}", func(e *core.RequestEvent) error {
+			return offersHandler.Update(e.Response, e.Request)
+		})
+		se.Router.DELETE("/offers/{id}", func(e *core.RequestEvent) error {
+			return offersHandler.Delete(e.Response, e.Request)
+		})
+
+		// Tasks routes
+		se.Router.GET("/tasks", func(e *core.RequestEvent) error {
+			return tasksHandler.List(e.Response, e.Request)
+		})
+		se.Router.POST("/tasks", func(e *core.RequestEvent) error {
+			return tasksHandler.Create(e.Response, e.Request)
+		})
+		se.Router.GET("/tasks/new", func(e *core.RequestEvent) error {
+			return tasksHandler.New(e.Response, e.Request)
+		})
+		se.Router.GET("/tasks/{id}/edit", func(e *core.RequestEvent) error {
+			return tasksHandler.Edit(e.Response, e.Request)
+		})
+		se.Router.POST("/tasks/{id}/toggle", func(e *core.RequestEvent) error {
+			return tasksHandler.Toggle(e.Response, e.Request)
+		})
+		se.Router.POST("/tasks/{id}", func(e *core.RequestEvent) error {
+			return tasksHandler.Update(e.Response, e.Request)
+		})
+		se.Router.PUT("/tasks/{id}", func(e *core.RequestEvent) error {
+			return tasksHandler.Update(e.Response, e.Request)
+		})
+		se.Router.DELETE("/tasks/{id}", func(e *core.RequestEvent) error {
+			return tasksHandler.Delete(e.Response, e.Request)
+		})
+
+		// Documents routes
+		se.Router.GET("/documents", func(e *core.RequestEvent) error {
+			return documentsHandler.List(e.Response, e.Request)
+		})
+		se.Router.POST("/documents", func(e *core.RequestEvent) error {
+			return documentsHandler.Create(e.Response, e.Request)
+		})
+		se.Router.GET("/documents/{id}/edit", func(e *core.RequestEvent) error {
+			return documentsHandler.Edit(e.Response, e.Request)
+		})
+		se.Router.GET("/documents/{id}/file", func(e *core.RequestEvent) error {
+			return documentsHandler.Download(e.Response, e.Request)
+		})
+		se.Router.POST("/documents/{id}", func(e *core.RequestEvent) error {
+			return documentsHandler.Update(e.Response, e.Request)
+		})
+		se.Router.PUT("/documents/{id}", func(e *core.RequestEvent) error {
+			return documentsHandler.Update(e.Response, e.Request)
+		})
+		se.Router.DELETE("/documents/{id}", func(e *core.RequestEvent) error {
+			return documentsHandler.Delete(e.Response, e.Request)
+		})
+
+		// Webhooks routes
+		se.Router.GET("/webhooks", func(e *core.RequestEvent) error {
+			return webhooksHandler.List(e.Response, e.Request)
+		})
+		se.Router.POST("/webhooks", func(e *core.RequestEvent) error {
+			return webhooksHandler.Create(e.Response, e.Request)
+		})
+		se.Router.GET("/webhooks/new", func(e *core.RequestEvent) error {
+			return webhooksHandler.New(e.Response, e.Request)
+		})
+		se.Router.GET("/webhooks/deliveries", func(e *core.RequestEvent) error {
+			return webhooksHandler.Deliveries(e.Response, e.Request)
+		})
+		se.Router.POST("/webhooks/deliveries/{id}/redeliver", func(e *core.RequestEvent) error {
+			return webhooksHandler.Redeliver(e.Response, e.Request)
+		})
+		se.Router.GET("/webhooks/{id}/edit", func(e *core.RequestEvent) error {
+			return webhooksHandler.Edit(e.Response, e.Request)
+		})
+		se.Router.POST("/webhooks/{id}/test", func(e *core.RequestEvent) error {
+			return webhooksHandler.Test(e.Response, e.Request)
+		})
+		se.Router.POST("/webhooks/{id}", func(e *core.RequestEvent) error {
+			return webhooksHandler.Update(e.Response, e.Request)
+		})
+		se.Router.PUT("/webhooks/{id}", func(e *core.RequestEvent) error {
+			return webhooksHandler.Update(e.Response, e.Request)
+		})
+		se.Router.DELETE("/webhooks/{id}", func(e *core.RequestEvent) error {
+			return webhooksHandler.Delete(e.Response, e.Request)
+		})
 
 		// API route for cascading dropdowns
 		se.Router.GET("/api/roles-by-company", func(e *core.RequestEvent) error {
 			return interviewsHandler.GetRolesByCompany(e.Response, e.Request)
 		})
+		se.Router.GET("/api/contacts-by-company", func(e *core.RequestEvent) error {
+			return interviewsHandler.GetContactsByCompany(e.Response, e.Request)
+		})
+		se.Router.POST("/api/interview-contacts", func(e *core.RequestEvent) error {
+			return interviewsHandler.CreateContact(e.Response, e.Request)
+		})
 
 		// Stats route
 		se.Router.GET("/stats", func(e *core.RequestEvent) error {
@@ -155,22 +329,158 @@ func main() {
 		})
 
 		// Import route
+		// An export zip with its uploaded files is past PocketBase's default body limit
 		se.Router.POST("/import", func(e *core.RequestEvent) error {
 			return importHandler.Import(e.Response, e.Request)
+		}).Bind(apis.BodyLimit(handlers.MaxArchiveSize))
+
+		// Job posting capture, from the bookmarklet or a browser extension
+		se.Router.GET("/capture", func(e *core.RequestEvent) error {
+			return captureHandler.Bookmarklet(e.Response, e.Request)
+		})
+		se.Router.POST("/capture", func(e *core.RequestEvent) error {
+			return captureHandler.Capture(e.Response, e.Request)
+		})
+
+		// JSON API, for scripts and other tools. Every route needs the API token,
+		// except its OpenAPI document.
+		se.Router.GET("/api/openapi.json", func(e *core.RequestEvent) error {
+			return apiHandler.OpenAPI(e.Response, e.Request)
+		})
+		api := se.Router.Group("/api/v1")
+		api.BindFunc(func(e *core.RequestEvent) error {
+			if err := apiHandler.Authorize(e.Response, e.Request); err != nil {
+				return err
+			}
+			return e.Next()
+		})
+		api.GET("/companies", func(e *core.RequestEvent) error {
+			return apiHandler.ListCompanies(e.Response, e.Request)
+		})
+		api.POST("/companies", func(e *core.RequestEvent) error {
+			return apiHandler.CreateCompany(e.Response, e.Request)
+		})
+		api.GET("/companies/{id}", func(e *core.RequestEvent) error {
+			return apiHandler.GetCompany(e.Response, e.Request)
+		})
+		api.PUT("/companies/{id}", func(e *core.RequestEvent) error {
+			return apiHandler.UpdateCompany(e.Response, e.Request)
+		})
+		api.PATCH("/companies/{id}", func(e *core.RequestEvent) error {
+			return apiHandler.UpdateCompany(e.Response, e.Request)
+		})
+		api.DELETE("/companies/{id}", func(e *core.RequestEvent) error {
+			return apiHandler.DeleteCompany(e.Response, e.Request)
+		})
+		api.GET("/roles", func(e *core.RequestEvent) error {
+			return apiHandler.ListRoles(e.Response, e.Request)
+		})
+		api.POST("/roles", func(e *core.RequestEvent) error {
+			return apiHandler.CreateRole(e.Response, e.Request)
+		})
+		api.GET("/roles/{id}", func(e *core.RequestEvent) error {
+			return apiHandler.GetRole(e.Response, e.Request)
+		})
+		api.PUT("/roles/{id}", func(e *core.RequestEvent) error {
+			return apiHandler.UpdateRole(e.Response, e.Request)
+		})
+		api.PATCH("/roles/{id}", func(e *core.RequestEvent) error {
+			return apiHandler.UpdateRole(e.Response, e.Request)
+		})
+		api.DELETE("/roles/{id}", func(e *core.RequestEvent) error {
+			return apiHandler.DeleteRole(e.Response, e.Request)
+		})
+		api.GET("/contacts", func(e *core.RequestEvent) error {
+			return apiHandler.ListContacts(e.Response, e.Request)
+		})
+		api.POST("/contacts", func(e *core.RequestEvent) error {
+			return apiHandler.CreateContact(e.Response, e.Request)
+		})
+		api.GET("/contacts/{id}", func(e *core.RequestEvent) error {
+			return apiHandler.GetContact(e.Response, e.Request)
+		})
+		api.PUT("/contacts/{id}", func(e *core.RequestEvent) error {
+			return apiHandler.UpdateContact(e.Response, e.Request)
+		})
+		api.PATCH("/contacts/{id}", func(e *core.RequestEvent) error {
+			return apiHandler.UpdateContact(e.Response, e.Request)
+		})
+		api.DELETE("/contacts/{id}", func(e *core.RequestEvent) error {
+			return apiHandler.DeleteContact(e.Response, e.Request)
+		})
+		api.GET("/interviews", func(e *core.RequestEvent) error {
+			return apiHandler.ListInterviews(e.Response, e.Request)
+		})
+		api.POST("/interviews", func(e *core.RequestEvent) error {
+			return apiHandler.CreateInterview(e.Response, e.Request)
+		})
+		api.GET("/interviews/{id}", func(e *core.RequestEvent) error {
+			return apiHandler.GetInterview(e.Response, e.Request)
+		})
+		api.PUT("/interviews/{id}", func(e *core.RequestEvent) error {
+			return apiHandler.UpdateInterview(e.Response, e.Request)
+		})
+		api.PATCH("/interviews/{id}", func(e *core.RequestEvent) error {
+			return apiHandler.UpdateInterview(e.Response, e.Request)
+		})
+		api.DELETE("/interviews/{id}", func(e *core.RequestEvent) error {
+			return apiHandler.DeleteInterview(e.Response, e.Request)
+		})
+		api.GET("/stats", func(e *core.RequestEvent) error {
+			return apiHandler.Stats(e.Response, e.Request)
 		})
 
 		return se.Next()
 	})
 
-	// Start the server
-	log.Printf("Starting server on http://localhost:%s", port)
-	log.Printf("PocketBase admin UI available at http://localhost:%s/_/", port)
-
-	// Set command line args to force serve mode
-	// PocketBase expects: program serve --http=host:port
-	os.Args = []string{os.Args[0], "serve", "--http=0.0.0.0:" + port}
+	// Serve unless another PocketBase command, like migrate or superuser, is given
+	args, serving := serveArgs(os.Args[1:], addr)
+	if serving {
+		// All interfaces include this machine
+		host := addr
+		if h, port, err := net.SplitHostPort(addr); err == nil && (h == "" || h == "0.0.0.0" || h == "::") {
+			host = "localhost:" + port
+		}
+		log.Printf("Starting server on http://%s", host)
+		log.Printf("PocketBase admin UI available at http://%s/_/", host)
+	}
+	app.RootCmd.SetArgs(args)
 
 	if err := app.Start(); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// serveArgs returns the command line to run PocketBase with: serve on addr
+// when no command is given, and any other command as is. Global flags like
+// --dir may come first either way. It reports whether the server serves on addr.
+func serveArgs(args []string, addr string) ([]string, bool) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-h" || arg == "--help" || arg == "-v" || arg == "--version":
+			return args, false
+		case strings.HasPrefix(arg, "-"):
+			// Skip the value of a global flag given as "--dir value"
+			if slices.Contains([]string{"--dir", "--encryptionEnv", "--queryTimeout"}, arg) {
+				i++
+			}
+		case arg == "serve" && !hasFlag(args[i+1:], "--http"):
+			return append(slices.Clone(args), "--http="+addr), true
+		default:
+			return args, false
+		}
+	}
+
+	return append([]string{"serve", "--http=" + addr}, args...), true
+}
+
+// hasFlag reports whether a flag is among the args, as "--flag value" or "--flag=value"
+func hasFlag(args []string, flag string) bool {
+	for _, arg := range args {
+		if arg == flag || strings.HasPrefix(arg, flag+"=") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+// Package contentline writes the content lines of iCalendar (RFC 5545) and
+// vCard (RFC 6350) files, which fold long lines the same way.
+package contentline
+
+import (
+	"bufio"
+	"io"
+	"unicode/utf8"
+)
+
+const (
+	maxLineOctets  = 75
+	lineTerminator = "\r\n"
+)
+
+// Writer writes content lines, folded to 75 octets. After an error it
+// writes nothing more, and Flush returns the error.
+type Writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Line writes a content line, continuing long lines on lines starting with a space
+func (cw *Writer) Line(s string) {
+	if cw.err != nil {
+		return
+	}
+
+	limit := maxLineOctets
+	for len(s) > limit {
+		// Never split a UTF-8 sequence
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		cw.write(s[:cut] + lineTerminator + " ")
+		s = s[cut:]
+		limit = maxLineOctets - 1 // The leading space counts
+	}
+	cw.write(s + lineTerminator)
+}
+
+func (cw *Writer) write(s string) {
+	if cw.err == nil {
+		_, cw.err = cw.w.WriteString(s)
+	}
+}
+
+// Flush writes the buffered lines, returning the first error
+func (cw *Writer) Flush() error {
+	if cw.err != nil {
+		return cw.err
+	}
+	return cw.w.Flush()
+}
@@ -0,0 +1,36 @@
+package contentline_test
+
+import (
+	"strings"
+	"testing"
+
+	"reverse-ats/internal/contentline"
+)
+
+func TestLineFolding(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"short", "SUMMARY:Tech screen", "SUMMARY:Tech screen\r\n"},
+		{"75 octets", strings.Repeat("a", 75), strings.Repeat("a", 75) + "\r\n"},
+		{"folded", strings.Repeat("a", 75+74+1), strings.Repeat("a", 75) + "\r\n " + strings.Repeat("a", 74) + "\r\n a\r\n"},
+		// The 2-octet é would straddle octet 75, so it starts the next line
+		{"UTF-8", strings.Repeat("a", 74) + "é", strings.Repeat("a", 74) + "\r\n é\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			cw := contentline.NewWriter(&b)
+			cw.Line(tt.line)
+			if err := cw.Flush(); err != nil {
+				t.Fatalf("Flush: %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("wrote %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
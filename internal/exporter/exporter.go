@@ -1,296 +1,433 @@
-package exporter
-
-import (
-	"encoding/csv"
-	"fmt"
-	"io"
-	"os"
-	"strconv"
-
-	"github.com/pocketbase/pocketbase"
-	"github.com/pocketbase/pocketbase/core"
-)
-
-// ExportAll exports all tables to CSV files in the specified directory
-func ExportAll(app *pocketbase.PocketBase, outputDir string) error {
-	// Create output directory if it doesn't exist
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
-	}
-
-	// Export each table
-	steps := []struct {
-		name     string
-		filename string
-		fn       func(*pocketbase.PocketBase, string) error
-	}{
-		{"companies", "reverse-ats - Companies.csv", ExportCompanies},
-		{"roles", "reverse-ats - Roles.csv", ExportRoles},
-		{"contacts", "reverse-ats - Contacts.csv", ExportContacts},
-		{"interviews", "reverse-ats - Interviews.csv", ExportInterviews},
-		{"interviews-contacts", "reverse-ats - InterviewsContacts.csv", ExportInterviewsContacts},
-	}
-
-	for _, step := range steps {
-		filepath := outputDir + "/" + step.filename
-		fmt.Printf("Exporting %s to %s...\n", step.name, filepath)
-		if err := step.fn(app, filepath); err != nil {
-			return fmt.Errorf("failed to export %s: %w", step.name, err)
-		}
-	}
-
-	fmt.Println("\n✅ All data exported successfully!")
-	return nil
-}
-
-// ExportCompanies exports companies table to CSV
-func ExportCompanies(app *pocketbase.PocketBase, filepath string) error {
-	records, err := app.FindRecordsByFilter("companies", "", "created", -1, 0)
-	if err != nil {
-		return err
-	}
-
-	file, err := os.Create(filepath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	return WriteCompaniesCSV(file, records)
-}
-
-// ExportRoles exports roles table to CSV
-func ExportRoles(app *pocketbase.PocketBase, filepath string) error {
-	records, err := app.FindRecordsByFilter("roles", "", "created", -1, 0)
-	if err != nil {
-		return err
-	}
-
-	file, err := os.Create(filepath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	return WriteRolesCSV(file, records)
-}
-
-// ExportContacts exports contacts table to CSV
-func ExportContacts(app *pocketbase.PocketBase, filepath string) error {
-	records, err := app.FindRecordsByFilter("contacts", "", "created", -1, 0)
-	if err != nil {
-		return err
-	}
-
-	file, err := os.Create(filepath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	return WriteContactsCSV(file, records)
-}
-
-// ExportInterviews exports interviews table to CSV
-func ExportInterviews(app *pocketbase.PocketBase, filepath string) error {
-	records, err := app.FindRecordsByFilter("interviews", "", "created", -1, 0)
-	if err != nil {
-		return err
-	}
-
-	file, err := os.Create(filepath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	return WriteInterviewsCSV(file, records)
-}
-
-// ExportInterviewsContacts exports interview-contact relationships to CSV
-func ExportInterviewsContacts(app *pocketbase.PocketBase, filepath string) error {
-	// Fetch all interviews with contacts field
-	records, err := app.FindRecordsByFilter("interviews", "", "created", -1, 0)
-	if err != nil {
-		return err
-	}
-
-	file, err := os.Create(filepath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	return WriteInterviewsContactsCSV(file, records)
-}
-
-// WriteCompaniesCSV writes companies data to CSV writer
-func WriteCompaniesCSV(writer io.Writer, records []*core.Record) error {
-	csvWriter := csv.NewWriter(writer)
-	defer csvWriter.Flush()
-
-	// Write header
-	csvWriter.Write([]string{"companyID", "name", "description", "url", "linkedin", "hqCity", "hqState"})
-
-	// Write data
-	for _, record := range records {
-		csvWriter.Write([]string{
-			record.Id,
-			record.GetString("name"),
-			emptyToNull(record.GetString("description")),
-			emptyToNull(record.GetString("url")),
-			emptyToNull(record.GetString("linkedin")),
-			emptyToNull(record.GetString("hq_city")),
-			emptyToNull(record.GetString("hq_state")),
-		})
-	}
-
-	return csvWriter.Error()
-}
-
-// WriteRolesCSV writes roles data to CSV writer
-func WriteRolesCSV(writer io.Writer, records []*core.Record) error {
-	csvWriter := csv.NewWriter(writer)
-	defer csvWriter.Flush()
-
-	// Write header
-	csvWriter.Write([]string{
-		"roleID", "companyID", "name", "url", "description", "coverLetter",
-		"applicationLocation", "appliedDate", "closedDate", "postedRangeMin",
-		"postedRangeMax", "equity", "workCity", "workState", "location",
-		"status", "discovery", "referral", "notes",
-	})
-
-	// Write data
-	for _, record := range records {
-		csvWriter.Write([]string{
-			record.Id,
-			record.GetString("company"),
-			record.GetString("name"),
-			emptyToNull(record.GetString("url")),
-			emptyToNull(record.GetString("description")),
-			emptyToNull(record.GetString("cover_letter")),
-			emptyToNull(record.GetString("application_location")),
-			emptyToNull(record.GetString("applied_date")),
-			emptyToNull(record.GetString("closed_date")),
-			int64ToString(record.GetInt("posted_range_min")),
-			int64ToString(record.GetInt("posted_range_max")),
-			boolToString(record.GetBool("equity")),
-			emptyToNull(record.GetString("work_city")),
-			emptyToNull(record.GetString("work_state")),
-			emptyToNull(record.GetString("location")),
-			emptyToNull(record.GetString("status")),
-			emptyToNull(record.GetString("discovery")),
-			boolToString(record.GetBool("referral")),
-			emptyToNull(record.GetString("notes")),
-		})
-	}
-
-	return csvWriter.Error()
-}
-
-// WriteContactsCSV writes contacts data to CSV writer
-func WriteContactsCSV(writer io.Writer, records []*core.Record) error {
-	csvWriter := csv.NewWriter(writer)
-	defer csvWriter.Flush()
-
-	// Write header
-	csvWriter.Write([]string{
-		"contactID", "companyID", "firstName", "lastName", "role",
-		"email", "phone", "linkedin", "notes",
-	})
-
-	// Write data
-	for _, record := range records {
-		csvWriter.Write([]string{
-			record.Id,
-			record.GetString("company"),
-			record.GetString("first_name"),
-			record.GetString("last_name"),
-			emptyToNull(record.GetString("role")),
-			emptyToNull(record.GetString("email")),
-			emptyToNull(record.GetString("phone")),
-			emptyToNull(record.GetString("linkedin")),
-			emptyToNull(record.GetString("notes")),
-		})
-	}
-
-	return csvWriter.Error()
-}
-
-// WriteInterviewsCSV writes interviews data to CSV writer
-func WriteInterviewsCSV(writer io.Writer, records []*core.Record) error {
-	csvWriter := csv.NewWriter(writer)
-	defer csvWriter.Flush()
-
-	// Write header
-	csvWriter.Write([]string{
-		"interviewID", "roleID", "date", "start", "end", "notes", "type",
-	})
-
-	// Write data
-	for _, record := range records {
-		csvWriter.Write([]string{
-			record.Id,
-			record.GetString("role"),
-			record.GetString("date"),
-			record.GetString("start"),
-			record.GetString("end"),
-			emptyToNull(record.GetString("notes")),
-			record.GetString("type"),
-		})
-	}
-
-	return csvWriter.Error()
-}
-
-// WriteInterviewsContactsCSV writes interview-contact relationships to CSV writer
-func WriteInterviewsContactsCSV(writer io.Writer, records []*core.Record) error {
-	csvWriter := csv.NewWriter(writer)
-	defer csvWriter.Flush()
-
-	// Write header
-	csvWriter.Write([]string{
-		"interviewsContactId", "interviewId", "contactId",
-	})
-
-	// Write data - flatten many-to-many relationships
-	linkID := 1
-	for _, record := range records {
-		interviewID := record.Id
-		contacts := record.GetStringSlice("contacts")
-
-		for _, contactID := range contacts {
-			csvWriter.Write([]string{
-				strconv.Itoa(linkID),
-				interviewID,
-				contactID,
-			})
-			linkID++
-		}
-	}
-
-	return csvWriter.Error()
-}
-
-// Helper functions
-func emptyToNull(s string) string {
-	if s == "" {
-		return "NULL"
-	}
-	return s
-}
-
-func int64ToString(val int) string {
-	if val == 0 {
-		return "NULL"
-	}
-	return strconv.Itoa(val)
-}
-
-func boolToString(val bool) string {
-	if val {
-		return "true"
-	}
-	return "false"
-}
+package exporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+// ExportAll exports all tables to CSV files in the specified directory
+func ExportAll(app *pocketbase.PocketBase, outputDir string) error {
+	return ExportToDir(app, outputDir, Filter{})
+}
+
+// ExportToDir exports the CSV files a filter selects, and their uploaded
+// files, to the specified directory
+func ExportToDir(app core.App, outputDir string, filter Filter) error {
+	// Create output directory if it doesn't exist
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	// Files from an earlier export would hand over more than the filter selects
+	if !filter.IsZero() {
+		if err := removeStaleExport(outputDir, filter); err != nil {
+			return err
+		}
+	}
+
+	err := Export(app, filter, func(name string) (io.WriteCloser, error) {
+		path := filepath.Join(outputDir, filepath.FromSlash(name))
+		if !strings.HasPrefix(name, FilesDir+"/") {
+			fmt.Printf("Exporting %s...\n", path)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+		return os.Create(path)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\n✅ All data exported successfully!")
+	return nil
+}
+
+// removeStaleExport removes the CSV files a filter doesn't select and the
+// uploaded files from an export directory. A JSON export is left alone, as
+// it may be the only backup, but is pointed out.
+func removeStaleExport(outputDir string, filter Filter) error {
+	for _, file := range csvFiles {
+		path := filepath.Join(outputDir, file.filename)
+		if filter.includes(file.name) {
+			continue
+		}
+		if err := os.Remove(path); err == nil {
+			fmt.Printf("Removed %s from an earlier export\n", path)
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+
+	if err := os.RemoveAll(filepath.Join(outputDir, FilesDir)); err != nil {
+		return fmt.Errorf("failed to remove earlier exported files: %w", err)
+	}
+
+	jsonPath := filepath.Join(outputDir, JSONFilename)
+	if _, err := os.Stat(jsonPath); err == nil {
+		fmt.Printf("Warning: %s holds a full JSON export; move it away before sharing %s\n", jsonPath, outputDir)
+	}
+
+	return nil
+}
+
+// exportFilesToDir copies uploaded files to the files folder of an export directory
+func exportFilesToDir(app core.App, outputDir string) error {
+	fmt.Printf("Exporting files to %s...\n", outputDir+"/"+FilesDir)
+	err := WriteFiles(app, func(name string) (io.WriteCloser, error) {
+		path := filepath.Join(outputDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+		return os.Create(path)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export files: %w", err)
+	}
+	return nil
+}
+
+// WriteCompaniesCSV writes companies data to CSV writer
+func WriteCompaniesCSV(writer io.Writer, records []*core.Record) error {
+	csvWriter := csv.NewWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	csvWriter.Write([]string{"companyID", "name", "description", "url", "linkedin", "hqCity", "hqState", "tags"})
+
+	// Write data
+	for _, record := range records {
+		csvWriter.Write([]string{
+			record.Id,
+			emptyToNull(record.GetString("name")),
+			emptyToNull(record.GetString("description")),
+			emptyToNull(record.GetString("url")),
+			emptyToNull(record.GetString("linkedin")),
+			emptyToNull(record.GetString("hq_city")),
+			emptyToNull(record.GetString("hq_state")),
+			tagNames(record),
+		})
+	}
+
+	return csvWriter.Error()
+}
+
+// WriteRolesCSV writes roles data to CSV writer
+func WriteRolesCSV(writer io.Writer, records []*core.Record) error {
+	csvWriter := csv.NewWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	csvWriter.Write([]string{
+		"roleID", "companyID", "name", "url", "description", "coverLetter",
+		"applicationLocation", "appliedDate", "closedDate", "postedRangeMin",
+		"postedRangeMax", "equity", "workCity", "workState", "location",
+		"status", "discovery", "referral", "notes",
+		"resumeDocumentID", "coverLetterDocumentID", "resumeFile", "coverLetterFile",
+		"tags",
+	})
+
+	// Write data
+	for _, record := range records {
+		csvWriter.Write([]string{
+			record.Id,
+			record.GetString("company"),
+			emptyToNull(record.GetString("name")),
+			emptyToNull(record.GetString("url")),
+			emptyToNull(record.GetString("description")),
+			emptyToNull(record.GetString("cover_letter")),
+			emptyToNull(record.GetString("application_location")),
+			dateToString(record, "applied_date"),
+			dateToString(record, "closed_date"),
+			numberToString(record.GetFloat("posted_range_min")),
+			numberToString(record.GetFloat("posted_range_max")),
+			boolToString(record.GetBool("equity")),
+			emptyToNull(record.GetString("work_city")),
+			emptyToNull(record.GetString("work_state")),
+			emptyToNull(record.GetString("location")),
+			emptyToNull(record.GetString("status")),
+			emptyToNull(record.GetString("discovery")),
+			boolToString(record.GetBool("referral")),
+			emptyToNull(record.GetString("notes")),
+			emptyToNull(record.GetString("resume_document")),
+			emptyToNull(record.GetString("cover_letter_document")),
+			emptyToNull(record.GetString("resume_file")),
+			emptyToNull(record.GetString("cover_letter_file")),
+			tagNames(record),
+		})
+	}
+
+	return csvWriter.Error()
+}
+
+// WriteContactsCSV writes contacts data to CSV writer
+func WriteContactsCSV(writer io.Writer, records []*core.Record) error {
+	csvWriter := csv.NewWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	csvWriter.Write([]string{
+		"contactID", "companyID", "firstName", "lastName", "role",
+		"email", "phone", "linkedin", "notes", "tags",
+	})
+
+	// Write data
+	for _, record := range records {
+		csvWriter.Write([]string{
+			record.Id,
+			record.GetString("company"),
+			emptyToNull(record.GetString("first_name")),
+			emptyToNull(record.GetString("last_name")),
+			emptyToNull(record.GetString("role")),
+			emptyToNull(record.GetString("email")),
+			emptyToNull(record.GetString("phone")),
+			emptyToNull(record.GetString("linkedin")),
+			emptyToNull(record.GetString("notes")),
+			tagNames(record),
+		})
+	}
+
+	return csvWriter.Error()
+}
+
+// WriteInterviewsCSV writes interviews data to CSV writer
+func WriteInterviewsCSV(writer io.Writer, records []*core.Record) error {
+	csvWriter := csv.NewWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	csvWriter.Write([]string{
+		"interviewID", "roleID", "date", "start", "end", "notes", "type", "tags",
+	})
+
+	// Write data
+	for _, record := range records {
+		csvWriter.Write([]string{
+			record.Id,
+			record.GetString("role"),
+			dateToString(record, "date"),
+			emptyToNull(record.GetString("start")),
+			emptyToNull(record.GetString("end")),
+			emptyToNull(record.GetString("notes")),
+			emptyToNull(record.GetString("type")),
+			tagNames(record),
+		})
+	}
+
+	return csvWriter.Error()
+}
+
+// WriteInterviewsContactsCSV writes interview-contact relationships to CSV writer
+func WriteInterviewsContactsCSV(writer io.Writer, records []*core.Record) error {
+	csvWriter := csv.NewWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	csvWriter.Write([]string{
+		"interviewsContactId", "interviewId", "contactId",
+	})
+
+	// Write data - flatten many-to-many relationships
+	linkID := 1
+	for _, record := range records {
+		interviewID := record.Id
+		contacts := record.GetStringSlice("contacts")
+
+		for _, contactID := range contacts {
+			csvWriter.Write([]string{
+				strconv.Itoa(linkID),
+				interviewID,
+				contactID,
+			})
+			linkID++
+		}
+	}
+
+	return csvWriter.Error()
+}
+
+// WriteDocumentsCSV writes document library data to CSV writer
+func WriteDocumentsCSV(writer io.Writer, records []*core.Record) error {
+	csvWriter := csv.NewWriter(writer)
+	defer csvWriter.Flush()
+
+	// Write header
+	csvWriter.Write([]string{
+		"documentID", "label", "kind", "file", "notes",
+	})
+
+	// Write data
+	for _, record := range records {
+		csvWriter.Write([]string{
+			record.Id,
+			emptyToNull(record.GetString("label")),
+			emptyToNull(record.GetString("kind")),
+			emptyToNull(record.GetString("file")),
+			emptyToNull(record.GetString("notes")),
+		})
+	}
+
+	return csvWriter.Error()
+}
+
+// FilesDir is the folder uploaded files are exported to, laid out as
+// files/<collection>/<recordID>/<filename>
+const FilesDir = "files"
+
+// fileFields lists the file fields exported per collection
+var fileFields = []struct {
+	collection string
+	fields     []string
+}{
+	{"roles", []string{"resume_file", "cover_letter_file"}},
+	{"documents", []string{"file"}},
+}
+
+// WriteFiles copies every uploaded file to a writer obtained from create,
+// which receives the slash-separated path of the file inside the export
+func WriteFiles(app core.App, create func(name string) (io.WriteCloser, error)) error {
+	selected := make(map[string][]*core.Record)
+	for _, source := range fileFields {
+		records, err := app.FindRecordsByFilter(source.collection, "", "id", -1, 0)
+		if err != nil {
+			return err
+		}
+		selected[source.collection] = records
+	}
+
+	return writeFiles(app, selected, create)
+}
+
+// writeFiles copies the uploaded files of the given records, by collection name
+func writeFiles(app core.App, selected map[string][]*core.Record, create func(name string) (io.WriteCloser, error)) error {
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		return err
+	}
+	defer fsys.Close()
+
+	for _, source := range fileFields {
+		for _, record := range selected[source.collection] {
+			for _, field := range source.fields {
+				filename := record.GetString(field)
+				if filename == "" {
+					continue
+				}
+
+				name := path.Join(FilesDir, source.collection, record.Id, filename)
+				if err := copyFile(fsys, record.BaseFilesPath()+"/"+filename, name, create); err != nil {
+					return fmt.Errorf("failed to export %s: %w", name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// copyFile copies a single stored file to the writer returned by create
+func copyFile(fsys *filesystem.System, key, name string, create func(name string) (io.WriteCloser, error)) error {
+	reader, err := fsys.GetReader(key)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	writer, err := create(name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(writer, reader); err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+// ExpandTags loads the tags of records so their names can be exported
+func ExpandTags(app core.App, records []*core.Record) error {
+	// Not every exported collection is taggable
+	if len(records) == 0 || records[0].Collection().Fields.GetByName("tags") == nil {
+		return nil
+	}
+
+	if errs := app.ExpandRecords(records, []string{"tags"}, nil); len(errs) > 0 {
+		return fmt.Errorf("failed to expand tags: %v", errs)
+	}
+	return nil
+}
+
+// tagNames returns the comma-separated names of a record's expanded tags
+func tagNames(record *core.Record) string {
+	var names []string
+	for _, tag := range record.ExpandedAll("tags") {
+		names = append(names, tag.GetString("name"))
+	}
+	return emptyToNull(strings.Join(names, ","))
+}
+
+// NullValue marks an empty value in CSV files
+const NullValue = "NULL"
+
+// Helper functions
+
+// emptyToNull writes an empty value as NULL. Text that is literally NULL,
+// after any backslashes, gets one more backslash so it reads back as text.
+func emptyToNull(s string) string {
+	if s == "" {
+		return NullValue
+	}
+	if strings.TrimLeft(s, `\`) == NullValue {
+		return `\` + s
+	}
+	return s
+}
+
+// NullToEmpty reads a value written by emptyToNull
+func NullToEmpty(s string) string {
+	if s == NullValue {
+		return ""
+	}
+	if strings.TrimLeft(s, `\`) == NullValue {
+		return s[1:]
+	}
+	return s
+}
+
+// numberToString writes a number in full, so zero and fractions survive
+func numberToString(val float64) string {
+	return strconv.FormatFloat(val, 'f', -1, 64)
+}
+
+// dateToString writes a date as YYYY-MM-DD, keeping the time only when it isn't midnight UTC
+func dateToString(record *core.Record, field string) string {
+	date := record.GetDateTime(field)
+	if date.IsZero() {
+		return NullValue
+	}
+
+	t := date.Time()
+	if t.Equal(t.Truncate(24 * time.Hour)) {
+		return t.Format("2006-01-02")
+	}
+	return date.String()
+}
+
+func boolToString(val bool) string {
+	if val {
+		return "true"
+	}
+	return "false"
+}
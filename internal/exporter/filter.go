@@ -0,0 +1,261 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/util"
+)
+
+// csvFiles lists the CSV files of an export in import order. The name is
+// what selects the file in a Filter.
+var csvFiles = []struct {
+	name       string
+	filename   string
+	collection string
+	write      func(io.Writer, []*core.Record) error
+}{
+	{"companies", "reverse-ats - Companies.csv", util.CollectionCompanies, WriteCompaniesCSV},
+	{"roles", "reverse-ats - Roles.csv", util.CollectionRoles, WriteRolesCSV},
+	{"contacts", "reverse-ats - Contacts.csv", util.CollectionContacts, WriteContactsCSV},
+	{"interviews", "reverse-ats - Interviews.csv", util.CollectionInterviews, WriteInterviewsCSV},
+	{"interviews", "reverse-ats - InterviewsContacts.csv", util.CollectionInterviews, WriteInterviewsContactsCSV},
+	{"documents", "reverse-ats - Documents.csv", util.CollectionDocuments, WriteDocumentsCSV},
+}
+
+// CSVCollections are the names that select the files of a CSV export
+var CSVCollections = []string{"companies", "roles", "contacts", "interviews", "documents"}
+
+// Filter narrows a CSV export down to a subset of the data. When it selects
+// roles, the other collections only hold what those roles lead to: their
+// companies, the contacts at those companies, their interviews and the
+// documents they were sent with. The zero Filter exports everything.
+type Filter struct {
+	Collections []string  // Files to export, from CSVCollections; all when empty
+	AppliedFrom time.Time // Roles applied on or after this day
+	AppliedTo   time.Time // Roles applied on or before this day
+	Statuses    []string  // Role statuses to export
+	IDs         []string  // Records to export by id, like the rows picked in a list
+}
+
+// ParseFilter builds a filter from the values of the export query params or
+// flags. Lists may be repeated or comma-separated, dates are YYYY-MM-DD, and
+// empty values don't filter.
+func ParseFilter(collections, statuses []string, appliedFrom, appliedTo string) (Filter, error) {
+	var filter Filter
+
+	for _, name := range splitList(collections) {
+		name = strings.ToLower(name)
+		if !slices.Contains(CSVCollections, name) {
+			return filter, fmt.Errorf("unknown collection %q, expected one of %s", name, strings.Join(CSVCollections, ", "))
+		}
+		if !slices.Contains(filter.Collections, name) {
+			filter.Collections = append(filter.Collections, name)
+		}
+	}
+
+	for _, status := range splitList(statuses) {
+		status = models.NormalizeRoleStatus(strings.ToUpper(status))
+		if !models.IsValidRoleStatus(status) {
+			return filter, fmt.Errorf("unknown status %q", status)
+		}
+		if !slices.Contains(filter.Statuses, status) {
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	var err error
+	if filter.AppliedFrom, err = parseFilterDate(appliedFrom); err != nil {
+		return filter, fmt.Errorf("invalid applied from date: %w", err)
+	}
+	if filter.AppliedTo, err = parseFilterDate(appliedTo); err != nil {
+		return filter, fmt.Errorf("invalid applied to date: %w", err)
+	}
+	if !filter.AppliedFrom.IsZero() && !filter.AppliedTo.IsZero() && filter.AppliedTo.Before(filter.AppliedFrom) {
+		return filter, fmt.Errorf("applied to date is before the applied from date")
+	}
+
+	return filter, nil
+}
+
+// IsZero reports whether the filter exports everything
+func (f Filter) IsZero() bool {
+	return len(f.Collections) == 0 && len(f.IDs) == 0 && !f.filtersRoles()
+}
+
+// includes reports whether the filter exports the files of a collection name
+func (f Filter) includes(name string) bool {
+	return len(f.Collections) == 0 || slices.Contains(f.Collections, name)
+}
+
+// filtersRoles reports whether the filter narrows down the roles
+func (f Filter) filtersRoles() bool {
+	return !f.AppliedFrom.IsZero() || !f.AppliedTo.IsZero() || len(f.Statuses) > 0
+}
+
+// roleConditions returns the conditions of the status and applied date filters
+func (f Filter) roleConditions() []dbx.Expression {
+	var conditions []dbx.Expression
+	if len(f.Statuses) > 0 {
+		conditions = append(conditions, dbx.In("status", toAny(f.Statuses)...))
+	}
+
+	if f.AppliedFrom.IsZero() && f.AppliedTo.IsZero() {
+		return conditions
+	}
+	conditions = append(conditions, dbx.NewExp("applied_date != ''"))
+	if !f.AppliedFrom.IsZero() {
+		conditions = append(conditions, dbx.NewExp("applied_date >= {:appliedFrom}", dbx.Params{
+			"appliedFrom": f.AppliedFrom.UTC().Format(types.DefaultDateLayout),
+		}))
+	}
+	if !f.AppliedTo.IsZero() {
+		conditions = append(conditions, dbx.NewExp("applied_date < {:appliedTo}", dbx.Params{
+			"appliedTo": f.AppliedTo.AddDate(0, 0, 1).UTC().Format(types.DefaultDateLayout),
+		}))
+	}
+	return conditions
+}
+
+// Export writes the CSV files a filter selects, and the uploaded files of the
+// exported roles and documents, each to the writer obtained from create as in
+// WriteFiles. Nothing is kept in memory beyond the records themselves.
+func Export(app core.App, filter Filter, create func(name string) (io.WriteCloser, error)) error {
+	selected, err := selectRecords(app, filter)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range csvFiles {
+		if !filter.includes(file.name) {
+			continue
+		}
+
+		records := selected[file.collection]
+		if err := ExpandTags(app, records); err != nil {
+			return fmt.Errorf("failed to export %s: %w", file.filename, err)
+		}
+
+		writer, err := create(file.filename)
+		if err != nil {
+			return fmt.Errorf("failed to export %s: %w", file.filename, err)
+		}
+		if err := file.write(writer, records); err != nil {
+			writer.Close()
+			return fmt.Errorf("failed to export %s: %w", file.filename, err)
+		}
+		if err := writer.Close(); err != nil {
+			return fmt.Errorf("failed to export %s: %w", file.filename, err)
+		}
+	}
+
+	// Only the files of exported records go along
+	for name := range selected {
+		if !filter.includes(name) {
+			delete(selected, name)
+		}
+	}
+	return writeFiles(app, selected, create)
+}
+
+// selectRecords returns the records of each exported collection by collection
+// name. Collections that aren't exported are left out, and the queries only
+// load the records the filter selects.
+func selectRecords(app core.App, filter Filter) (map[string][]*core.Record, error) {
+	conditions := make(map[string][]dbx.Expression)
+	if len(filter.IDs) > 0 {
+		for _, file := range csvFiles {
+			conditions[file.collection] = []dbx.Expression{dbx.In("id", toAny(filter.IDs)...)}
+		}
+	}
+
+	selected := make(map[string][]*core.Record)
+	if filter.filtersRoles() {
+		roles, err := findRecords(app, util.CollectionRoles, append(conditions[util.CollectionRoles], filter.roleConditions()...))
+		if err != nil {
+			return nil, err
+		}
+		selected[util.CollectionRoles] = roles
+
+		// The other collections only hold what the roles lead to
+		var roleIDs, companyIDs, documentIDs []any
+		for _, record := range roles {
+			roleIDs = append(roleIDs, record.Id)
+			companyIDs = append(companyIDs, record.GetString("company"))
+			for _, field := range []string{"resume_document", "cover_letter_document"} {
+				if id := record.GetString(field); id != "" {
+					documentIDs = append(documentIDs, id)
+				}
+			}
+		}
+		conditions[util.CollectionCompanies] = append(conditions[util.CollectionCompanies], dbx.In("id", companyIDs...))
+		conditions[util.CollectionContacts] = append(conditions[util.CollectionContacts], dbx.In("company", companyIDs...))
+		conditions[util.CollectionInterviews] = append(conditions[util.CollectionInterviews], dbx.In("role", roleIDs...))
+		conditions[util.CollectionDocuments] = append(conditions[util.CollectionDocuments], dbx.In("id", documentIDs...))
+	}
+
+	for _, file := range csvFiles {
+		if _, ok := selected[file.collection]; ok || !filter.includes(file.name) {
+			continue
+		}
+		records, err := findRecords(app, file.collection, conditions[file.collection])
+		if err != nil {
+			return nil, err
+		}
+		selected[file.collection] = records
+	}
+
+	return selected, nil
+}
+
+// findRecords returns the records of a collection that meet all the conditions, by id
+func findRecords(app core.App, collection string, conditions []dbx.Expression) ([]*core.Record, error) {
+	var records []*core.Record
+	query := app.RecordQuery(collection).OrderBy("id")
+	for _, condition := range conditions {
+		query.AndWhere(condition)
+	}
+	if err := query.All(&records); err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", collection, err)
+	}
+	return records, nil
+}
+
+// toAny converts values to the arguments of dbx.In
+func toAny(values []string) []any {
+	args := make([]any, len(values))
+	for i, value := range values {
+		args[i] = value
+	}
+	return args
+}
+
+// splitList splits repeated, comma-separated values into their trimmed items
+func splitList(values []string) []string {
+	var items []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// parseFilterDate reads a YYYY-MM-DD date, the empty string being no date
+func parseFilterDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
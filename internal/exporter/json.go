@@ -0,0 +1,130 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+
+	"reverse-ats/internal/util"
+)
+
+// JSONSchemaVersion is the version of the JSON export format.
+// Bump it when a change would stop older importers from reading the files.
+const JSONSchemaVersion = 1
+
+// JSONFilename is the name of the JSON export file
+const JSONFilename = "reverse-ats.json"
+
+// JSONCollections lists the collections of a JSON export, each one after the
+// collections it references so they can be restored in order. The webhook
+// delivery log is left out: it is pruned after 30 days and isn't data to restore.
+var JSONCollections = []string{
+	util.CollectionTags,
+	util.CollectionDocuments,
+	util.CollectionCompanies,
+	util.CollectionRoles,
+	util.CollectionContacts,
+	util.CollectionInterviews,
+	util.CollectionOffers,
+	util.CollectionTasks,
+	util.CollectionRoleStatusEvents,
+	util.CollectionWebhooks,
+}
+
+// JSONDocument is a full-fidelity export of all data. Each record holds every
+// field of its collection by name, with relations as record IDs and file
+// fields as the names of the files exported to the files folder.
+type JSONDocument struct {
+	SchemaVersion int                         `json:"schemaVersion"`
+	ExportedAt    time.Time                   `json:"exportedAt"`
+	Collections   map[string][]map[string]any `json:"collections"`
+}
+
+// ExportJSON exports all collections to a JSON file, and uploaded files next to it
+func ExportJSON(app *pocketbase.PocketBase, outputDir string) error {
+	return ExportJSONFile(app, filepath.Join(outputDir, JSONFilename))
+}
+
+// ExportJSONFile exports all collections to the JSON file at path, and
+// uploaded files to the files folder next to it
+func ExportJSONFile(app core.App, path string) error {
+	outputDir := filepath.Dir(path)
+
+	// Create output directory if it doesn't exist
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	fmt.Printf("Exporting all collections to %s...\n", path)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := WriteJSON(app, file); err != nil {
+		return fmt.Errorf("failed to export JSON: %w", err)
+	}
+
+	if err := exportFilesToDir(app, outputDir); err != nil {
+		return err
+	}
+
+	fmt.Println("\n✅ All data exported successfully!")
+	return nil
+}
+
+// WriteJSON writes all collections as a JSONDocument
+func WriteJSON(app core.App, writer io.Writer) error {
+	doc := JSONDocument{
+		SchemaVersion: JSONSchemaVersion,
+		ExportedAt:    time.Now().UTC(),
+		Collections:   make(map[string][]map[string]any, len(JSONCollections)),
+	}
+
+	for _, name := range JSONCollections {
+		records, err := app.FindRecordsByFilter(name, "", "id", -1, 0)
+		if err != nil {
+			return fmt.Errorf("failed to query %s: %w", name, err)
+		}
+
+		rows := make([]map[string]any, len(records))
+		for i, record := range records {
+			rows[i] = recordFields(record)
+		}
+		doc.Collections[name] = rows
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// jsonSecretFields are left out of JSON exports, by collection, so a backup
+// doesn't hold the keys that sign webhook deliveries
+var jsonSecretFields = map[string][]string{
+	util.CollectionWebhooks: {"secret"},
+}
+
+// recordFields returns the value of every field of a record by name, but
+// for the secret ones
+func recordFields(record *core.Record) map[string]any {
+	secret := jsonSecretFields[record.Collection().Name]
+
+	fields := make(map[string]any)
+	for _, field := range record.Collection().Fields {
+		if slices.Contains(secret, field.GetName()) {
+			continue
+		}
+		fields[field.GetName()] = record.Get(field.GetName())
+	}
+	return fields
+}
@@ -0,0 +1,69 @@
+package exporter
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// nopWriteCloser adapts a zip entry writer, which needs no closing
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// WriteZip writes the export a filter selects as a zip, the CSV files at the
+// top and uploaded files in the files folder. start, when set, is called
+// right before the first entry is written, so an HTTP response can still
+// report an error that comes up earlier.
+func WriteZip(app core.App, writer io.Writer, filter Filter, start func()) error {
+	zipWriter := zip.NewWriter(writer)
+	started := false
+
+	err := Export(app, filter, func(name string) (io.WriteCloser, error) {
+		if !started && start != nil {
+			start()
+		}
+		started = true
+
+		entry, err := zipWriter.Create(name)
+		if err != nil {
+			return nil, err
+		}
+		return nopWriteCloser{entry}, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return zipWriter.Close()
+}
+
+// ExportZipFile writes the export a filter selects to a zip file at path
+func ExportZipFile(app core.App, path string, filter Filter) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	fmt.Printf("Exporting to %s...\n", path)
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteZip(app, file, filter, nil); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	fmt.Println("\n✅ All data exported successfully!")
+	return nil
+}
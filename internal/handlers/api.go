@@ -0,0 +1,400 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/search"
+
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/openapi"
+	"reverse-ats/internal/util"
+)
+
+const (
+	apiDefaultPerPage = 50
+	apiMaxPerPage     = 500
+	maxAPIBodySize    = 1 << 20
+)
+
+// APIHandler serves the versioned JSON API under /api/v1
+type APIHandler struct {
+	app   *pocketbase.PocketBase
+	token string // Required bearer token; the API is disabled without one
+}
+
+func NewAPIHandler(app *pocketbase.PocketBase, token string) *APIHandler {
+	return &APIHandler{app: app, token: token}
+}
+
+// Authorize checks the bearer token of an API request
+func (h *APIHandler) Authorize(w http.ResponseWriter, r *http.Request) error {
+	if h.token == "" {
+		return apiError(w, http.StatusNotFound, "The API is disabled. Set "+util.EnvAPIToken+" to enable it.", nil)
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="reverse-ats"`)
+		return apiError(w, http.StatusUnauthorized, "Invalid or missing API token", nil)
+	}
+
+	return nil
+}
+
+// apiResource describes how the API lists, reads and writes the records of a
+// collection as models of type T
+type apiResource[T any] struct {
+	collection string
+	name       string            // Singular name for messages
+	fields     map[string]string // Body field names by record field, for validation errors
+	sorts      []string          // Record fields a list can be sorted by
+	sort       string            // Default sort
+	filters    []apiFilter
+
+	// convert turns records into models, expanding the relations it needs
+	convert func(app core.App, records []*core.Record) ([]T, error)
+
+	// save validates a model, applies it to a new or existing record and saves
+	// the record. It runs in a transaction.
+	save func(app core.App, record *core.Record, item T) error
+}
+
+// notFound is the message of a missing record
+func (res apiResource[T]) notFound() string {
+	return strings.ToUpper(res.name[:1]) + res.name[1:] + " not found"
+}
+
+// apiFilter is a query param that narrows down a list. Comma-separated or
+// repeated values match any of them; different params must all match.
+type apiFilter struct {
+	param       string
+	description string
+	schema      *openapi.Schema // Schema of one value, for the OpenAPI document
+	// build returns the filter expression matching one value of the param,
+	// with the placeholder standing in for the value it binds
+	build func(value, placeholder string) (string, any, error)
+}
+
+// equalFilter matches records whose field is the value
+func equalFilter(param, field, description string) apiFilter {
+	return apiFilter{param, description, &openapi.Schema{Type: "string"}, func(value, placeholder string) (string, any, error) {
+		return field + " = " + placeholder, value, nil
+	}}
+}
+
+// anyFilter matches records whose multiple relation or nested field holds the value
+func anyFilter(param, field, description string) apiFilter {
+	return apiFilter{param, description, &openapi.Schema{Type: "string"}, func(value, placeholder string) (string, any, error) {
+		return field + " ?= " + placeholder, value, nil
+	}}
+}
+
+// enumFilter matches records whose field is one of values, given in any
+// spelling normalize accepts
+func enumFilter(param, field, description string, values []string, normalize func(string) string) apiFilter {
+	return apiFilter{param, description, &openapi.Schema{Type: "string", Enum: values}, func(value, placeholder string) (string, any, error) {
+		normalized := normalize(value)
+		if !slices.Contains(values, normalized) {
+			return "", nil, fmt.Errorf("unknown %s %q", param, value)
+		}
+		return field + " = " + placeholder, normalized, nil
+	}}
+}
+
+// boolFilter matches records whose field is true or false
+func boolFilter(param, field, description string) apiFilter {
+	return apiFilter{param, description, &openapi.Schema{Type: "boolean"}, func(value, placeholder string) (string, any, error) {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", nil, fmt.Errorf("%s must be true or false", param)
+		}
+		return fmt.Sprintf("%s = %t", field, b), nil, nil
+	}}
+}
+
+// dateFilter matches records whose date field is on or after (from) or on or
+// before (to) a YYYY-MM-DD day
+func dateFilter(param, field, description string, from bool) apiFilter {
+	return apiFilter{param, description, &openapi.Schema{Type: "string", Format: "date"}, func(value, placeholder string) (string, any, error) {
+		day, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return "", nil, fmt.Errorf("%s must be a YYYY-MM-DD date", param)
+		}
+		if from {
+			return field + " >= " + placeholder, day.Format("2006-01-02"), nil
+		}
+		return field + " < " + placeholder, day.AddDate(0, 0, 1).Format("2006-01-02"), nil
+	}}
+}
+
+// apiInvalid is an invalid field of a request body
+type apiInvalid struct {
+	status  int
+	field   string
+	message string
+}
+
+func (e *apiInvalid) Error() string {
+	return e.field + ": " + e.message
+}
+
+// invalidField reports a bad value of a body field
+func invalidField(field, format string, args ...any) error {
+	return &apiInvalid{status: http.StatusBadRequest, field: field, message: fmt.Sprintf(format, args...)}
+}
+
+// writeJSON sends a JSON response
+func writeJSON(w http.ResponseWriter, status int, body any) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(body)
+}
+
+// apiError sends a JSON error and returns err, or the message as an error when err is nil
+func apiError(w http.ResponseWriter, status int, message string, err error) error {
+	return apiFieldsError(w, status, message, nil, err)
+}
+
+// apiFieldsError sends a JSON error listing the problems with body fields
+func apiFieldsError(w http.ResponseWriter, status int, message string, fields map[string]string, err error) error {
+	writeJSON(w, status, models.APIError{Status: status, Message: message, Fields: fields})
+	if err == nil {
+		err = fmt.Errorf("%s", message)
+	}
+	return err
+}
+
+// apiSaveError sends the error of a failed save: the invalid fields, or a server error
+func apiSaveError[T any](w http.ResponseWriter, res apiResource[T], err error) error {
+	var invalid *apiInvalid
+	if errors.As(err, &invalid) {
+		return apiFieldsError(w, invalid.status, "Invalid "+res.name, map[string]string{invalid.field: invalid.message}, err)
+	}
+
+	var fieldErrs validation.Errors
+	if errors.As(err, &fieldErrs) {
+		fields := make(map[string]string, len(fieldErrs))
+		for name, fieldErr := range fieldErrs {
+			if bodyName, ok := res.fields[name]; ok {
+				name = bodyName
+			}
+			fields[name] = fieldErr.Error()
+		}
+		return apiFieldsError(w, http.StatusBadRequest, "Invalid "+res.name, fields, err)
+	}
+
+	return apiError(w, http.StatusInternalServerError, "Failed to save "+res.name, err)
+}
+
+// decodeAPIBody reads a JSON request body onto item. Fields missing from the
+// body keep the value item already has.
+func decodeAPIBody(w http.ResponseWriter, r *http.Request, item any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAPIBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(item); err != nil {
+		return apiError(w, http.StatusBadRequest, "Invalid JSON body: "+err.Error(), err)
+	}
+	return nil
+}
+
+// apiList sends a page of the records matching the filter params, sorted by
+// the sort param: comma-separated fields, descending when prefixed with '-'
+func apiList[T any](h *APIHandler, w http.ResponseWriter, r *http.Request, res apiResource[T]) error {
+	query := r.URL.Query()
+
+	page, err := queryInt(query.Get("page"), 1)
+	if err != nil || page < 1 {
+		return apiError(w, http.StatusBadRequest, "page must be a positive number", err)
+	}
+	perPage, err := queryInt(query.Get("per_page"), apiDefaultPerPage)
+	if err != nil || perPage < 1 || perPage > apiMaxPerPage {
+		return apiError(w, http.StatusBadRequest, fmt.Sprintf("per_page must be between 1 and %d", apiMaxPerPage), err)
+	}
+
+	sort := query.Get("sort")
+	if sort == "" {
+		sort = res.sort
+	}
+	sortFields := search.ParseSortFromString(sort)
+	for _, field := range sortFields {
+		if !slices.Contains(res.sorts, field.Name) {
+			return apiError(w, http.StatusBadRequest, fmt.Sprintf("Cannot sort by %q, use one of: %s", field.Name, strings.Join(res.sorts, ", ")), nil)
+		}
+	}
+	// Records with the same sort values keep a stable order across pages
+	sortFields = append(sortFields, search.SortField{Name: "id", Direction: search.SortAsc})
+
+	collection, err := h.app.FindCollectionByNameOrId(res.collection)
+	if err != nil {
+		return apiError(w, http.StatusInternalServerError, "Failed to find collection", err)
+	}
+	resolver := core.NewRecordFieldResolver(h.app, collection, nil, true)
+	baseQuery := h.app.RecordQuery(collection)
+
+	// Build the filter from the params that are set
+	var exprs []string
+	params := dbx.Params{}
+	for _, filter := range res.filters {
+		var matches []string
+		for _, value := range queryList(query[filter.param]) {
+			name := fmt.Sprintf("f%d", len(params))
+			expr, param, err := filter.build(value, "{:"+name+"}")
+			if err != nil {
+				return apiError(w, http.StatusBadRequest, "Invalid filter: "+err.Error(), err)
+			}
+			if param != nil {
+				params[name] = param
+			}
+			matches = append(matches, expr)
+		}
+		if len(matches) > 0 {
+			exprs = append(exprs, "("+strings.Join(matches, " || ")+")")
+		}
+	}
+	if len(exprs) > 0 {
+		expr, err := search.FilterData(strings.Join(exprs, " && ")).BuildExpr(resolver, params)
+		if err != nil {
+			return apiError(w, http.StatusBadRequest, "Invalid filter", err)
+		}
+		baseQuery.AndWhere(expr)
+	}
+
+	records := []*core.Record{}
+	result, err := search.NewProvider(resolver).
+		Query(baseQuery).
+		Sort(sortFields).
+		Page(page).
+		PerPage(perPage).
+		Exec(&records)
+	if err != nil {
+		return apiError(w, http.StatusInternalServerError, "Failed to fetch "+res.collection, err)
+	}
+
+	items, err := res.convert(h.app, records)
+	if err != nil {
+		return apiError(w, http.StatusInternalServerError, "Failed to fetch "+res.collection, err)
+	}
+
+	return writeJSON(w, http.StatusOK, models.APIList[T]{
+		Items:      items,
+		Page:       result.Page,
+		PerPage:    result.PerPage,
+		TotalItems: result.TotalItems,
+		TotalPages: result.TotalPages,
+	})
+}
+
+// apiGet sends one record
+func apiGet[T any](h *APIHandler, w http.ResponseWriter, r *http.Request, res apiResource[T]) error {
+	record, err := h.app.FindRecordById(res.collection, r.PathValue("id"))
+	if err != nil {
+		return apiError(w, http.StatusNotFound, res.notFound(), err)
+	}
+
+	return apiSend(h, w, http.StatusOK, res, record)
+}
+
+// apiCreate creates a record from a JSON body and sends it
+func apiCreate[T any](h *APIHandler, w http.ResponseWriter, r *http.Request, res apiResource[T]) error {
+	var item T
+	if err := decodeAPIBody(w, r, &item); err != nil {
+		return err
+	}
+
+	collection, err := h.app.FindCollectionByNameOrId(res.collection)
+	if err != nil {
+		return apiError(w, http.StatusInternalServerError, "Failed to find collection", err)
+	}
+
+	record := core.NewRecord(collection)
+	if err := h.app.RunInTransaction(func(txApp core.App) error {
+		return res.save(txApp, record, item)
+	}); err != nil {
+		return apiSaveError(w, res, err)
+	}
+
+	return apiSend(h, w, http.StatusCreated, res, record)
+}
+
+// apiUpdate changes the fields of a record given in a JSON body and sends it.
+// Fields left out keep their value.
+func apiUpdate[T any](h *APIHandler, w http.ResponseWriter, r *http.Request, res apiResource[T]) error {
+	record, err := h.app.FindRecordById(res.collection, r.PathValue("id"))
+	if err != nil {
+		return apiError(w, http.StatusNotFound, res.notFound(), err)
+	}
+
+	items, err := res.convert(h.app, []*core.Record{record})
+	if err != nil {
+		return apiError(w, http.StatusInternalServerError, "Failed to fetch "+res.name, err)
+	}
+	item := items[0]
+	if err := decodeAPIBody(w, r, &item); err != nil {
+		return err
+	}
+
+	if err := h.app.RunInTransaction(func(txApp core.App) error {
+		return res.save(txApp, record, item)
+	}); err != nil {
+		return apiSaveError(w, res, err)
+	}
+
+	return apiSend(h, w, http.StatusOK, res, record)
+}
+
+// apiDelete deletes a record
+func apiDelete[T any](h *APIHandler, w http.ResponseWriter, r *http.Request, res apiResource[T]) error {
+	record, err := h.app.FindRecordById(res.collection, r.PathValue("id"))
+	if err != nil {
+		return apiError(w, http.StatusNotFound, res.notFound(), err)
+	}
+
+	if err := h.app.Delete(record); err != nil {
+		return apiError(w, http.StatusInternalServerError, "Failed to delete "+res.name, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// apiSend sends one record as a model
+func apiSend[T any](h *APIHandler, w http.ResponseWriter, status int, res apiResource[T], record *core.Record) error {
+	items, err := res.convert(h.app, []*core.Record{record})
+	if err != nil {
+		return apiError(w, http.StatusInternalServerError, "Failed to fetch "+res.name, err)
+	}
+	return writeJSON(w, status, items[0])
+}
+
+// queryInt reads a number query param, fallback when it is empty
+func queryInt(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// queryList splits repeated, comma-separated query values into their trimmed items
+func queryList(values []string) []string {
+	var items []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
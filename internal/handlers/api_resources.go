@@ -0,0 +1,375 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/util"
+)
+
+var apiCompanies = apiResource[models.Company]{
+	collection: util.CollectionCompanies,
+	name:       "company",
+	fields: map[string]string{
+		"name":        "Name",
+		"description": "Description",
+		"url":         "Url",
+		"linkedin":    "Linkedin",
+		"hq_city":     "HqCity",
+		"hq_state":    "HqState",
+		"tags":        "Tags",
+	},
+	sorts: []string{"name", "hq_city", "hq_state"},
+	sort:  "name",
+	filters: []apiFilter{
+		equalFilter("name", "name", "Company name"),
+		equalFilter("hq_city", "hq_city", "Headquarters city"),
+		equalFilter("hq_state", "hq_state", "Headquarters state"),
+		anyFilter("tag", "tags.name", "Tag name"),
+	},
+	convert: func(app core.App, records []*core.Record) ([]models.Company, error) {
+		if err := expandTags(app, records); err != nil {
+			return nil, err
+		}
+		companies := make([]models.Company, len(records))
+		for i, record := range records {
+			companies[i] = recordToCompany(record)
+		}
+		return companies, nil
+	},
+	save: func(app core.App, record *core.Record, company models.Company) error {
+		record.Set("name", company.Name)
+		record.Set("description", company.Description)
+		record.Set("url", company.Url)
+		record.Set("linkedin", company.Linkedin)
+		record.Set("hq_city", company.HqCity)
+		record.Set("hq_state", company.HqState)
+		if err := setAPITags(app, record, company.Tags); err != nil {
+			return err
+		}
+		return app.Save(record)
+	},
+}
+
+var apiRoles = apiResource[models.Role]{
+	collection: util.CollectionRoles,
+	name:       "role",
+	fields: map[string]string{
+		"company":               "CompanyID",
+		"name":                  "Name",
+		"url":                   "Url",
+		"description":           "Description",
+		"cover_letter":          "CoverLetter",
+		"application_location":  "ApplicationLocation",
+		"applied_date":          "AppliedDate",
+		"closed_date":           "ClosedDate",
+		"posted_range_min":      "PostedRangeMin",
+		"posted_range_max":      "PostedRangeMax",
+		"equity":                "Equity",
+		"work_city":             "WorkCity",
+		"work_state":            "WorkState",
+		"location":              "Location",
+		"status":                "Status",
+		"discovery":             "Discovery",
+		"referral":              "Referral",
+		"notes":                 "Notes",
+		"resume_document":       "ResumeDocumentID",
+		"cover_letter_document": "CoverLetterDocumentID",
+		"tags":                  "Tags",
+	},
+	sorts: []string{
+		"name", "applied_date", "closed_date", "posted_range_min", "posted_range_max",
+		"status", "location", "work_city", "work_state", "equity", "referral",
+	},
+	sort: "-applied_date",
+	filters: []apiFilter{
+		equalFilter("company", "company", "Company ID"),
+		enumFilter("status", "status", "Status", models.RoleStatuses, models.NormalizeRoleStatus),
+		enumFilter("location", "location", "Remote, hybrid or onsite", models.Locations, models.NormalizeLocation),
+		equalFilter("discovery", "discovery", "Where the role was found"),
+		equalFilter("work_city", "work_city", "Work city"),
+		equalFilter("work_state", "work_state", "Work state"),
+		boolFilter("equity", "equity", "Whether the role comes with equity"),
+		boolFilter("referral", "referral", "Whether you were referred"),
+		dateFilter("applied_from", "applied_date", "Applied on or after this day", true),
+		dateFilter("applied_to", "applied_date", "Applied on or before this day", false),
+		anyFilter("tag", "tags.name", "Tag name"),
+	},
+	convert: func(app core.App, records []*core.Record) ([]models.Role, error) {
+		if errs := app.ExpandRecords(records, []string{"company", "tags"}, nil); len(errs) > 0 {
+			return nil, fmt.Errorf("failed to expand roles: %v", errs)
+		}
+		roles := make([]models.Role, len(records))
+		for i, record := range records {
+			roles[i] = recordToRole(record)
+		}
+		return roles, nil
+	},
+	save: saveAPIRole,
+}
+
+// saveAPIRole saves a role and records its status change, as the role form
+// does. Submitted files can't be set through the API, library documents can.
+func saveAPIRole(app core.App, record *core.Record, role models.Role) error {
+	status := models.NormalizeRoleStatus(role.Status)
+	if !models.IsValidRoleStatus(status) {
+		return invalidField("Status", "unknown status %q", role.Status)
+	}
+
+	oldStatus := record.GetString("status")
+	if !models.CanTransitionRoleStatus(oldStatus, status) {
+		return &apiInvalid{
+			status:  http.StatusUnprocessableEntity,
+			field:   "Status",
+			message: fmt.Sprintf("a role can't move from %s to %s", oldStatus, status),
+		}
+	}
+
+	location := models.NormalizeLocation(role.Location)
+	if !models.IsValidLocation(location) {
+		return invalidField("Location", "unknown location %q", role.Location)
+	}
+
+	record.Set("company", role.CompanyID)
+	record.Set("name", role.Name)
+	record.Set("url", role.Url)
+	record.Set("description", role.Description)
+	record.Set("cover_letter", role.CoverLetter)
+	record.Set("application_location", role.ApplicationLocation)
+	record.Set("applied_date", role.AppliedDate)
+	record.Set("closed_date", role.ClosedDate)
+	record.Set("posted_range_min", role.PostedRangeMin)
+	record.Set("posted_range_max", role.PostedRangeMax)
+	record.Set("equity", role.Equity)
+	record.Set("work_city", role.WorkCity)
+	record.Set("work_state", role.WorkState)
+	record.Set("location", location)
+	record.Set("status", status)
+	record.Set("discovery", role.Discovery)
+	record.Set("referral", role.Referral)
+	record.Set("notes", role.Notes)
+	record.Set("resume_document", role.ResumeDocumentID)
+	record.Set("cover_letter_document", role.CoverLetterDocumentID)
+	if err := setAPITags(app, record, role.Tags); err != nil {
+		return err
+	}
+
+	if err := app.Save(record); err != nil {
+		return err
+	}
+	return util.RecordRoleStatusChange(app, record.Id, oldStatus, status, util.StatusSourceAPI, time.Now())
+}
+
+var apiContacts = apiResource[models.Contact]{
+	collection: util.CollectionContacts,
+	name:       "contact",
+	fields: map[string]string{
+		"company":    "CompanyID",
+		"first_name": "FirstName",
+		"last_name":  "LastName",
+		"role":       "Role",
+		"email":      "Email",
+		"phone":      "Phone",
+		"linkedin":   "Linkedin",
+		"notes":      "Notes",
+		"tags":       "Tags",
+	},
+	sorts: []string{"first_name", "last_name", "email", "role"},
+	sort:  "first_name,last_name",
+	filters: []apiFilter{
+		equalFilter("company", "company", "Company ID"),
+		equalFilter("email", "email", "Email address"),
+		equalFilter("first_name", "first_name", "First name"),
+		equalFilter("last_name", "last_name", "Last name"),
+		anyFilter("tag", "tags.name", "Tag name"),
+	},
+	convert: func(app core.App, records []*core.Record) ([]models.Contact, error) {
+		if errs := app.ExpandRecords(records, []string{"company", "tags"}, nil); len(errs) > 0 {
+			return nil, fmt.Errorf("failed to expand contacts: %v", errs)
+		}
+		contacts := make([]models.Contact, len(records))
+		for i, record := range records {
+			contacts[i] = recordToContact(record)
+		}
+		return contacts, nil
+	},
+	save: func(app core.App, record *core.Record, contact models.Contact) error {
+		record.Set("company", contact.CompanyID)
+		record.Set("first_name", contact.FirstName)
+		record.Set("last_name", contact.LastName)
+		record.Set("role", contact.Role)
+		record.Set("email", contact.Email)
+		record.Set("phone", contact.Phone)
+		record.Set("linkedin", contact.Linkedin)
+		record.Set("notes", contact.Notes)
+		if err := setAPITags(app, record, contact.Tags); err != nil {
+			return err
+		}
+		return app.Save(record)
+	},
+}
+
+var apiInterviews = apiResource[models.Interview]{
+	collection: util.CollectionInterviews,
+	name:       "interview",
+	fields: map[string]string{
+		"role":     "RoleID",
+		"date":     "Date",
+		"start":    "Start",
+		"end":      "End",
+		"notes":    "Notes",
+		"type":     "Type",
+		"contacts": "ContactIDs",
+		"tags":     "Tags",
+	},
+	sorts: []string{"date", "type"},
+	sort:  "-date",
+	filters: []apiFilter{
+		equalFilter("role", "role", "Role ID"),
+		equalFilter("company", "role.company", "Company ID of the role"),
+		enumFilter("type", "type", "Interview type", models.InterviewTypes, models.NormalizeInterviewType),
+		anyFilter("contact", "contacts", "Contact ID of a participant"),
+		dateFilter("date_from", "date", "On or after this day", true),
+		dateFilter("date_to", "date", "On or before this day", false),
+		anyFilter("tag", "tags.name", "Tag name"),
+	},
+	convert: func(app core.App, records []*core.Record) ([]models.Interview, error) {
+		if errs := app.ExpandRecords(records, []string{"role.company", "contacts", "tags"}, nil); len(errs) > 0 {
+			return nil, fmt.Errorf("failed to expand interviews: %v", errs)
+		}
+		interviews := make([]models.Interview, len(records))
+		for i, record := range records {
+			interviews[i] = recordToInterview(record)
+			if roleRecord := record.ExpandedOne("role"); roleRecord != nil {
+				if companyRecord := roleRecord.ExpandedOne("company"); companyRecord != nil {
+					interviews[i].CompanyName = companyRecord.GetString("name")
+				}
+			}
+		}
+		return interviews, nil
+	},
+	save: func(app core.App, record *core.Record, interview models.Interview) error {
+		interviewType := models.NormalizeInterviewType(interview.Type)
+		if interviewType != "" && !models.IsValidInterviewType(interviewType) {
+			return invalidField("Type", "unknown interview type %q", interview.Type)
+		}
+
+		contactIDs, err := interviewContacts(app, interview.ContactIDs, interview.RoleID)
+		if err != nil {
+			return invalidField("ContactIDs", "%v", err)
+		}
+
+		record.Set("role", interview.RoleID)
+		record.Set("date", interview.Date)
+		record.Set("start", interview.Start)
+		record.Set("end", interview.End)
+		record.Set("notes", interview.Notes)
+		record.Set("type", interviewType)
+		record.Set("contacts", contactIDs)
+		if err := setAPITags(app, record, interview.Tags); err != nil {
+			return err
+		}
+		return app.Save(record)
+	},
+}
+
+// setAPITags sets a record's tags by name, creating tags that don't exist yet
+func setAPITags(app core.App, record *core.Record, tags []models.Tag) error {
+	ids, err := util.FindOrCreateTags(app, models.SplitTagNames(models.TagNames(tags)))
+	if err != nil {
+		return err
+	}
+	record.Set("tags", ids)
+	return nil
+}
+
+func (h *APIHandler) ListCompanies(w http.ResponseWriter, r *http.Request) error {
+	return apiList(h, w, r, apiCompanies)
+}
+
+func (h *APIHandler) GetCompany(w http.ResponseWriter, r *http.Request) error {
+	return apiGet(h, w, r, apiCompanies)
+}
+
+func (h *APIHandler) CreateCompany(w http.ResponseWriter, r *http.Request) error {
+	return apiCreate(h, w, r, apiCompanies)
+}
+
+func (h *APIHandler) UpdateCompany(w http.ResponseWriter, r *http.Request) error {
+	return apiUpdate(h, w, r, apiCompanies)
+}
+
+func (h *APIHandler) DeleteCompany(w http.ResponseWriter, r *http.Request) error {
+	return apiDelete(h, w, r, apiCompanies)
+}
+
+func (h *APIHandler) ListRoles(w http.ResponseWriter, r *http.Request) error {
+	return apiList(h, w, r, apiRoles)
+}
+
+func (h *APIHandler) GetRole(w http.ResponseWriter, r *http.Request) error {
+	return apiGet(h, w, r, apiRoles)
+}
+
+func (h *APIHandler) CreateRole(w http.ResponseWriter, r *http.Request) error {
+	return apiCreate(h, w, r, apiRoles)
+}
+
+func (h *APIHandler) UpdateRole(w http.ResponseWriter, r *http.Request) error {
+	return apiUpdate(h, w, r, apiRoles)
+}
+
+func (h *APIHandler) DeleteRole(w http.ResponseWriter, r *http.Request) error {
+	return apiDelete(h, w, r, apiRoles)
+}
+
+func (h *APIHandler) ListContacts(w http.ResponseWriter, r *http.Request) error {
+	return apiList(h, w, r, apiContacts)
+}
+
+func (h *APIHandler) GetContact(w http.ResponseWriter, r *http.Request) error {
+	return apiGet(h, w, r, apiContacts)
+}
+
+func (h *APIHandler) CreateContact(w http.ResponseWriter, r *http.Request) error {
+	return apiCreate(h, w, r, apiContacts)
+}
+
+func (h *APIHandler) UpdateContact(w http.ResponseWriter, r *http.Request) error {
+	return apiUpdate(h, w, r, apiContacts)
+}
+
+func (h *APIHandler) DeleteContact(w http.ResponseWriter, r *http.Request) error {
+	return apiDelete(h, w, r, apiContacts)
+}
+
+func (h *APIHandler) ListInterviews(w http.ResponseWriter, r *http.Request) error {
+	return apiList(h, w, r, apiInterviews)
+}
+
+func (h *APIHandler) GetInterview(w http.ResponseWriter, r *http.Request) error {
+	return apiGet(h, w, r, apiInterviews)
+}
+
+func (h *APIHandler) CreateInterview(w http.ResponseWriter, r *http.Request) error {
+	return apiCreate(h, w, r, apiInterviews)
+}
+
+func (h *APIHandler) UpdateInterview(w http.ResponseWriter, r *http.Request) error {
+	return apiUpdate(h, w, r, apiInterviews)
+}
+
+func (h *APIHandler) DeleteInterview(w http.ResponseWriter, r *http.Request) error {
+	return apiDelete(h, w, r, apiInterviews)
+}
+
+// Stats sends the stats page metrics, over the same range, start_date and
+// end_date params
+func (h *APIHandler) Stats(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	return writeJSON(w, http.StatusOK, collectStats(h.app, query.Get("range"), query.Get("start_date"), query.Get("end_date")))
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/openapi"
+	"reverse-ats/internal/util"
+)
+
+// OpenAPI sends the OpenAPI document of the JSON API. It needs no token, so
+// tools can read it before they are set up with one.
+func (h *APIHandler) OpenAPI(w http.ResponseWriter, r *http.Request) error {
+	return writeJSON(w, http.StatusOK, apiSpec())
+}
+
+// apiSpec builds the OpenAPI document of the JSON API from its resources and
+// the models they send, so it changes with them
+func apiSpec() *openapi.Document {
+	doc := &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: openapi.Info{
+			Title:   "reverse-ats API",
+			Version: "1",
+			Description: "Companies, roles, contacts and interviews of a reverse-ats server. " +
+				"Filters take comma-separated values to match any of them; different filters must all match. " +
+				"Updates change the fields given in the body and keep the others.",
+		},
+		Servers: []openapi.Server{{URL: "/api/v1"}},
+		Paths:   make(map[string]*openapi.PathItem),
+		Components: openapi.Components{
+			SecuritySchemes: map[string]*openapi.SecurityScheme{
+				"token": {Type: "http", Scheme: "bearer", Description: "The " + util.EnvAPIToken + " of the server"},
+			},
+		},
+		Security: []map[string][]string{{"token": {}}},
+	}
+
+	addSpecPaths(doc, apiCompanies)
+	addSpecPaths(doc, apiRoles)
+	addSpecPaths(doc, apiContacts)
+	addSpecPaths(doc, apiInterviews)
+
+	doc.Paths["/stats"] = &openapi.PathItem{
+		Get: &openapi.Operation{
+			OperationID: "getStats",
+			Summary:     "The stats page metrics over a date range",
+			Tags:        []string{"Stats"},
+			Parameters: []openapi.Parameter{
+				{Name: "range", In: "query", Description: "Days to look back, all, or custom between start_date and end_date",
+					Schema: &openapi.Schema{Type: "string", Enum: []string{"7", "30", "90", "180", "365", "all", "custom"}, Default: "30"}},
+				{Name: "start_date", In: "query", Schema: &openapi.Schema{Type: "string", Format: "date"}},
+				{Name: "end_date", In: "query", Schema: &openapi.Schema{Type: "string", Format: "date"}},
+			},
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "The metrics", Content: openapi.JSON(doc.Components.Schema(reflect.TypeFor[models.Stats]()))},
+				"401": specError(doc, "Invalid or missing API token"),
+			},
+		},
+	}
+
+	// Tags are set by name
+	doc.Components.Schemas["Tag"].Properties["ID"].ReadOnly = true
+
+	return doc
+}
+
+// addSpecPaths documents the list, get, create, update and delete operations of a resource
+func addSpecPaths[T any](doc *openapi.Document, res apiResource[T]) {
+	t := reflect.TypeFor[T]()
+	item := doc.Components.Schema(t)
+	list := doc.Components.Schema(reflect.TypeFor[models.APIList[T]]())
+
+	// Fields the API doesn't save, like display names, are read-only
+	writable := make(map[string]bool)
+	for _, name := range res.fields {
+		writable[name] = true
+	}
+	for name, property := range doc.Components.Schemas[openapi.SchemaName(t)].Properties {
+		property.ReadOnly = !writable[name]
+	}
+
+	name := strings.ToUpper(res.name[:1]) + res.name[1:]
+	plural := strings.ToUpper(res.collection[:1]) + res.collection[1:]
+	tags := []string{plural}
+	body := &openapi.RequestBody{Required: true, Content: openapi.JSON(item)}
+
+	listParams := []openapi.Parameter{
+		{Name: "sort", In: "query", Description: "Comma-separated fields, descending with a leading '-': " + strings.Join(res.sorts, ", "),
+			Schema: &openapi.Schema{Type: "string", Default: res.sort}},
+		{Name: "page", In: "query", Schema: &openapi.Schema{Type: "integer", Minimum: intPtr(1), Default: 1}},
+		{Name: "per_page", In: "query", Schema: &openapi.Schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(apiMaxPerPage), Default: apiDefaultPerPage}},
+	}
+	for _, filter := range res.filters {
+		listParams = append(listParams, openapi.Parameter{Name: filter.param, In: "query", Description: filter.description, Schema: filter.schema})
+	}
+
+	doc.Paths["/"+res.collection] = &openapi.PathItem{
+		Get: &openapi.Operation{
+			OperationID: "list" + plural,
+			Summary:     "List " + res.collection,
+			Tags:        tags,
+			Parameters:  listParams,
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "A page of " + res.collection, Content: openapi.JSON(list)},
+				"400": specError(doc, "Invalid filter, sort or page"),
+				"401": specError(doc, "Invalid or missing API token"),
+			},
+		},
+		Post: &openapi.Operation{
+			OperationID: "create" + name,
+			Summary:     "Create a " + res.name,
+			Tags:        tags,
+			RequestBody: body,
+			Responses: map[string]*openapi.Response{
+				"201": {Description: "The new " + res.name, Content: openapi.JSON(item)},
+				"400": specError(doc, "Invalid body; Fields holds the problem with each field"),
+				"401": specError(doc, "Invalid or missing API token"),
+			},
+		},
+	}
+
+	updateResponses := map[string]*openapi.Response{
+		"200": {Description: "The updated " + res.name, Content: openapi.JSON(item)},
+		"400": specError(doc, "Invalid body; Fields holds the problem with each field"),
+		"401": specError(doc, "Invalid or missing API token"),
+		"404": specError(doc, name+" not found"),
+	}
+	if res.collection == util.CollectionRoles {
+		updateResponses["422"] = specError(doc, "The role can't move to the new status")
+	}
+
+	doc.Paths["/"+res.collection+"/{id}"] = &openapi.PathItem{
+		Parameters: []openapi.Parameter{{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}}},
+		Get: &openapi.Operation{
+			OperationID: "get" + name,
+			Summary:     "Get a " + res.name,
+			Tags:        tags,
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "The " + res.name, Content: openapi.JSON(item)},
+				"401": specError(doc, "Invalid or missing API token"),
+				"404": specError(doc, name+" not found"),
+			},
+		},
+		Put: &openapi.Operation{
+			OperationID: "update" + name,
+			Summary:     "Update a " + res.name,
+			Tags:        tags,
+			RequestBody: body,
+			Responses:   updateResponses,
+		},
+		Patch: &openapi.Operation{
+			OperationID: "patch" + name,
+			Summary:     "Update some fields of a " + res.name,
+			Tags:        tags,
+			RequestBody: body,
+			Responses:   updateResponses,
+		},
+		Delete: &openapi.Operation{
+			OperationID: "delete" + name,
+			Summary:     "Delete a " + res.name,
+			Tags:        tags,
+			Responses: map[string]*openapi.Response{
+				"204": {Description: "Deleted"},
+				"401": specError(doc, "Invalid or missing API token"),
+				"404": specError(doc, name+" not found"),
+			},
+		},
+	}
+}
+
+// specError documents an error response
+func specError(doc *openapi.Document, description string) *openapi.Response {
+	return &openapi.Response{
+		Description: description,
+		Content:     openapi.JSON(doc.Components.Schema(reflect.TypeFor[models.APIError]())),
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
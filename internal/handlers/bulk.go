@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/pocketbase/pocketbase/core"
+
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/templates"
+	"reverse-ats/internal/util"
+)
+
+// bulkList describes a list page to the bulk action endpoint shared by the lists
+type bulkList struct {
+	collection string   // Collection of the rows
+	rowPrefix  string   // Row element ids are the prefix, a dash and the record id
+	noun       string   // Plural name of the rows for messages
+	actions    []string // Actions the list offers, from models
+
+	// check validates the list's own actions before anything is saved,
+	// returning what to tell the user when the action can't be applied
+	check func(r *http.Request, action string, records []*core.Record) string
+
+	// edit applies the list's own actions to one record inside the transaction
+	edit func(txApp core.App, r *http.Request, action string, record *core.Record) error
+
+	// rows renders the refreshed rows of the changed records
+	rows func(app core.App, records []*core.Record) ([]templ.Component, error)
+}
+
+// runBulkAction applies the action of a bulk action form to the picked rows
+// of a list in one transaction, so either every row changes or none does.
+// HTMX requests get the changed rows back, to be swapped in where they are.
+func runBulkAction(app core.App, w http.ResponseWriter, r *http.Request, list bulkList) error {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return err
+	}
+
+	action := r.FormValue("action")
+	if !slices.Contains(list.actions, action) {
+		http.Error(w, "Unknown bulk action", http.StatusBadRequest)
+		return fmt.Errorf("unknown bulk action %q", action)
+	}
+
+	var ids []string
+	for _, id := range r.Form["ids"] {
+		if id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		http.Error(w, "Select at least one row", http.StatusBadRequest)
+		return fmt.Errorf("no rows selected")
+	}
+
+	records, err := app.FindRecordsByIds(list.collection, ids)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to fetch %s", list.noun), http.StatusInternalServerError)
+		return err
+	}
+	if len(records) != len(ids) {
+		http.Error(w, fmt.Sprintf("Some of the selected %s no longer exist, reload the page", list.noun), http.StatusNotFound)
+		return fmt.Errorf("found %d of %d selected %s", len(records), len(ids), list.noun)
+	}
+
+	listPath := "/" + list.collection
+
+	// Exporting changes nothing: download a zip of the picked rows
+	if action == models.BulkActionExport {
+		query := url.Values{"collections": {list.collection}, "ids": ids}
+		exportURL := "/export?" + query.Encode()
+		if r.Header.Get("HX-Request") == "true" {
+			w.Header().Set("HX-Redirect", exportURL)
+			w.WriteHeader(http.StatusOK)
+			return nil
+		}
+		http.Redirect(w, r, exportURL, http.StatusSeeOther)
+		return nil
+	}
+
+	var tagNames []string
+	switch action {
+	case models.BulkActionTag:
+		tagNames = models.SplitTagNames(r.FormValue("tag"))
+		if len(tagNames) == 0 {
+			http.Error(w, "Enter a tag to add", http.StatusBadRequest)
+			return fmt.Errorf("missing tag")
+		}
+	case models.BulkActionDelete:
+	default:
+		if problem := list.check(r, action, records); problem != "" {
+			http.Error(w, problem, http.StatusUnprocessableEntity)
+			return fmt.Errorf("bulk %s of %s rejected: %s", action, list.noun, problem)
+		}
+	}
+
+	err = app.RunInTransaction(func(txApp core.App) error {
+		switch action {
+		case models.BulkActionDelete:
+			for _, record := range records {
+				if err := txApp.Delete(record); err != nil {
+					return err
+				}
+			}
+			return nil
+		case models.BulkActionTag:
+			tagIDs, err := util.FindOrCreateTags(txApp, tagNames)
+			if err != nil {
+				return err
+			}
+			for _, record := range records {
+				tags := record.GetStringSlice("tags")
+				for _, id := range tagIDs {
+					if !slices.Contains(tags, id) {
+						tags = append(tags, id)
+					}
+				}
+				record.Set("tags", tags)
+				if err := txApp.Save(record); err != nil {
+					return err
+				}
+			}
+			return nil
+		default:
+			for _, record := range records {
+				if err := list.edit(txApp, r, action, record); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update %s", list.noun), http.StatusInternalServerError)
+		return err
+	}
+
+	if r.Header.Get("HX-Request") != "true" {
+		http.Redirect(w, r, listPath, http.StatusSeeOther)
+		return nil
+	}
+
+	if action == models.BulkActionDelete {
+		removed := make([]string, len(records))
+		for i, record := range records {
+			removed[i] = list.rowPrefix + "-" + record.Id
+		}
+		message := fmt.Sprintf("Deleted %d %s.", len(records), bulkNoun(list.noun, len(records)))
+		return templates.BulkResult(nil, removed, message).Render(r.Context(), w)
+	}
+
+	rows, err := list.rows(app, records)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to fetch %s", list.noun), http.StatusInternalServerError)
+		return err
+	}
+	message := fmt.Sprintf("Updated %d %s.", len(records), bulkNoun(list.noun, len(records)))
+	return templates.BulkResult(rows, nil, message).Render(r.Context(), w)
+}
+
+// bulkNoun returns the singular of a list's plural noun for one row
+func bulkNoun(noun string, count int) string {
+	if count != 1 {
+		return noun
+	}
+	if strings.HasSuffix(noun, "ies") {
+		return strings.TrimSuffix(noun, "ies") + "y"
+	}
+	return strings.TrimSuffix(noun, "s")
+}
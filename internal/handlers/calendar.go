@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+
+	"reverse-ats/internal/ical"
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/util"
+)
+
+// CalendarHandler serves the interviews as an iCalendar feed
+type CalendarHandler struct {
+	app   *pocketbase.PocketBase
+	token string // Required ?token= of the feed; the feed is disabled without one
+}
+
+func NewCalendarHandler(app *pocketbase.PocketBase, token string) *CalendarHandler {
+	return &CalendarHandler{app: app, token: token}
+}
+
+// Interviews serves every interview as a calendar to subscribe to
+func (h *CalendarHandler) Interviews(w http.ResponseWriter, r *http.Request) error {
+	if h.token == "" {
+		http.Error(w, "The calendar feed is disabled. Set "+util.EnvCalendarToken+" to enable it.", http.StatusNotFound)
+		return fmt.Errorf("calendar feed is disabled")
+	}
+	if subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(h.token)) != 1 {
+		http.Error(w, "Invalid calendar token", http.StatusUnauthorized)
+		return fmt.Errorf("invalid calendar token")
+	}
+
+	events, err := interviewEvents(h.app, r, "", nil)
+	if err != nil {
+		http.Error(w, "Failed to fetch interviews", http.StatusInternalServerError)
+		return err
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	return ical.Write(w, "Interviews", events)
+}
+
+// interviewEvents returns the interviews matching a filter as calendar events
+func interviewEvents(app *pocketbase.PocketBase, r *http.Request, filter string, params dbx.Params) ([]ical.Event, error) {
+	records, err := app.FindRecordsByFilter(util.CollectionInterviews, filter, "date", -1, 0, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if errs := app.ExpandRecords(records, []string{"contacts", "tags"}, nil); len(errs) > 0 {
+		return nil, fmt.Errorf("failed to expand contacts: %v", errs)
+	}
+
+	// Fetch all roles and companies once to avoid N+1 queries
+	rolesMap, err := util.FetchRolesMap(app)
+	if err != nil {
+		return nil, err
+	}
+	companiesMap, err := util.FetchCompaniesMap(app)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]ical.Event, 0, len(records))
+	for _, record := range records {
+		interview := recordToInterview(record)
+		if roleInfo, ok := rolesMap[interview.RoleID]; ok {
+			interview.RoleName = roleInfo.Name
+			interview.CompanyName = companiesMap[roleInfo.CompanyID]
+		}
+		if event, ok := interviewEvent(interview, requestBaseURL(r)); ok {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// interviewEvent turns an interview into a calendar event. Interviews whose
+// times can't be read become all-day events, and ones without a date are skipped.
+func interviewEvent(interview models.Interview, baseURL string) (ical.Event, bool) {
+	summary := models.InterviewTypeLabel(interview.Type)
+	switch {
+	case interview.RoleName != "" && interview.CompanyName != "":
+		summary += fmt.Sprintf(": %s at %s", interview.RoleName, interview.CompanyName)
+	case interview.CompanyName != "":
+		summary += ": " + interview.CompanyName
+	}
+
+	event := ical.Event{
+		UID:         interview.ID + "@reverse-ats",
+		Summary:     summary,
+		Description: interview.Notes,
+		URL:         fmt.Sprintf("%s/interviews/%s/edit", baseURL, interview.ID),
+	}
+
+	for _, contact := range interview.Contacts {
+		event.Attendees = append(event.Attendees, ical.Attendee{
+			Name:  contact.FirstName + " " + contact.LastName,
+			Email: contact.Email,
+		})
+	}
+	for _, tag := range interview.Tags {
+		event.Categories = append(event.Categories, tag.Name)
+	}
+
+	day, err := time.Parse("2006-01-02", interview.Date)
+	if err != nil {
+		return event, false
+	}
+	start, startErr := time.Parse("2006-01-02 15:04", interview.Date+" "+interview.Start)
+	end, endErr := time.Parse("2006-01-02 15:04", interview.Date+" "+interview.End)
+	if startErr != nil || endErr != nil {
+		event.AllDay = true
+		event.Start = day
+		event.End = day.AddDate(0, 0, 1)
+		return event, true
+	}
+
+	// An interview ending before it starts runs past midnight
+	if end.Before(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+
+	event.Start = start
+	event.End = end
+	return event, true
+}
+
+// requestBaseURL returns the scheme and host the app was reached at
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// writeICS sends calendar events as a file download
+func writeICS(w http.ResponseWriter, filename string, events []ical.Event) error {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	return ical.Write(w, "", events)
+}
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/posting"
+	"reverse-ats/internal/templates"
+	"reverse-ats/internal/util"
+)
+
+// maxCaptureSize caps captured pages; inline scripts and styles make some a few MB
+const maxCaptureSize = 20 << 20
+
+// Lengths of the company and role fields a capture fills
+const (
+	maxCompanyNameLength     = 500
+	maxRoleNameLength        = 500
+	maxRoleDescriptionLength = 50000
+	maxWorkCityLength        = 200
+	maxWorkStateLength       = 100
+)
+
+// errNoCompany is returned when a posting doesn't tell its company
+var errNoCompany = errors.New("couldn't tell the company of the posting")
+
+// CaptureHandler saves job postings sent from the browser as roles
+type CaptureHandler struct {
+	app   *pocketbase.PocketBase
+	token string // Required token; capture is disabled without one
+}
+
+func NewCaptureHandler(app *pocketbase.PocketBase, token string) *CaptureHandler {
+	return &CaptureHandler{app: app, token: token}
+}
+
+// captureRequest is a job posting sent by the bookmarklet, as a form, or by a
+// browser extension, as JSON
+type captureRequest struct {
+	URL       string
+	Title     string
+	Selection string // Text selected on the page, used as the description
+	HTML      string
+}
+
+// Bookmarklet shows the page to install the capture bookmarklet from
+func (h *CaptureHandler) Bookmarklet(w http.ResponseWriter, r *http.Request) error {
+	script := ""
+	if h.token != "" {
+		script = bookmarkletScript(requestBaseURL(r)+"/capture", h.token)
+	}
+	return templates.CaptureBookmarklet(script).Render(r.Context(), w)
+}
+
+// Capture saves a job posting as a RESEARCH role, finding or creating its
+// company. A posting whose URL is already saved shows the existing role.
+func (h *CaptureHandler) Capture(w http.ResponseWriter, r *http.Request) error {
+	wantsJSON := strings.HasPrefix(r.Header.Get("Content-Type"), "application/json")
+	fail := func(status int, message string, err error) error {
+		if wantsJSON {
+			return apiError(w, status, message, err)
+		}
+		http.Error(w, message, status)
+		if err == nil {
+			err = fmt.Errorf("%s", message)
+		}
+		return err
+	}
+
+	if h.token == "" {
+		return fail(http.StatusNotFound, "Capture is disabled. Set "+util.EnvCaptureToken+" to enable it.", nil)
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxCaptureSize)
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	var req captureRequest
+	if wantsJSON {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return fail(http.StatusBadRequest, "Invalid JSON body: "+err.Error(), err)
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			return fail(http.StatusBadRequest, "Invalid form data", err)
+		}
+		req = captureRequest{
+			URL:       r.FormValue("url"),
+			Title:     r.FormValue("title"),
+			Selection: r.FormValue("selection"),
+			HTML:      r.FormValue("html"),
+		}
+		if token == "" {
+			token = r.FormValue("token")
+		}
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		return fail(http.StatusUnauthorized, "Invalid or missing token. Reinstall the bookmarklet from /capture.", nil)
+	}
+
+	u, err := url.Parse(strings.TrimSpace(req.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fail(http.StatusBadRequest, "Only web pages can be captured", err)
+	}
+
+	var capture models.Capture
+	err = h.app.RunInTransaction(func(txApp core.App) error {
+		capture, err = saveCapture(txApp, posting.Parse(u.String(), req.Title, req.HTML), req.Selection)
+		return err
+	})
+	if errors.Is(err, errNoCompany) {
+		return fail(http.StatusUnprocessableEntity, "Couldn't tell the company of this posting. Add the role from the Roles page instead.", err)
+	}
+	if err != nil {
+		return fail(http.StatusInternalServerError, "Failed to save the posting", err)
+	}
+
+	if wantsJSON {
+		status := http.StatusOK
+		if capture.RoleCreated {
+			status = http.StatusCreated
+		}
+		return writeJSON(w, status, capture)
+	}
+	return templates.CaptureResult(capture).Render(r.Context(), w)
+}
+
+// saveCapture finds or creates the company and the role of a posting. The
+// selected text, when there is some, is the description.
+func saveCapture(app core.App, p posting.Posting, selection string) (models.Capture, error) {
+	roleRecord, err := app.FindFirstRecordByData(util.CollectionRoles, "url", p.URL)
+	if err == nil {
+		companyRecord, err := app.FindRecordById(util.CollectionCompanies, roleRecord.GetString("company"))
+		if err != nil {
+			return models.Capture{}, err
+		}
+		return captureResult(app, roleRecord, companyRecord, false, false)
+	}
+
+	companyRecord, err := findPostingCompany(app, p)
+	if err != nil {
+		return models.Capture{}, err
+	}
+	companyCreated := companyRecord == nil
+	if companyCreated {
+		if p.Company == "" {
+			return models.Capture{}, errNoCompany
+		}
+		collection, err := app.FindCollectionByNameOrId(util.CollectionCompanies)
+		if err != nil {
+			return models.Capture{}, err
+		}
+		companyRecord = core.NewRecord(collection)
+		companyRecord.Set("name", util.Truncate(p.Company, maxCompanyNameLength))
+		companyRecord.Set("url", p.CompanyURL)
+		if err := app.Save(companyRecord); err != nil {
+			// A website the URL field won't take is left out rather than losing the posting
+			companyRecord.Set("url", "")
+			if err := app.Save(companyRecord); err != nil {
+				return models.Capture{}, err
+			}
+		}
+	}
+
+	collection, err := app.FindCollectionByNameOrId(util.CollectionRoles)
+	if err != nil {
+		return models.Capture{}, err
+	}
+
+	name := p.Title
+	if name == "" {
+		name = "Role at " + companyRecord.GetString("name")
+	}
+	description := strings.TrimSpace(selection)
+	if description == "" {
+		description = p.Description
+	}
+	location := ""
+	if p.Remote {
+		location = models.LocationRemote
+	}
+
+	roleRecord = core.NewRecord(collection)
+	roleRecord.Set("company", companyRecord.Id)
+	roleRecord.Set("name", util.Truncate(name, maxRoleNameLength))
+	roleRecord.Set("url", p.URL)
+	roleRecord.Set("description", util.Truncate(description, maxRoleDescriptionLength))
+	roleRecord.Set("work_city", util.Truncate(p.City, maxWorkCityLength))
+	roleRecord.Set("work_state", util.Truncate(p.State, maxWorkStateLength))
+	roleRecord.Set("location", location)
+	roleRecord.Set("status", models.RoleStatusResearch)
+	if err := app.Save(roleRecord); err != nil {
+		return models.Capture{}, err
+	}
+	if err := util.RecordRoleStatusChange(app, roleRecord.Id, "", models.RoleStatusResearch, util.StatusSourceCapture, time.Now()); err != nil {
+		return models.Capture{}, err
+	}
+
+	return captureResult(app, roleRecord, companyRecord, true, companyCreated)
+}
+
+// findPostingCompany finds the company of a posting by name, or else by its
+// website against the company URLs. It returns nil when none matches.
+func findPostingCompany(app core.App, p posting.Posting) (*core.Record, error) {
+	companyRecords, err := app.FindRecordsByFilter(util.CollectionCompanies, "", "name", -1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range companyRecords {
+		if posting.SameName(record.GetString("name"), p.Company) {
+			return record, nil
+		}
+	}
+
+	if host := urlHost(p.CompanyURL); host != "" {
+		for _, record := range companyRecords {
+			if matchesAnyDomain(host, []string{urlHost(record.GetString("url"))}) {
+				return record, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// captureResult converts the saved records of a capture
+func captureResult(app core.App, roleRecord, companyRecord *core.Record, roleCreated, companyCreated bool) (models.Capture, error) {
+	if err := expandTags(app, []*core.Record{roleRecord, companyRecord}); err != nil {
+		return models.Capture{}, err
+	}
+	role := recordToRole(roleRecord)
+	role.CompanyName = companyRecord.GetString("name")
+
+	return models.Capture{
+		Role:           role,
+		Company:        recordToCompany(companyRecord),
+		RoleCreated:    roleCreated,
+		CompanyCreated: companyCreated,
+	}, nil
+}
+
+// bookmarkletScript returns the javascript: URL of the bookmarklet. It posts
+// the page's URL, title, selected text and HTML to the capture endpoint as a
+// form in a new tab, which works on pages whose security policy blocks fetch.
+func bookmarkletScript(action, token string) string {
+	quote := func(s string) string {
+		b, _ := json.Marshal(s)
+		return string(b)
+	}
+	script := "javascript:(function(){" +
+		"var f=document.createElement('form');" +
+		"f.method='post';f.action=" + quote(action) + ";f.target='_blank';f.acceptCharset='utf-8';" +
+		"var d={token:" + quote(token) + ",url:location.href,title:document.title," +
+		"selection:String(getSelection()),html:document.documentElement.outerHTML};" +
+		"for(var k in d){var i=document.createElement('input');i.type='hidden';i.name=k;i.value=d[k];f.appendChild(i)}" +
+		"document.body.appendChild(f);f.submit();f.remove()})()"
+
+	// Browsers decode javascript: URLs before running them
+	return strings.ReplaceAll(script, "%", "%25")
+}
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/a-h/templ"
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/core"
 
@@ -33,6 +34,7 @@ func recordToContact(record *core.Record) models.Contact {
 		Phone:     record.GetString("phone"),
 		Linkedin:  record.GetString("linkedin"),
 		Notes:     record.GetString("notes"),
+		Tags:      recordTags(record),
 		CreatedAt: record.GetDateTime("created").String(),
 		UpdatedAt: record.GetDateTime("updated").String(),
 	}
@@ -92,19 +94,32 @@ func (h *ContactsHandler) List(w http.ResponseWriter, r *http.Request) error {
 		sortField = ""
 	}
 
+	// Narrow down to the ?tag= filter, if any
+	filter, params, tagFilter, err := listTagFilter(h.app, r)
+	if err != nil {
+		http.Error(w, "Failed to fetch tags", http.StatusInternalServerError)
+		return err
+	}
+
 	// Fetch contacts
 	records, err := h.app.FindRecordsByFilter(
 		"contacts",
-		"",
+		filter,
 		sortField,
 		-1, // all records
 		0,
+		params,
 	)
 	if err != nil {
 		http.Error(w, "Failed to fetch contacts", http.StatusInternalServerError)
 		return err
 	}
 
+	if err := expandTags(h.app, records); err != nil {
+		http.Error(w, "Failed to fetch tags", http.StatusInternalServerError)
+		return err
+	}
+
 	// Fetch all companies once to avoid N+1 queries
 	companiesMap, err := util.FetchCompaniesMap(h.app)
 	if err != nil {
@@ -137,7 +152,7 @@ func (h *ContactsHandler) List(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
-	return templates.ContactsList(contacts, sortBy, order, companies).Render(r.Context(), w)
+	return templates.ContactsList(contacts, sortBy, order, companies, tagFilter).Render(r.Context(), w)
 }
 
 func (h *ContactsHandler) New(w http.ResponseWriter, r *http.Request) error {
@@ -148,7 +163,13 @@ func (h *ContactsHandler) New(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
-	return templates.ContactFormNew(companies).Render(r.Context(), w)
+	tags, err := util.FetchTags(h.app)
+	if err != nil {
+		http.Error(w, "Failed to fetch tags", http.StatusInternalServerError)
+		return err
+	}
+
+	return templates.ContactFormNew(companies, tags).Render(r.Context(), w)
 }
 
 func (h *ContactsHandler) Create(w http.ResponseWriter, r *http.Request) error {
@@ -172,6 +193,10 @@ func (h *ContactsHandler) Create(w http.ResponseWriter, r *http.Request) error {
 	record.Set("phone", r.FormValue("phone"))
 	record.Set("linkedin", r.FormValue("linkedin"))
 	record.Set("notes", r.FormValue("notes"))
+	if err := setRecordTags(h.app, r, record); err != nil {
+		http.Error(w, "Failed to save tags", http.StatusInternalServerError)
+		return err
+	}
 
 	if err := h.app.Save(record); err != nil {
 		http.Error(w, "Failed to create contact", http.StatusInternalServerError)
@@ -180,6 +205,7 @@ func (h *ContactsHandler) Create(w http.ResponseWriter, r *http.Request) error {
 
 	// If HTMX request, return just the new row
 	if r.Header.Get("HX-Request") == "true" {
+		h.app.ExpandRecord(record, []string{"tags"}, nil)
 		contact := recordToContact(record)
 		// Fetch company name for display
 		if companyID := record.GetString("company"); companyID != "" {
@@ -209,6 +235,7 @@ func (h *ContactsHandler) Edit(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
+	h.app.ExpandRecord(record, []string{"tags"}, nil)
 	contact := recordToContact(record)
 
 	// Fetch company name for display
@@ -225,7 +252,13 @@ func (h *ContactsHandler) Edit(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
-	return templates.ContactFormEdit(contact, companies).Render(r.Context(), w)
+	tags, err := util.FetchTags(h.app)
+	if err != nil {
+		http.Error(w, "Failed to fetch tags", http.StatusInternalServerError)
+		return err
+	}
+
+	return templates.ContactFormEdit(contact, companies, tags).Render(r.Context(), w)
 }
 
 func (h *ContactsHandler) Update(w http.ResponseWriter, r *http.Request) error {
@@ -255,6 +288,10 @@ func (h *ContactsHandler) Update(w http.ResponseWriter, r *http.Request) error {
 	record.Set("phone", r.FormValue("phone"))
 	record.Set("linkedin", r.FormValue("linkedin"))
 	record.Set("notes", r.FormValue("notes"))
+	if err := setRecordTags(h.app, r, record); err != nil {
+		http.Error(w, "Failed to save tags", http.StatusInternalServerError)
+		return err
+	}
 
 	if err := h.app.Save(record); err != nil {
 		http.Error(w, "Failed to update contact", http.StatusInternalServerError)
@@ -294,3 +331,23 @@ func (h *ContactsHandler) Delete(w http.ResponseWriter, r *http.Request) error {
 	http.Redirect(w, r, "/contacts", http.StatusSeeOther)
 	return nil
 }
+
+// Bulk applies an action to the contacts picked in the list
+func (h *ContactsHandler) Bulk(w http.ResponseWriter, r *http.Request) error {
+	return runBulkAction(h.app, w, r, bulkList{
+		collection: util.CollectionContacts,
+		rowPrefix:  "contact",
+		noun:       "contacts",
+		actions:    models.BulkActions,
+		rows: func(app core.App, records []*core.Record) ([]templ.Component, error) {
+			if errs := app.ExpandRecords(records, []string{"company", "tags"}, nil); len(errs) > 0 {
+				return nil, fmt.Errorf("failed to expand contacts: %v", errs)
+			}
+			rows := make([]templ.Component, len(records))
+			for i, record := range records {
+				rows[i] = templates.ContactRow(recordToContact(record))
+			}
+			return rows, nil
+		},
+	})
+}
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/templates"
+	"reverse-ats/internal/util"
+)
+
+const maxDocumentSize = 10 << 20 // 10 MB, matches the file fields' MaxSize
+
+type DocumentsHandler struct {
+	app *pocketbase.PocketBase
+}
+
+func NewDocumentsHandler(app *pocketbase.PocketBase) *DocumentsHandler {
+	return &DocumentsHandler{app: app}
+}
+
+func recordToDocument(record *core.Record) models.Document {
+	return models.Document{
+		ID:        record.Id,
+		Label:     record.GetString("label"),
+		Kind:      record.GetString("kind"),
+		File:      record.GetString("file"),
+		Notes:     record.GetString("notes"),
+		CreatedAt: record.GetDateTime("created").String(),
+		UpdatedAt: record.GetDateTime("updated").String(),
+	}
+}
+
+// parseUploadForm parses a form that may carry file uploads.
+// Plain urlencoded forms (e.g. inline add rows) are parsed as usual.
+func parseUploadForm(w http.ResponseWriter, r *http.Request) error {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		r.Body = http.MaxBytesReader(w, r.Body, maxDocumentSize*3) // Up to 2 files plus fields
+		return r.ParseMultipartForm(maxMemory)
+	}
+	return r.ParseForm()
+}
+
+// uploadedFile returns the file uploaded in a form field, or nil if there is none
+func uploadedFile(r *http.Request, field string) (*filesystem.File, error) {
+	if r.MultipartForm == nil || len(r.MultipartForm.File[field]) == 0 {
+		return nil, nil
+	}
+
+	header := r.MultipartForm.File[field][0]
+	if header.Size == 0 {
+		return nil, nil
+	}
+	if header.Size > maxDocumentSize {
+		return nil, fmt.Errorf("file too large: %d bytes (max %d bytes)", header.Size, maxDocumentSize)
+	}
+
+	return filesystem.NewFileFromMultipart(header)
+}
+
+// serveRecordFile streams the file stored in a record's file field
+func serveRecordFile(app core.App, w http.ResponseWriter, r *http.Request, record *core.Record, field string) error {
+	filename := record.GetString(field)
+	if filename == "" {
+		http.Error(w, "File not found", http.StatusNotFound)
+		return fmt.Errorf("no file in %s", field)
+	}
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		http.Error(w, "Failed to open file storage", http.StatusInternalServerError)
+		return err
+	}
+	defer fsys.Close()
+
+	if err := fsys.Serve(w, r, record.BaseFilesPath()+"/"+filename, filename); err != nil {
+		http.Error(w, "File not found", http.StatusNotFound)
+		return err
+	}
+
+	return nil
+}
+
+func (h *DocumentsHandler) List(w http.ResponseWriter, r *http.Request) error {
+	documents, err := util.FetchDocuments(h.app)
+	if err != nil {
+		http.Error(w, "Failed to fetch documents", http.StatusInternalServerError)
+		return err
+	}
+
+	// Count the roles referencing each document
+	roleRecords, err := h.app.FindRecordsByFilter(
+		util.CollectionRoles,
+		"resume_document != '' || cover_letter_document != ''",
+		"",
+		-1,
+		0,
+	)
+	if err != nil {
+		http.Error(w, "Failed to fetch roles", http.StatusInternalServerError)
+		return err
+	}
+
+	roleCounts := make(map[string]int)
+	for _, record := range roleRecords {
+		roleCounts[record.GetString("resume_document")]++
+		roleCounts[record.GetString("cover_letter_document")]++
+	}
+	for i := range documents {
+		documents[i].RoleCount = roleCounts[documents[i].ID]
+	}
+
+	return templates.DocumentsList(documents).Render(r.Context(), w)
+}
+
+func (h *DocumentsHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	if err := parseUploadForm(w, r); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return err
+	}
+
+	file, err := uploadedFile(r, "file")
+	if err != nil {
+		http.Error(w, "Invalid file: "+err.Error(), http.StatusBadRequest)
+		return err
+	}
+	if file == nil {
+		http.Error(w, "A file is required", http.StatusBadRequest)
+		return fmt.Errorf("missing document file")
+	}
+
+	collection, err := h.app.FindCollectionByNameOrId(util.CollectionDocuments)
+	if err != nil {
+		http.Error(w, "Failed to find collection", http.StatusInternalServerError)
+		return err
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("label", r.FormValue("label"))
+	record.Set("kind", r.FormValue("kind"))
+	record.Set("notes", r.FormValue("notes"))
+	record.Set("file", file)
+
+	if err := h.app.Save(record); err != nil {
+		http.Error(w, "Failed to create document", http.StatusInternalServerError)
+		return err
+	}
+
+	// If HTMX request, return just the new row
+	if r.Header.Get("HX-Request") == "true" {
+		return templates.DocumentRow(recordToDocument(record)).Render(r.Context(), w)
+	}
+
+	// Otherwise redirect
+	http.Redirect(w, r, "/documents", http.StatusSeeOther)
+	return nil
+}
+
+func (h *DocumentsHandler) Edit(w http.ResponseWriter, r *http.Request) error {
+	// Extract ID from URL path parameter
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	record, err := h.app.FindRecordById(util.CollectionDocuments, id)
+	if err != nil {
+		http.Error(w, "Document not found", http.StatusNotFound)
+		return err
+	}
+
+	return templates.DocumentFormEdit(recordToDocument(record)).Render(r.Context(), w)
+}
+
+func (h *DocumentsHandler) Update(w http.ResponseWriter, r *http.Request) error {
+	// Extract ID from URL path parameter
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	if err := parseUploadForm(w, r); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return err
+	}
+
+	record, err := h.app.FindRecordById(util.CollectionDocuments, id)
+	if err != nil {
+		http.Error(w, "Document not found", http.StatusNotFound)
+		return err
+	}
+
+	record.Set("label", r.FormValue("label"))
+	record.Set("kind", r.FormValue("kind"))
+	record.Set("notes", r.FormValue("notes"))
+
+	// Replace the file only when a new one was uploaded
+	file, err := uploadedFile(r, "file")
+	if err != nil {
+		http.Error(w, "Invalid file: "+err.Error(), http.StatusBadRequest)
+		return err
+	}
+	if file != nil {
+		record.Set("file", file)
+	}
+
+	if err := h.app.Save(record); err != nil {
+		http.Error(w, "Failed to update document", http.StatusInternalServerError)
+		return err
+	}
+
+	http.Redirect(w, r, "/documents", http.StatusSeeOther)
+	return nil
+}
+
+func (h *DocumentsHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	// Extract ID from URL path parameter
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	record, err := h.app.FindRecordById(util.CollectionDocuments, id)
+	if err != nil {
+		http.Error(w, "Document not found", http.StatusNotFound)
+		return err
+	}
+
+	if err := h.app.Delete(record); err != nil {
+		http.Error(w, "Failed to delete document", http.StatusInternalServerError)
+		return err
+	}
+
+	// If HTMX request, return empty response (row will be removed)
+	if r.Header.Get("HX-Request") == "true" {
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+
+	// Otherwise redirect
+	http.Redirect(w, r, "/documents", http.StatusSeeOther)
+	return nil
+}
+
+// Download serves the file of a library document
+func (h *DocumentsHandler) Download(w http.ResponseWriter, r *http.Request) error {
+	record, err := h.app.FindRecordById(util.CollectionDocuments, r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Document not found", http.StatusNotFound)
+		return err
+	}
+
+	return serveRecordFile(h.app, w, r, record, "file")
+}
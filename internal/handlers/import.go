@@ -1,170 +1,298 @@
-package handlers
-
-import (
-	"fmt"
-	"io"
-	"net/http"
-	"os"
-	"path/filepath"
-	"strings"
-
-	"github.com/pocketbase/pocketbase"
-
-	"reverse-ats/internal/importer"
-)
-
-const (
-	maxUploadSize = 10 << 20 // 10 MB
-	maxMemory     = 5 << 20  // 5 MB for parsing multipart form
-)
-
-type ImportHandler struct {
-	app *pocketbase.PocketBase
-}
-
-func NewImportHandler(app *pocketbase.PocketBase) *ImportHandler {
-	return &ImportHandler{app: app}
-}
-
-// validateCSVFile performs security checks on uploaded files
-func validateCSVFile(filename string, size int64) error {
-	// Check file extension
-	ext := strings.ToLower(filepath.Ext(filename))
-	if ext != ".csv" {
-		return fmt.Errorf("invalid file type: %s (only .csv files allowed)", ext)
-	}
-
-	// Check file size
-	if size > maxUploadSize {
-		return fmt.Errorf("file too large: %d bytes (max %d bytes)", size, maxUploadSize)
-	}
-
-	if size == 0 {
-		return fmt.Errorf("empty file")
-	}
-
-	return nil
-}
-
-// saveUploadedFile saves a file to a temporary location with security checks
-func saveUploadedFile(file io.Reader, filename string, size int64) (string, error) {
-	// Validate file
-	if err := validateCSVFile(filename, size); err != nil {
-		return "", err
-	}
-
-	// Create temp file with secure permissions
-	tempFile, err := os.CreateTemp("", "import-*.csv")
-	if err != nil {
-		return "", fmt.Errorf("failed to create temp file: %w", err)
-	}
-	defer tempFile.Close()
-
-	// Set secure permissions (owner read/write only)
-	if err := tempFile.Chmod(0600); err != nil {
-		os.Remove(tempFile.Name())
-		return "", fmt.Errorf("failed to set file permissions: %w", err)
-	}
-
-	// Copy with size limit
-	written, err := io.CopyN(tempFile, file, maxUploadSize+1)
-	if err != nil && err != io.EOF {
-		os.Remove(tempFile.Name())
-		return "", fmt.Errorf("failed to save file: %w", err)
-	}
-
-	if written > maxUploadSize {
-		os.Remove(tempFile.Name())
-		return "", fmt.Errorf("file exceeds maximum size")
-	}
-
-	return tempFile.Name(), nil
-}
-
-func (h *ImportHandler) Import(w http.ResponseWriter, r *http.Request) error {
-	// Limit request size
-	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize*5) // Allow up to 5 files
-
-	// Parse multipart form
-	if err := r.ParseMultipartForm(maxMemory); err != nil {
-		http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
-		return err
-	}
-
-	// Track temporary files for cleanup
-	var tempFiles []string
-	defer func() {
-		for _, f := range tempFiles {
-			os.Remove(f)
-		}
-	}()
-
-	// Map form field names to step names
-	fieldToStep := map[string]string{
-		"companies":           "companies",
-		"roles":               "roles",
-		"contacts":            "contacts",
-		"interviews":          "interviews",
-		"interviews_contacts": "interviews-contacts",
-	}
-
-	// Get import steps in correct order
-	steps := importer.GetImportSteps()
-
-	// Process uploaded files and map them to steps
-	uploadedSteps := make(map[string]string) // step name -> temp file path
-	for fieldName, stepName := range fieldToStep {
-		file, header, err := r.FormFile(fieldName)
-		if err != nil {
-			// File is optional, skip if not provided
-			if err == http.ErrMissingFile {
-				continue
-			}
-			http.Error(w, fmt.Sprintf("Failed to read %s file: %v", fieldName, err), http.StatusBadRequest)
-			return err
-		}
-		defer file.Close()
-
-		// Save file to temp location
-		tempPath, err := saveUploadedFile(file, header.Filename, header.Size)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("Failed to save %s file: %v", fieldName, err), http.StatusBadRequest)
-			return err
-		}
-
-		tempFiles = append(tempFiles, tempPath)
-		uploadedSteps[stepName] = tempPath
-	}
-
-	// Check if at least one file was uploaded
-	if len(uploadedSteps) == 0 {
-		http.Error(w, "No files uploaded", http.StatusBadRequest)
-		return fmt.Errorf("no files uploaded")
-	}
-
-	// Set filepaths for uploaded steps
-	for i := range steps {
-		if tempPath, ok := uploadedSteps[steps[i].Name]; ok {
-			steps[i].Filepath = tempPath
-		}
-	}
-
-	// Import using shared logic (skip missing files)
-	errors := importer.ImportFromSteps(h.app, steps, true)
-
-	// Return response
-	if len(errors) > 0 {
-		var errorMessages []string
-		for _, err := range errors {
-			errorMessages = append(errorMessages, err.Error())
-		}
-		errMsg := "Import completed with errors:\n" + strings.Join(errorMessages, "\n")
-		http.Error(w, errMsg, http.StatusInternalServerError)
-		return fmt.Errorf("%s", errMsg)
-	}
-
-	// Redirect to home page on success
-	w.Header().Set("HX-Redirect", "/")
-	w.WriteHeader(http.StatusOK)
-	return nil
-}
+package handlers
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pocketbase/pocketbase"
+
+	"reverse-ats/internal/importer"
+	"reverse-ats/internal/templates"
+)
+
+const (
+	maxUploadSize = 10 << 20 // 10 MB
+	maxMemory     = 5 << 20  // 5 MB for parsing multipart form
+)
+
+// MaxArchiveSize caps an import upload. An export zip also carries the
+// uploaded files, each up to 10 MB. They are spooled to disk while the form
+// is parsed and never extracted, so only the CSV entries are held to 10 MB.
+const MaxArchiveSize = 1 << 30 // 1 GB
+
+type ImportHandler struct {
+	app *pocketbase.PocketBase
+}
+
+func NewImportHandler(app *pocketbase.PocketBase) *ImportHandler {
+	return &ImportHandler{app: app}
+}
+
+// validateCSVFile performs security checks on uploaded files
+func validateCSVFile(filename string, size int64) error {
+	// Check file extension
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext != ".csv" {
+		return fmt.Errorf("invalid file type: %s (only .csv files allowed)", ext)
+	}
+
+	// Check file size
+	if size > maxUploadSize {
+		return fmt.Errorf("file too large: %d bytes (max %d bytes)", size, maxUploadSize)
+	}
+
+	if size == 0 {
+		return fmt.Errorf("empty file")
+	}
+
+	return nil
+}
+
+// saveUploadedFile saves a file to a temporary location with security checks
+func saveUploadedFile(file io.Reader, filename string, size int64) (string, error) {
+	// Validate file
+	if err := validateCSVFile(filename, size); err != nil {
+		return "", err
+	}
+
+	return writeTempCSV(file)
+}
+
+// writeTempCSV copies a CSV to a temporary file, failing once it grows past maxUploadSize
+func writeTempCSV(file io.Reader) (string, error) {
+	// Create temp file with secure permissions
+	tempFile, err := os.CreateTemp("", "import-*.csv")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer tempFile.Close()
+
+	// Set secure permissions (owner read/write only)
+	if err := tempFile.Chmod(0600); err != nil {
+		os.Remove(tempFile.Name())
+		return "", fmt.Errorf("failed to set file permissions: %w", err)
+	}
+
+	// Copy with size limit
+	written, err := io.CopyN(tempFile, file, maxUploadSize+1)
+	if err != nil && err != io.EOF {
+		os.Remove(tempFile.Name())
+		return "", fmt.Errorf("failed to save file: %w", err)
+	}
+
+	if written > maxUploadSize {
+		os.Remove(tempFile.Name())
+		return "", fmt.Errorf("file exceeds maximum size")
+	}
+
+	return tempFile.Name(), nil
+}
+
+// extractImportZip saves the CSV files of an export zip to temporary files,
+// keyed by step name. Files are found by name anywhere in the zip and
+// everything else, like uploaded documents, is ignored. Each CSV is held to
+// maxUploadSize however far it decompresses, so a zip bomb can't fill the disk.
+func extractImportZip(file multipart.File, header *multipart.FileHeader, steps []importer.ImportStep) (map[string]string, error) {
+	if ext := strings.ToLower(filepath.Ext(header.Filename)); ext != ".zip" {
+		return nil, fmt.Errorf("invalid file type: %s (only .zip files allowed)", ext)
+	}
+	if header.Size == 0 {
+		return nil, fmt.Errorf("empty file")
+	}
+	if header.Size > MaxArchiveSize {
+		return nil, fmt.Errorf("file too large: %d bytes (max %d bytes)", header.Size, MaxArchiveSize)
+	}
+
+	archive, err := zip.NewReader(file, header.Size)
+	if err != nil {
+		return nil, fmt.Errorf("not a valid zip file: %w", err)
+	}
+
+	stepByFilename := make(map[string]string, len(steps))
+	for _, step := range steps {
+		stepByFilename[step.Filename] = step.Name
+	}
+
+	paths := make(map[string]string)
+	for _, entry := range archive.File {
+		stepName, ok := stepByFilename[path.Base(entry.Name)]
+		if !ok || entry.FileInfo().IsDir() {
+			continue
+		}
+		if _, ok := paths[stepName]; ok {
+			removeTempFiles(paths)
+			return nil, fmt.Errorf("zip contains more than one %s", path.Base(entry.Name))
+		}
+
+		// The header can lie about the size, so writeTempCSV checks it again
+		if entry.UncompressedSize64 > maxUploadSize {
+			removeTempFiles(paths)
+			return nil, fmt.Errorf("%s is too large: %d bytes (max %d bytes)", entry.Name, entry.UncompressedSize64, maxUploadSize)
+		}
+
+		tempPath, err := extractZipEntry(entry)
+		if err != nil {
+			removeTempFiles(paths)
+			return nil, fmt.Errorf("%s: %w", entry.Name, err)
+		}
+		paths[stepName] = tempPath
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("zip contains none of the import CSV files")
+	}
+	return paths, nil
+}
+
+// extractZipEntry saves one zip entry to a temporary file
+func extractZipEntry(entry *zip.File) (string, error) {
+	reader, err := entry.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer reader.Close()
+
+	return writeTempCSV(reader)
+}
+
+// removeTempFiles deletes temporary files, keyed by step name
+func removeTempFiles(paths map[string]string) {
+	for _, f := range paths {
+		os.Remove(f)
+	}
+}
+
+func (h *ImportHandler) Import(w http.ResponseWriter, r *http.Request) error {
+	// Limit request size
+	r.Body = http.MaxBytesReader(w, r.Body, MaxArchiveSize) // Allow up to 5 files, or one export zip with its uploaded files
+
+	// Parse multipart form
+	if err := r.ParseMultipartForm(maxMemory); err != nil {
+		http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
+		return err
+	}
+
+	// Track temporary files for cleanup
+	var tempFiles []string
+	defer func() {
+		for _, f := range tempFiles {
+			os.Remove(f)
+		}
+	}()
+
+	// Map form field names to step names
+	fieldToStep := map[string]string{
+		"companies":           "companies",
+		"roles":               "roles",
+		"contacts":            "contacts",
+		"interviews":          "interviews",
+		"interviews_contacts": "interviews-contacts",
+	}
+
+	// Get import steps in correct order
+	steps := importer.GetImportSteps()
+
+	// Process uploaded files and map them to steps
+	uploadedSteps := make(map[string]string) // step name -> temp file path
+
+	// An export zip holds all the CSV files at once
+	archive, archiveHeader, err := r.FormFile("archive")
+	if err == nil {
+		defer archive.Close()
+
+		for fieldName := range fieldToStep {
+			if _, ok := r.MultipartForm.File[fieldName]; ok {
+				http.Error(w, "Upload either an export zip or CSV files, not both", http.StatusBadRequest)
+				return fmt.Errorf("both a zip and CSV files were uploaded")
+			}
+		}
+
+		uploadedSteps, err = extractImportZip(archive, archiveHeader, steps)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to read zip file: %v", err), http.StatusBadRequest)
+			return err
+		}
+		for _, tempPath := range uploadedSteps {
+			tempFiles = append(tempFiles, tempPath)
+		}
+	} else if err != http.ErrMissingFile {
+		http.Error(w, fmt.Sprintf("Failed to read zip file: %v", err), http.StatusBadRequest)
+		return err
+	}
+
+	for fieldName, stepName := range fieldToStep {
+		file, header, err := r.FormFile(fieldName)
+		if err != nil {
+			// File is optional, skip if not provided
+			if err == http.ErrMissingFile {
+				continue
+			}
+			http.Error(w, fmt.Sprintf("Failed to read %s file: %v", fieldName, err), http.StatusBadRequest)
+			return err
+		}
+		defer file.Close()
+
+		// Save file to temp location
+		tempPath, err := saveUploadedFile(file, header.Filename, header.Size)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to save %s file: %v", fieldName, err), http.StatusBadRequest)
+			return err
+		}
+
+		tempFiles = append(tempFiles, tempPath)
+		uploadedSteps[stepName] = tempPath
+	}
+
+	// Check if at least one file was uploaded
+	if len(uploadedSteps) == 0 {
+		http.Error(w, "No files uploaded", http.StatusBadRequest)
+		return fmt.Errorf("no files uploaded")
+	}
+
+	// Set filepaths for uploaded steps
+	for i := range steps {
+		if tempPath, ok := uploadedSteps[steps[i].Name]; ok {
+			steps[i].Filepath = tempPath
+		}
+	}
+
+	// Import using shared logic (skip missing files).
+	// The modal checks the files with a dry run before confirming the import.
+	dryRun := r.FormValue("dry_run") == "true"
+	report, errors := importer.ImportFromSteps(h.app, steps, true, dryRun)
+
+	// The modal shows the per-row report of a dry run, and the counts or
+	// problems of an import
+	if dryRun || r.Header.Get("HX-Request") == "true" {
+		if err := templates.ImportReport(*report).Render(r.Context(), w); err != nil {
+			return err
+		}
+		if len(errors) > 0 && !dryRun {
+			return fmt.Errorf("import failed: %w", errors[0])
+		}
+		return nil
+	}
+
+	// Return response
+	if len(errors) > 0 {
+		var errorMessages []string
+		for _, err := range errors {
+			errorMessages = append(errorMessages, err.Error())
+		}
+		errMsg := "Import completed with errors:\n" + strings.Join(errorMessages, "\n")
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return fmt.Errorf("%s", errMsg)
+	}
+
+	// Redirect to home page on success
+	w.Header().Set("HX-Redirect", "/")
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
@@ -0,0 +1,344 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+
+	"reverse-ats/internal/ical"
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/templates"
+	"reverse-ats/internal/util"
+)
+
+// maxInviteSize caps uploaded invites; real ones are a few KB
+const maxInviteSize = 1 << 20
+
+// NewFromInvite reads an uploaded .ics invite and shows the new interview form
+// pre-filled from its first event. The company, role and participants are
+// guessed from the email addresses of the organizer and attendees.
+func (h *InterviewsHandler) NewFromInvite(w http.ResponseWriter, r *http.Request) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxInviteSize)
+	if err := r.ParseMultipartForm(maxInviteSize); err != nil {
+		http.Error(w, "The invite is too large or the upload failed", http.StatusBadRequest)
+		return err
+	}
+
+	file, header, err := r.FormFile("invite")
+	if err != nil {
+		http.Error(w, "No invite uploaded", http.StatusBadRequest)
+		return err
+	}
+	defer file.Close()
+
+	// Times are shown in the server's time zone, like the rest of the app
+	events, err := ical.Parse(file, time.Local)
+	if err != nil {
+		http.Error(w, "Failed to read invite: "+err.Error(), http.StatusBadRequest)
+		return err
+	}
+	if len(events) == 0 {
+		http.Error(w, "The invite holds no events", http.StatusBadRequest)
+		return fmt.Errorf("no events in %s", header.Filename)
+	}
+
+	interview, contacts, invite, err := h.matchInvite(events[0])
+	if err != nil {
+		http.Error(w, "Failed to match invite", http.StatusInternalServerError)
+		return err
+	}
+	invite.Filename = header.Filename
+
+	return h.renderNewForm(w, r, interview, contacts, invite)
+}
+
+// matchInvite turns an invite event into a new interview, guessing its company
+// from the attendees who are contacts, or else from their email domains
+// against the company URLs. It returns the company's contacts for the form.
+func (h *InterviewsHandler) matchInvite(event ical.Event) (*models.Interview, []models.Contact, *models.InterviewInvite, error) {
+	interview := &models.Interview{Notes: inviteNotes(event)}
+	if !event.Start.IsZero() {
+		interview.Date = event.Start.Format("2006-01-02")
+		if !event.AllDay {
+			interview.Start = event.Start.Format("15:04")
+			if !event.End.IsZero() {
+				interview.End = event.End.Format("15:04")
+			}
+		}
+	}
+	invite := &models.InterviewInvite{Summary: event.Summary}
+
+	attendees := inviteAttendees(event)
+	if len(attendees) == 0 {
+		return interview, nil, invite, nil
+	}
+
+	companyID, err := h.guessInviteCompany(attendees)
+	if err != nil || companyID == "" {
+		return interview, nil, invite, err
+	}
+
+	companyRecord, err := h.app.FindRecordById(util.CollectionCompanies, companyID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	invite.CompanyID = companyID
+	invite.CompanyName = companyRecord.GetString("name")
+
+	roleID, err := h.guessInviteRole(companyID, event.Summary)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	interview.RoleID = roleID
+
+	contacts, err := util.FetchContactsForCompany(h.app, companyID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Attendees at the company's domain, or at the domain of a contact in the
+	// invite, that aren't contacts yet are offered to be added
+	domains := []string{urlHost(companyRecord.GetString("url"))}
+	var newAttendees []ical.Attendee
+	for _, attendee := range attendees {
+		if contact, ok := findContactByEmail(contacts, attendee.Email); ok {
+			interview.ContactIDs = append(interview.ContactIDs, contact.ID)
+			invite.Matched = append(invite.Matched, contact)
+			domains = append(domains, emailDomain(contact.Email))
+		} else {
+			newAttendees = append(newAttendees, attendee)
+		}
+	}
+
+	for _, attendee := range newAttendees {
+		if matchesAnyDomain(emailDomain(attendee.Email), domains) {
+			firstName, lastName := splitName(attendee.Name, attendee.Email)
+			invite.Unmatched = append(invite.Unmatched, models.InviteAttendee{
+				FirstName: firstName,
+				LastName:  lastName,
+				Email:     attendee.Email,
+			})
+		}
+	}
+
+	return interview, contacts, invite, nil
+}
+
+// guessInviteCompany returns the company most attendees belong to, as
+// contacts or by email domain, or "" when none matches
+func (h *InterviewsHandler) guessInviteCompany(attendees []ical.Attendee) (string, error) {
+	contactRecords, err := h.app.FindRecordsByFilter(util.CollectionContacts, "email != ''", "id", -1, 0)
+	if err != nil {
+		return "", err
+	}
+	contactCompanies := make(map[string]string)
+	for _, record := range contactRecords {
+		contactCompanies[strings.ToLower(record.GetString("email"))] = record.GetString("company")
+	}
+
+	var candidates []string
+	votes := make(map[string]int)
+	vote := func(companyID string) {
+		if votes[companyID] == 0 {
+			candidates = append(candidates, companyID)
+		}
+		votes[companyID]++
+	}
+
+	for _, attendee := range attendees {
+		if companyID := contactCompanies[strings.ToLower(attendee.Email)]; companyID != "" {
+			vote(companyID)
+		}
+	}
+
+	if len(candidates) == 0 {
+		companyRecords, err := h.app.FindRecordsByFilter(util.CollectionCompanies, "url != ''", "name", -1, 0)
+		if err != nil {
+			return "", err
+		}
+		for _, record := range companyRecords {
+			host := urlHost(record.GetString("url"))
+			for _, attendee := range attendees {
+				if matchesAnyDomain(emailDomain(attendee.Email), []string{host}) {
+					vote(record.Id)
+				}
+			}
+		}
+	}
+
+	best := ""
+	for _, companyID := range candidates {
+		if votes[companyID] > votes[best] {
+			best = companyID
+		}
+	}
+	return best, nil
+}
+
+// guessInviteRole picks the company's role the invite is most likely for:
+// the only open role, or the open role named in the summary. It returns ""
+// when it can't tell.
+func (h *InterviewsHandler) guessInviteRole(companyID, summary string) (string, error) {
+	roleRecords, err := h.app.FindRecordsByFilter(
+		util.CollectionRoles,
+		"company = {:company}",
+		"name",
+		-1,
+		0,
+		dbx.Params{"company": companyID},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	var open []string
+	named := ""
+	for _, record := range roleRecords {
+		if !models.IsOpenRoleStatus(record.GetString("status")) {
+			continue
+		}
+		open = append(open, record.Id)
+		if name := record.GetString("name"); name != "" && strings.Contains(strings.ToLower(summary), strings.ToLower(name)) {
+			named = record.Id
+		}
+	}
+
+	switch {
+	case len(open) == 1:
+		return open[0], nil
+	case named != "":
+		return named, nil
+	case len(open) == 0 && len(roleRecords) == 1:
+		return roleRecords[0].Id, nil
+	}
+	return "", nil
+}
+
+// renderNewForm renders the new interview form, optionally pre-filled from an invite
+func (h *InterviewsHandler) renderNewForm(w http.ResponseWriter, r *http.Request, interview *models.Interview, contacts []models.Contact, invite *models.InterviewInvite) error {
+	roles, err := util.FetchRolesForDropdown(h.app)
+	if err != nil {
+		http.Error(w, "Failed to fetch roles", http.StatusInternalServerError)
+		return err
+	}
+
+	// List the guessed company's roles first
+	if invite != nil && invite.CompanyID != "" {
+		sort.SliceStable(roles, func(i, j int) bool {
+			return roles[i].CompanyID == invite.CompanyID && roles[j].CompanyID != invite.CompanyID
+		})
+	}
+
+	tags, err := util.FetchTags(h.app)
+	if err != nil {
+		http.Error(w, "Failed to fetch tags", http.StatusInternalServerError)
+		return err
+	}
+
+	return templates.InterviewFormNew(interview, roles, contacts, tags, invite).Render(r.Context(), w)
+}
+
+// inviteAttendees returns the organizer and attendees that have an email, once each
+func inviteAttendees(event ical.Event) []ical.Attendee {
+	seen := make(map[string]bool)
+	var attendees []ical.Attendee
+	for _, attendee := range append([]ical.Attendee{event.Organizer}, event.Attendees...) {
+		email := strings.ToLower(strings.TrimSpace(attendee.Email))
+		if email == "" || seen[email] {
+			continue
+		}
+		seen[email] = true
+		attendee.Email = strings.TrimSpace(attendee.Email)
+		attendees = append(attendees, attendee)
+	}
+	return attendees
+}
+
+// inviteNotes keeps the summary, location and description of an invite as interview notes
+func inviteNotes(event ical.Event) string {
+	var parts []string
+	if event.Summary != "" {
+		parts = append(parts, event.Summary)
+	}
+	if event.Location != "" {
+		parts = append(parts, "Location: "+event.Location)
+	}
+	if description := strings.TrimSpace(event.Description); description != "" {
+		parts = append(parts, description)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// findContactByEmail finds a contact by email address, ignoring case
+func findContactByEmail(contacts []models.Contact, email string) (models.Contact, bool) {
+	for _, contact := range contacts {
+		if contact.Email != "" && strings.EqualFold(contact.Email, email) {
+			return contact, true
+		}
+	}
+	return models.Contact{}, false
+}
+
+// splitName splits an attendee name, written "First Last" or "Last, First",
+// into first and last name. Calendars often use the email address as the
+// name, which is no name at all.
+func splitName(name, email string) (string, string) {
+	fields := strings.Fields(name)
+	if len(fields) == 0 || strings.EqualFold(name, email) {
+		return "", ""
+	}
+	if last, first, ok := strings.Cut(name, ","); ok {
+		return strings.TrimSpace(first), strings.TrimSpace(last)
+	}
+	if len(fields) == 1 {
+		return fields[0], ""
+	}
+	return strings.Join(fields[:len(fields)-1], " "), fields[len(fields)-1]
+}
+
+// emailDomain returns the lowercase domain of an email address
+func emailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(email[at+1:])
+}
+
+// urlHost returns the lowercase host of a company URL without "www."
+func urlHost(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return ""
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// matchesAnyDomain reports whether an email domain is one of the given
+// domains or a subdomain of one, or the other way around
+func matchesAnyDomain(domain string, domains []string) bool {
+	if domain == "" {
+		return false
+	}
+	for _, d := range domains {
+		if d == "" {
+			continue
+		}
+		if domain == d || strings.HasSuffix(domain, "."+d) || strings.HasSuffix(d, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,449 +1,655 @@
-package handlers
-
-import (
-	"fmt"
-	"net/http"
-	"sort"
-	"strings"
-	"time"
-
-	"github.com/pocketbase/pocketbase"
-	"github.com/pocketbase/pocketbase/core"
-
-	"reverse-ats/internal/models"
-	"reverse-ats/internal/templates"
-	"reverse-ats/internal/util"
-)
-
-type InterviewsHandler struct {
-	app *pocketbase.PocketBase
-}
-
-func NewInterviewsHandler(app *pocketbase.PocketBase) *InterviewsHandler {
-	return &InterviewsHandler{app: app}
-}
-
-// convertTimeTo24Hour converts 12-hour time format (e.g., "12:00 PM") to 24-hour format (e.g., "12:00")
-func convertTimeTo24Hour(timeStr string) string {
-	if timeStr == "" {
-		return ""
-	}
-
-	// Try to parse as 12-hour format first
-	formats := []string{
-		"3:04 PM",
-		"03:04 PM",
-		"15:04",    // Already 24-hour
-		"3:04",     // Already 24-hour without leading zero
-	}
-
-	for _, format := range formats {
-		if t, err := time.Parse(format, timeStr); err == nil {
-			// Return in 24-hour format HH:MM
-			return t.Format("15:04")
-		}
-	}
-
-	// If all parsing fails, return original
-	return timeStr
-}
-
-func recordToInterview(record *core.Record) models.Interview {
-	// Extract date properly from DateField
-	dateValue := ""
-	if dt := record.GetDateTime("date"); !dt.IsZero() {
-		// Format as YYYY-MM-DD for HTML date input
-		dateValue = dt.Time().Format("2006-01-02")
-	}
-
-	// Convert times to 24-hour format for HTML time inputs
-	startTime := convertTimeTo24Hour(record.GetString("start"))
-	endTime := convertTimeTo24Hour(record.GetString("end"))
-
-	interview := models.Interview{
-		ID:        record.Id,
-		RoleID:    record.GetString("role"),
-		Date:      dateValue,
-		Start:     startTime,
-		End:       endTime,
-		Notes:     record.GetString("notes"),
-		Type:      record.GetString("type"),
-		CreatedAt: record.GetDateTime("created").String(),
-		UpdatedAt: record.GetDateTime("updated").String(),
-	}
-
-	// Get role and company info from expanded relation
-	if roleRecord := record.ExpandedOne("role"); roleRecord != nil {
-		interview.RoleName = roleRecord.GetString("name")
-		// Get company from role's company relation
-		companyID := roleRecord.GetString("company")
-		interview.CompanyID = companyID
-	}
-
-	return interview
-}
-
-func sortInterviewsByCompanyName(interviews []models.Interview, order string) {
-	sort.Slice(interviews, func(i, j int) bool {
-		cmpResult := strings.Compare(
-			strings.ToLower(interviews[i].CompanyName),
-			strings.ToLower(interviews[j].CompanyName),
-		)
-		if order == "desc" {
-			return cmpResult > 0
-		}
-		return cmpResult < 0
-	})
-}
-
-func (h *InterviewsHandler) List(w http.ResponseWriter, r *http.Request) error {
-	sortBy := r.URL.Query().Get("sort")
-	order := r.URL.Query().Get("order")
-
-	// Validate sort field
-	validSortFields := map[string]bool{
-		"company_name": true,
-		"date":         true,
-	}
-
-	if sortBy == "" || !validSortFields[sortBy] {
-		sortBy = "date"
-	}
-
-	if order != "asc" && order != "desc" {
-		order = "desc"
-	}
-
-	// For company_name sorting, we need to fetch all and sort in memory
-	// For other fields, we can use PocketBase's native sorting
-	var sortField string
-	doInMemorySort := (sortBy == "company_name")
-
-	if !doInMemorySort {
-		sortField = sortBy
-		if order == "desc" {
-			sortField = "-" + sortBy
-		}
-	} else {
-		// No sort for in-memory sorting
-		sortField = ""
-	}
-
-	// Fetch interviews
-	records, err := h.app.FindRecordsByFilter(
-		"interviews",
-		"",
-		sortField,
-		-1, // all records
-		0,
-	)
-	if err != nil {
-		http.Error(w, "Failed to fetch interviews", http.StatusInternalServerError)
-		return err
-	}
-
-	// Convert records to Interview structs and fetch role/company info
-	// Fetch all roles and companies once to avoid N+1 queries
-	rolesMap, err := util.FetchRolesMap(h.app)
-	if err != nil {
-		http.Error(w, "Failed to fetch roles", http.StatusInternalServerError)
-		return err
-	}
-
-	companiesMap, err := util.FetchCompaniesMap(h.app)
-	if err != nil {
-		http.Error(w, "Failed to fetch companies", http.StatusInternalServerError)
-		return err
-	}
-
-	interviews := make([]models.Interview, len(records))
-	for i, record := range records {
-		interview := recordToInterview(record)
-
-		// Look up role and company info from maps
-		if roleID := record.GetString("role"); roleID != "" {
-			if roleInfo, ok := rolesMap[roleID]; ok {
-				interview.RoleName = roleInfo.Name
-				interview.CompanyID = roleInfo.CompanyID
-
-				// Look up company name
-				if companyName, ok := companiesMap[roleInfo.CompanyID]; ok {
-					interview.CompanyName = companyName
-				}
-			}
-		}
-		interviews[i] = interview
-	}
-
-	// Sort in memory if needed
-	if doInMemorySort {
-		sortInterviewsByCompanyName(interviews, order)
-	}
-
-	// Fetch roles with company info for inline form dropdown
-	roleRecords, err := h.app.FindRecordsByFilter(util.CollectionRoles, "", "name", -1, 0)
-	if err != nil {
-		http.Error(w, "Failed to fetch roles", http.StatusInternalServerError)
-		return err
-	}
-
-	// Convert to Role format with company names (reuse companiesMap)
-	roles := make([]models.Role, len(roleRecords))
-	for i, record := range roleRecords {
-		role := models.Role{
-			ID:        record.Id,
-			Name:      record.GetString("name"),
-			CompanyID: record.GetString("company"),
-		}
-
-		// Look up company name from map
-		if companyID := record.GetString("company"); companyID != "" {
-			if companyName, ok := companiesMap[companyID]; ok {
-				role.CompanyName = companyName
-			}
-		}
-		roles[i] = role
-	}
-
-	// Fetch companies for dropdown
-	companies, err := util.FetchCompaniesForDropdown(h.app)
-	if err != nil {
-		http.Error(w, "Failed to fetch companies", http.StatusInternalServerError)
-		return err
-	}
-
-	return templates.InterviewsList(interviews, sortBy, order, companies).Render(r.Context(), w)
-}
-
-func (h *InterviewsHandler) New(w http.ResponseWriter, r *http.Request) error {
-	// Fetch roles with company info for dropdown
-	roleRecords, err := h.app.FindRecordsByFilter(util.CollectionRoles, "", "name", -1, 0)
-	if err != nil {
-		http.Error(w, "Failed to fetch roles", http.StatusInternalServerError)
-		return err
-	}
-
-	// Convert to RoleWithCompany format
-	roles := make([]models.Role, len(roleRecords))
-	for i, record := range roleRecords {
-		role := models.Role{
-			ID:   record.Id,
-			Name: record.GetString("name"),
-			CompanyID: record.GetString("company"),
-		}
-
-		// Fetch company name
-		if companyID := record.GetString("company"); companyID != "" {
-			if companyRecord, err := h.app.FindRecordById(util.CollectionCompanies, companyID); err == nil {
-				role.CompanyName = companyRecord.GetString("name")
-			}
-		}
-		roles[i] = role
-	}
-
-	return templates.InterviewFormNew(roles).Render(r.Context(), w)
-}
-
-func (h *InterviewsHandler) Create(w http.ResponseWriter, r *http.Request) error {
-	if err := r.ParseForm(); err != nil {
-		http.Error(w, "Invalid form data", http.StatusBadRequest)
-		return err
-	}
-
-	collection, err := h.app.FindCollectionByNameOrId(util.CollectionInterviews)
-	if err != nil {
-		http.Error(w, "Failed to find collection", http.StatusInternalServerError)
-		return err
-	}
-
-	record := core.NewRecord(collection)
-	record.Set("role", r.FormValue("role"))
-	record.Set("date", r.FormValue("date"))
-	record.Set("start", r.FormValue("start"))
-	record.Set("end", r.FormValue("end"))
-	record.Set("notes", r.FormValue("notes"))
-	record.Set("type", r.FormValue("type"))
-	// TODO: Handle contacts many-to-many relationship
-
-	if err := h.app.Save(record); err != nil {
-		http.Error(w, "Failed to create interview", http.StatusInternalServerError)
-		return err
-	}
-
-	// If HTMX request, return just the new row
-	if r.Header.Get("HX-Request") == "true" {
-		interview := recordToInterview(record)
-
-		// Fetch role to get role name and company info
-		if roleID := record.GetString("role"); roleID != "" {
-			if roleRecord, err := h.app.FindRecordById(util.CollectionRoles, roleID); err == nil {
-				interview.RoleName = roleRecord.GetString("name")
-				interview.CompanyID = roleRecord.GetString("company")
-
-				// Fetch company name
-				if companyID := roleRecord.GetString("company"); companyID != "" {
-					if companyRecord, err := h.app.FindRecordById(util.CollectionCompanies, companyID); err == nil {
-						interview.CompanyName = companyRecord.GetString("name")
-					}
-				}
-			}
-		}
-
-		return templates.InterviewRow(interview).Render(r.Context(), w)
-	}
-
-	// Otherwise redirect
-	http.Redirect(w, r, "/interviews", http.StatusSeeOther)
-	return nil
-}
-
-func (h *InterviewsHandler) Edit(w http.ResponseWriter, r *http.Request) error {
-	// Extract ID from URL path parameter
-	id := r.PathValue("id")
-	if id == "" {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
-		return fmt.Errorf("missing id parameter")
-	}
-
-	record, err := h.app.FindRecordById(util.CollectionInterviews, id)
-	if err != nil {
-		http.Error(w, "Interview not found", http.StatusNotFound)
-		return err
-	}
-
-	interview := recordToInterview(record)
-
-	// Fetch role info for display
-	if roleID := record.GetString("role"); roleID != "" {
-		if roleRecord, err := h.app.FindRecordById(util.CollectionRoles, roleID); err == nil {
-			interview.RoleName = roleRecord.GetString("name")
-		}
-	}
-
-	// Fetch roles with company info for dropdown
-	roleRecords, err := h.app.FindRecordsByFilter(util.CollectionRoles, "", "name", -1, 0)
-	if err != nil {
-		http.Error(w, "Failed to fetch roles", http.StatusInternalServerError)
-		return err
-	}
-
-	// Convert to Role format with company names
-	roles := make([]models.Role, len(roleRecords))
-	for i, rec := range roleRecords {
-		role := models.Role{
-			ID:   rec.Id,
-			Name: rec.GetString("name"),
-			CompanyID: rec.GetString("company"),
-		}
-
-		// Fetch company name
-		if companyID := rec.GetString("company"); companyID != "" {
-			if companyRecord, err := h.app.FindRecordById(util.CollectionCompanies, companyID); err == nil {
-				role.CompanyName = companyRecord.GetString("name")
-			}
-		}
-		roles[i] = role
-	}
-
-	return templates.InterviewFormEdit(interview, roles).Render(r.Context(), w)
-}
-
-func (h *InterviewsHandler) Update(w http.ResponseWriter, r *http.Request) error {
-	// Extract ID from URL path parameter
-	id := r.PathValue("id")
-	if id == "" {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
-		return fmt.Errorf("missing id parameter")
-	}
-
-	if err := r.ParseForm(); err != nil {
-		http.Error(w, "Invalid form data", http.StatusBadRequest)
-		return err
-	}
-
-	record, err := h.app.FindRecordById(util.CollectionInterviews, id)
-	if err != nil {
-		http.Error(w, "Interview not found", http.StatusNotFound)
-		return err
-	}
-
-	record.Set("role", r.FormValue("role_id"))
-	record.Set("date", r.FormValue("date"))
-	record.Set("start", r.FormValue("start"))
-	record.Set("end", r.FormValue("end"))
-	record.Set("notes", r.FormValue("notes"))
-	record.Set("type", r.FormValue("type"))
-	// TODO: Handle contacts many-to-many relationship
-
-	if err := h.app.Save(record); err != nil {
-		http.Error(w, "Failed to update interview", http.StatusInternalServerError)
-		return err
-	}
-
-	http.Redirect(w, r, "/interviews", http.StatusSeeOther)
-	return nil
-}
-
-func (h *InterviewsHandler) Delete(w http.ResponseWriter, r *http.Request) error {
-	// Extract ID from URL path parameter
-	id := r.PathValue("id")
-	if id == "" {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
-		return fmt.Errorf("missing id parameter")
-	}
-
-	record, err := h.app.FindRecordById(util.CollectionInterviews, id)
-	if err != nil {
-		http.Error(w, "Interview not found", http.StatusNotFound)
-		return err
-	}
-
-	if err := h.app.Delete(record); err != nil {
-		http.Error(w, "Failed to delete interview", http.StatusInternalServerError)
-		return err
-	}
-
-	// If HTMX request, return empty response (row will be removed)
-	if r.Header.Get("HX-Request") == "true" {
-		w.WriteHeader(http.StatusOK)
-		return nil
-	}
-
-	// Otherwise redirect
-	http.Redirect(w, r, "/interviews", http.StatusSeeOther)
-	return nil
-}
-
-// GetRolesByCompany returns role options for a selected company
-func (h *InterviewsHandler) GetRolesByCompany(w http.ResponseWriter, r *http.Request) error {
-	companyID := r.URL.Query().Get("company")
-	if companyID == "" {
-		w.Write([]byte(`<option value="">Select company first</option>`))
-		return nil
-	}
-
-	// Validate that company ID exists to prevent injection
-	_, err := h.app.FindRecordById(util.CollectionCompanies, companyID)
-	if err != nil {
-		w.Write([]byte(`<option value="">Invalid company</option>`))
-		return nil
-	}
-
-	// Fetch roles for this company using safe filter syntax
-	filter := fmt.Sprintf("company='%s'", companyID)
-	roleRecords, err := h.app.FindRecordsByFilter(util.CollectionRoles, filter, "name", -1, 0)
-	if err != nil {
-		http.Error(w, "Failed to fetch roles", http.StatusInternalServerError)
-		return err
-	}
-
-	// Build HTML options
-	html := `<option value="">Select Role *</option>`
-	for _, record := range roleRecords {
-		html += fmt.Sprintf(`<option value="%s">%s</option>`, record.Id, record.GetString("name"))
-	}
-
-	w.Header().Set("Content-Type", "text/html")
-	w.Write([]byte(html))
-	return nil
-}
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/templates"
+	"reverse-ats/internal/util"
+)
+
+type InterviewsHandler struct {
+	app *pocketbase.PocketBase
+}
+
+func NewInterviewsHandler(app *pocketbase.PocketBase) *InterviewsHandler {
+	return &InterviewsHandler{app: app}
+}
+
+// convertTimeTo24Hour converts 12-hour time format (e.g., "12:00 PM") to 24-hour format (e.g., "12:00")
+func convertTimeTo24Hour(timeStr string) string {
+	if timeStr == "" {
+		return ""
+	}
+
+	// Try to parse as 12-hour format first
+	formats := []string{
+		"3:04 PM",
+		"03:04 PM",
+		"15:04", // Already 24-hour
+		"3:04",  // Already 24-hour without leading zero
+	}
+
+	for _, format := range formats {
+		if t, err := time.Parse(format, timeStr); err == nil {
+			// Return in 24-hour format HH:MM
+			return t.Format("15:04")
+		}
+	}
+
+	// If all parsing fails, return original
+	return timeStr
+}
+
+func recordToInterview(record *core.Record) models.Interview {
+	// Extract date properly from DateField
+	dateValue := ""
+	if dt := record.GetDateTime("date"); !dt.IsZero() {
+		// Format as YYYY-MM-DD for HTML date input
+		dateValue = dt.Time().Format("2006-01-02")
+	}
+
+	// Convert times to 24-hour format for HTML time inputs
+	startTime := convertTimeTo24Hour(record.GetString("start"))
+	endTime := convertTimeTo24Hour(record.GetString("end"))
+
+	interview := models.Interview{
+		ID:        record.Id,
+		RoleID:    record.GetString("role"),
+		Date:      dateValue,
+		Start:     startTime,
+		End:       endTime,
+		Notes:     record.GetString("notes"),
+		Type:      record.GetString("type"),
+		Tags:      recordTags(record),
+		CreatedAt: record.GetDateTime("created").String(),
+		UpdatedAt: record.GetDateTime("updated").String(),
+	}
+
+	// Get role and company info from expanded relation
+	if roleRecord := record.ExpandedOne("role"); roleRecord != nil {
+		interview.RoleName = roleRecord.GetString("name")
+		// Get company from role's company relation
+		companyID := roleRecord.GetString("company")
+		interview.CompanyID = companyID
+	}
+
+	// Get participants from expanded contacts relation
+	interview.ContactIDs = record.GetStringSlice("contacts")
+	for _, contactRecord := range record.ExpandedAll("contacts") {
+		interview.Contacts = append(interview.Contacts, recordToContact(contactRecord))
+	}
+
+	return interview
+}
+
+// selectedContacts returns the contact ids submitted with an interview form.
+// Every contact must belong to the company of the interview's role.
+func (h *InterviewsHandler) selectedContacts(r *http.Request, roleID string) ([]string, error) {
+	return interviewContacts(h.app, r.Form["contacts"], roleID)
+}
+
+// interviewContacts returns the given contact ids without blanks and
+// duplicates, after checking they all belong to the company of the role
+func interviewContacts(app core.App, ids []string, roleID string) ([]string, error) {
+	contactIDs := []string{}
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			contactIDs = append(contactIDs, id)
+		}
+	}
+	if len(contactIDs) == 0 {
+		return contactIDs, nil
+	}
+
+	roleRecord, err := app.FindRecordById(util.CollectionRoles, roleID)
+	if err != nil {
+		return nil, fmt.Errorf("role not found")
+	}
+	companyID := roleRecord.GetString("company")
+
+	contactRecords, err := app.FindRecordsByIds(util.CollectionContacts, contactIDs)
+	if err != nil || len(contactRecords) != len(contactIDs) {
+		return nil, fmt.Errorf("contact not found")
+	}
+	for _, contactRecord := range contactRecords {
+		if contactRecord.GetString("company") != companyID {
+			return nil, fmt.Errorf("contact %s does not belong to the role's company", contactRecord.Id)
+		}
+	}
+
+	return contactIDs, nil
+}
+
+// companyForRequest resolves the company used to filter contacts, either
+// directly from the company query param or through the selected role
+func (h *InterviewsHandler) companyForRequest(r *http.Request) string {
+	if companyID := r.FormValue("company"); companyID != "" {
+		if _, err := h.app.FindRecordById(util.CollectionCompanies, companyID); err == nil {
+			return companyID
+		}
+		return ""
+	}
+
+	if roleID := r.FormValue("role"); roleID != "" {
+		if roleRecord, err := h.app.FindRecordById(util.CollectionRoles, roleID); err == nil {
+			return roleRecord.GetString("company")
+		}
+	}
+
+	return ""
+}
+
+func sortInterviewsByCompanyName(interviews []models.Interview, order string) {
+	sort.Slice(interviews, func(i, j int) bool {
+		cmpResult := strings.Compare(
+			strings.ToLower(interviews[i].CompanyName),
+			strings.ToLower(interviews[j].CompanyName),
+		)
+		if order == "desc" {
+			return cmpResult > 0
+		}
+		return cmpResult < 0
+	})
+}
+
+func (h *InterviewsHandler) List(w http.ResponseWriter, r *http.Request) error {
+	sortBy := r.URL.Query().Get("sort")
+	order := r.URL.Query().Get("order")
+
+	// Validate sort field
+	validSortFields := map[string]bool{
+		"company_name": true,
+		"date":         true,
+	}
+
+	if sortBy == "" || !validSortFields[sortBy] {
+		sortBy = "date"
+	}
+
+	if order != "asc" && order != "desc" {
+		order = "desc"
+	}
+
+	// For company_name sorting, we need to fetch all and sort in memory
+	// For other fields, we can use PocketBase's native sorting
+	var sortField string
+	doInMemorySort := (sortBy == "company_name")
+
+	if !doInMemorySort {
+		sortField = sortBy
+		if order == "desc" {
+			sortField = "-" + sortBy
+		}
+	} else {
+		// No sort for in-memory sorting
+		sortField = ""
+	}
+
+	// Narrow down to the ?tag= filter, if any
+	filter, params, tagFilter, err := listTagFilter(h.app, r)
+	if err != nil {
+		http.Error(w, "Failed to fetch tags", http.StatusInternalServerError)
+		return err
+	}
+
+	// Fetch interviews
+	records, err := h.app.FindRecordsByFilter(
+		"interviews",
+		filter,
+		sortField,
+		-1, // all records
+		0,
+		params,
+	)
+	if err != nil {
+		http.Error(w, "Failed to fetch interviews", http.StatusInternalServerError)
+		return err
+	}
+
+	// Expand participants and tags in one query per relation
+	if errs := h.app.ExpandRecords(records, []string{"contacts", "tags"}, nil); len(errs) > 0 {
+		http.Error(w, "Failed to fetch contacts", http.StatusInternalServerError)
+		return fmt.Errorf("failed to expand contacts: %v", errs)
+	}
+
+	// Convert records to Interview structs and fetch role/company info
+	// Fetch all roles and companies once to avoid N+1 queries
+	rolesMap, err := util.FetchRolesMap(h.app)
+	if err != nil {
+		http.Error(w, "Failed to fetch roles", http.StatusInternalServerError)
+		return err
+	}
+
+	companiesMap, err := util.FetchCompaniesMap(h.app)
+	if err != nil {
+		http.Error(w, "Failed to fetch companies", http.StatusInternalServerError)
+		return err
+	}
+
+	interviews := make([]models.Interview, len(records))
+	for i, record := range records {
+		interview := recordToInterview(record)
+
+		// Look up role and company info from maps
+		if roleID := record.GetString("role"); roleID != "" {
+			if roleInfo, ok := rolesMap[roleID]; ok {
+				interview.RoleName = roleInfo.Name
+				interview.CompanyID = roleInfo.CompanyID
+
+				// Look up company name
+				if companyName, ok := companiesMap[roleInfo.CompanyID]; ok {
+					interview.CompanyName = companyName
+				}
+			}
+		}
+		interviews[i] = interview
+	}
+
+	// Sort in memory if needed
+	if doInMemorySort {
+		sortInterviewsByCompanyName(interviews, order)
+	}
+
+	// Fetch roles with company info for inline form dropdown
+	roleRecords, err := h.app.FindRecordsByFilter(util.CollectionRoles, "", "name", -1, 0)
+	if err != nil {
+		http.Error(w, "Failed to fetch roles", http.StatusInternalServerError)
+		return err
+	}
+
+	// Convert to Role format with company names (reuse companiesMap)
+	roles := make([]models.Role, len(roleRecords))
+	for i, record := range roleRecords {
+		role := models.Role{
+			ID:        record.Id,
+			Name:      record.GetString("name"),
+			CompanyID: record.GetString("company"),
+		}
+
+		// Look up company name from map
+		if companyID := record.GetString("company"); companyID != "" {
+			if companyName, ok := companiesMap[companyID]; ok {
+				role.CompanyName = companyName
+			}
+		}
+		roles[i] = role
+	}
+
+	// Fetch companies for dropdown
+	companies, err := util.FetchCompaniesForDropdown(h.app)
+	if err != nil {
+		http.Error(w, "Failed to fetch companies", http.StatusInternalServerError)
+		return err
+	}
+
+	return templates.InterviewsList(interviews, sortBy, order, companies, tagFilter).Render(r.Context(), w)
+}
+
+func (h *InterviewsHandler) New(w http.ResponseWriter, r *http.Request) error {
+	return h.renderNewForm(w, r, nil, nil, nil)
+}
+
+func (h *InterviewsHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return err
+	}
+
+	collection, err := h.app.FindCollectionByNameOrId(util.CollectionInterviews)
+	if err != nil {
+		http.Error(w, "Failed to find collection", http.StatusInternalServerError)
+		return err
+	}
+
+	contactIDs, err := h.selectedContacts(r, r.FormValue("role"))
+	if err != nil {
+		http.Error(w, "Invalid contacts: "+err.Error(), http.StatusBadRequest)
+		return err
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("role", r.FormValue("role"))
+	record.Set("date", r.FormValue("date"))
+	record.Set("start", r.FormValue("start"))
+	record.Set("end", r.FormValue("end"))
+	record.Set("notes", r.FormValue("notes"))
+	record.Set("type", models.NormalizeInterviewType(r.FormValue("type")))
+	record.Set("contacts", contactIDs)
+	if err := setRecordTags(h.app, r, record); err != nil {
+		http.Error(w, "Failed to save tags", http.StatusInternalServerError)
+		return err
+	}
+
+	if err := h.app.Save(record); err != nil {
+		http.Error(w, "Failed to create interview", http.StatusInternalServerError)
+		return err
+	}
+
+	// If HTMX request, return just the new row
+	if r.Header.Get("HX-Request") == "true" {
+		h.app.ExpandRecord(record, []string{"contacts", "tags"}, nil)
+		interview := recordToInterview(record)
+
+		// Fetch role to get role name and company info
+		if roleID := record.GetString("role"); roleID != "" {
+			if roleRecord, err := h.app.FindRecordById(util.CollectionRoles, roleID); err == nil {
+				interview.RoleName = roleRecord.GetString("name")
+				interview.CompanyID = roleRecord.GetString("company")
+
+				// Fetch company name
+				if companyID := roleRecord.GetString("company"); companyID != "" {
+					if companyRecord, err := h.app.FindRecordById(util.CollectionCompanies, companyID); err == nil {
+						interview.CompanyName = companyRecord.GetString("name")
+					}
+				}
+			}
+		}
+
+		return templates.InterviewRow(interview).Render(r.Context(), w)
+	}
+
+	// Otherwise redirect
+	http.Redirect(w, r, "/interviews", http.StatusSeeOther)
+	return nil
+}
+
+func (h *InterviewsHandler) Edit(w http.ResponseWriter, r *http.Request) error {
+	// Extract ID from URL path parameter
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	record, err := h.app.FindRecordById(util.CollectionInterviews, id)
+	if err != nil {
+		http.Error(w, "Interview not found", http.StatusNotFound)
+		return err
+	}
+
+	h.app.ExpandRecord(record, []string{"tags"}, nil)
+	interview := recordToInterview(record)
+
+	// Fetch role info for display
+	if roleID := record.GetString("role"); roleID != "" {
+		if roleRecord, err := h.app.FindRecordById(util.CollectionRoles, roleID); err == nil {
+			interview.RoleName = roleRecord.GetString("name")
+			interview.CompanyID = roleRecord.GetString("company")
+		}
+	}
+
+	// Fetch contacts of the interview's company for the participants select
+	contacts, err := util.FetchContactsForCompany(h.app, interview.CompanyID)
+	if err != nil {
+		http.Error(w, "Failed to fetch contacts", http.StatusInternalServerError)
+		return err
+	}
+
+	// Fetch roles with company info for dropdown
+	roleRecords, err := h.app.FindRecordsByFilter(util.CollectionRoles, "", "name", -1, 0)
+	if err != nil {
+		http.Error(w, "Failed to fetch roles", http.StatusInternalServerError)
+		return err
+	}
+
+	// Convert to Role format with company names
+	roles := make([]models.Role, len(roleRecords))
+	for i, rec := range roleRecords {
+		role := models.Role{
+			ID:        rec.Id,
+			Name:      rec.GetString("name"),
+			CompanyID: rec.GetString("company"),
+		}
+
+		// Fetch company name
+		if companyID := rec.GetString("company"); companyID != "" {
+			if companyRecord, err := h.app.FindRecordById(util.CollectionCompanies, companyID); err == nil {
+				role.CompanyName = companyRecord.GetString("name")
+			}
+		}
+		roles[i] = role
+	}
+
+	tags, err := util.FetchTags(h.app)
+	if err != nil {
+		http.Error(w, "Failed to fetch tags", http.StatusInternalServerError)
+		return err
+	}
+
+	return templates.InterviewFormEdit(interview, roles, contacts, tags).Render(r.Context(), w)
+}
+
+func (h *InterviewsHandler) Update(w http.ResponseWriter, r *http.Request) error {
+	// Extract ID from URL path parameter
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return err
+	}
+
+	record, err := h.app.FindRecordById(util.CollectionInterviews, id)
+	if err != nil {
+		http.Error(w, "Interview not found", http.StatusNotFound)
+		return err
+	}
+
+	contactIDs, err := h.selectedContacts(r, r.FormValue("role"))
+	if err != nil {
+		http.Error(w, "Invalid contacts: "+err.Error(), http.StatusBadRequest)
+		return err
+	}
+
+	record.Set("role", r.FormValue("role"))
+	record.Set("date", r.FormValue("date"))
+	record.Set("start", r.FormValue("start"))
+	record.Set("end", r.FormValue("end"))
+	record.Set("notes", r.FormValue("notes"))
+	record.Set("type", models.NormalizeInterviewType(r.FormValue("type")))
+	record.Set("contacts", contactIDs)
+	if err := setRecordTags(h.app, r, record); err != nil {
+		http.Error(w, "Failed to save tags", http.StatusInternalServerError)
+		return err
+	}
+
+	if err := h.app.Save(record); err != nil {
+		http.Error(w, "Failed to update interview", http.StatusInternalServerError)
+		return err
+	}
+
+	http.Redirect(w, r, "/interviews", http.StatusSeeOther)
+	return nil
+}
+
+func (h *InterviewsHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	// Extract ID from URL path parameter
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	record, err := h.app.FindRecordById(util.CollectionInterviews, id)
+	if err != nil {
+		http.Error(w, "Interview not found", http.StatusNotFound)
+		return err
+	}
+
+	if err := h.app.Delete(record); err != nil {
+		http.Error(w, "Failed to delete interview", http.StatusInternalServerError)
+		return err
+	}
+
+	// If HTMX request, return empty response (row will be removed)
+	if r.Header.Get("HX-Request") == "true" {
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+
+	// Otherwise redirect
+	http.Redirect(w, r, "/interviews", http.StatusSeeOther)
+	return nil
+}
+
+// DownloadICS sends a single interview as an .ics file to add to a calendar
+func (h *InterviewsHandler) DownloadICS(w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	events, err := interviewEvents(h.app, r, "id = {:id}", dbx.Params{"id": id})
+	if err != nil {
+		http.Error(w, "Failed to fetch interview", http.StatusInternalServerError)
+		return err
+	}
+	if len(events) == 0 {
+		http.Error(w, "Interview not found", http.StatusNotFound)
+		return fmt.Errorf("interview %s not found", id)
+	}
+
+	return writeICS(w, fmt.Sprintf("interview-%s.ics", id), events)
+}
+
+// GetRolesByCompany returns role options for a selected company
+func (h *InterviewsHandler) GetRolesByCompany(w http.ResponseWriter, r *http.Request) error {
+	companyID := r.URL.Query().Get("company")
+	if companyID == "" {
+		w.Write([]byte(`<option value="">Select company first</option>`))
+		return nil
+	}
+
+	// Validate that company ID exists to prevent injection
+	_, err := h.app.FindRecordById(util.CollectionCompanies, companyID)
+	if err != nil {
+		w.Write([]byte(`<option value="">Invalid company</option>`))
+		return nil
+	}
+
+	// Fetch roles for this company using safe filter syntax
+	filter := fmt.Sprintf("company='%s'", companyID)
+	roleRecords, err := h.app.FindRecordsByFilter(util.CollectionRoles, filter, "name", -1, 0)
+	if err != nil {
+		http.Error(w, "Failed to fetch roles", http.StatusInternalServerError)
+		return err
+	}
+
+	// Build HTML options
+	html := `<option value="">Select Role *</option>`
+	for _, record := range roleRecords {
+		html += fmt.Sprintf(`<option value="%s">%s</option>`, record.Id, record.GetString("name"))
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	w.Write([]byte(html))
+	return nil
+}
+
+// GetContactsByCompany returns participant options for the company of the
+// selected company or role, keeping the already selected contacts selected
+func (h *InterviewsHandler) GetContactsByCompany(w http.ResponseWriter, r *http.Request) error {
+	companyID := h.companyForRequest(r)
+	if companyID == "" {
+		return templates.InterviewContactOptions(nil, nil).Render(r.Context(), w)
+	}
+
+	contacts, err := util.FetchContactsForCompany(h.app, companyID)
+	if err != nil {
+		http.Error(w, "Failed to fetch contacts", http.StatusInternalServerError)
+		return err
+	}
+
+	return templates.InterviewContactOptions(contacts, r.URL.Query()["contacts"]).Render(r.Context(), w)
+}
+
+// CreateContact creates a contact from the interview form and returns the
+// refreshed participant options with the new contact selected
+func (h *InterviewsHandler) CreateContact(w http.ResponseWriter, r *http.Request) error {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return err
+	}
+
+	companyID := h.companyForRequest(r)
+	if companyID == "" {
+		http.Error(w, "Select a role first", http.StatusBadRequest)
+		return fmt.Errorf("missing company for new contact")
+	}
+
+	firstName := strings.TrimSpace(r.FormValue("new_contact_first_name"))
+	lastName := strings.TrimSpace(r.FormValue("new_contact_last_name"))
+	if firstName == "" || lastName == "" {
+		http.Error(w, "First and last name are required", http.StatusBadRequest)
+		return fmt.Errorf("missing contact name")
+	}
+
+	collection, err := h.app.FindCollectionByNameOrId(util.CollectionContacts)
+	if err != nil {
+		http.Error(w, "Failed to find collection", http.StatusInternalServerError)
+		return err
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("company", companyID)
+	record.Set("first_name", firstName)
+	record.Set("last_name", lastName)
+	record.Set("role", r.FormValue("new_contact_role"))
+	record.Set("email", r.FormValue("new_contact_email"))
+
+	if err := h.app.Save(record); err != nil {
+		http.Error(w, "Failed to create contact", http.StatusInternalServerError)
+		return err
+	}
+
+	contacts, err := util.FetchContactsForCompany(h.app, companyID)
+	if err != nil {
+		http.Error(w, "Failed to fetch contacts", http.StatusInternalServerError)
+		return err
+	}
+
+	selected := append(r.Form["contacts"], record.Id)
+	return templates.InterviewContactOptions(contacts, selected).Render(r.Context(), w)
+}
+
+// Bulk applies an action to the interviews picked in the list
+func (h *InterviewsHandler) Bulk(w http.ResponseWriter, r *http.Request) error {
+	return runBulkAction(h.app, w, r, bulkList{
+		collection: util.CollectionInterviews,
+		rowPrefix:  "interview",
+		noun:       "interviews",
+		actions:    models.BulkActions,
+		rows: func(app core.App, records []*core.Record) ([]templ.Component, error) {
+			if errs := app.ExpandRecords(records, []string{"role.company", "contacts", "tags"}, nil); len(errs) > 0 {
+				return nil, fmt.Errorf("failed to expand interviews: %v", errs)
+			}
+			rows := make([]templ.Component, len(records))
+			for i, record := range records {
+				interview := recordToInterview(record)
+				if roleRecord := record.ExpandedOne("role"); roleRecord != nil {
+					if companyRecord := roleRecord.ExpandedOne("company"); companyRecord != nil {
+						interview.CompanyName = companyRecord.GetString("name")
+					}
+				}
+				rows[i] = templates.InterviewRow(interview)
+			}
+			return rows, nil
+		},
+	})
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/templates"
+	"reverse-ats/internal/util"
+)
+
+type OffersHandler struct {
+	app *pocketbase.PocketBase
+}
+
+func NewOffersHandler(app *pocketbase.PocketBase) *OffersHandler {
+	return &OffersHandler{app: app}
+}
+
+func recordToOffer(record *core.Record) models.Offer {
+	// Format the deadline as YYYY-MM-DD for HTML date input
+	deadline := ""
+	if dt := record.GetDateTime("deadline"); !dt.IsZero() {
+		deadline = dt.Time().Format("2006-01-02")
+	}
+
+	return models.Offer{
+		ID:                 record.Id,
+		RoleID:             record.GetString("role"),
+		BaseSalary:         int64(record.GetInt("base_salary")),
+		SignOnBonus:        int64(record.GetInt("sign_on_bonus")),
+		BonusTargetPercent: record.GetFloat("bonus_target_percent"),
+		EquityShares:       int64(record.GetInt("equity_shares")),
+		EquityValue:        int64(record.GetInt("equity_value")),
+		VestingSchedule:    record.GetString("vesting_schedule"),
+		CliffMonths:        int64(record.GetInt("cliff_months")),
+		BenefitsNotes:      record.GetString("benefits_notes"),
+		Deadline:           deadline,
+		NegotiationRounds:  int64(record.GetInt("negotiation_rounds")),
+		NegotiationNotes:   record.GetString("negotiation_notes"),
+		CreatedAt:          record.GetDateTime("created").String(),
+		UpdatedAt:          record.GetDateTime("updated").String(),
+	}
+}
+
+// fetchOffers converts offer records and fills in role and company names
+func (h *OffersHandler) fetchOffers(records []*core.Record) ([]models.Offer, error) {
+	rolesMap, err := util.FetchRolesMap(h.app)
+	if err != nil {
+		return nil, err
+	}
+
+	companiesMap, err := util.FetchCompaniesMap(h.app)
+	if err != nil {
+		return nil, err
+	}
+
+	offers := make([]models.Offer, len(records))
+	for i, record := range records {
+		offer := recordToOffer(record)
+		if roleInfo, ok := rolesMap[offer.RoleID]; ok {
+			offer.RoleName = roleInfo.Name
+			offer.CompanyName = companiesMap[roleInfo.CompanyID]
+		}
+		offers[i] = offer
+	}
+
+	return offers, nil
+}
+
+// setOfferFields copies the offer form values onto a record
+func setOfferFields(r *http.Request, record *core.Record) error {
+	if r.FormValue("role") == "" {
+		return fmt.Errorf("role is required")
+	}
+	record.Set("role", r.FormValue("role"))
+
+	for _, field := range []string{"base_salary", "sign_on_bonus", "equity_shares", "equity_value", "cliff_months", "negotiation_rounds"} {
+		value := strings.ReplaceAll(strings.TrimSpace(r.FormValue(field)), ",", "")
+		if value == "" {
+			record.Set(field, 0)
+			continue
+		}
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || number < 0 {
+			return fmt.Errorf("%s must be a whole non-negative number", field)
+		}
+		record.Set(field, number)
+	}
+
+	bonusPercent := 0.0
+	if value := strings.TrimSuffix(strings.TrimSpace(r.FormValue("bonus_target_percent")), "%"); value != "" {
+		percent, err := strconv.ParseFloat(value, 64)
+		if err != nil || percent < 0 {
+			return fmt.Errorf("bonus_target_percent must be a non-negative number")
+		}
+		bonusPercent = percent
+	}
+	record.Set("bonus_target_percent", bonusPercent)
+
+	vestingSchedule := strings.TrimSpace(r.FormValue("vesting_schedule"))
+	if _, err := models.ParseVestingSchedule(vestingSchedule); err != nil {
+		return err
+	}
+	record.Set("vesting_schedule", vestingSchedule)
+
+	record.Set("deadline", r.FormValue("deadline"))
+	record.Set("benefits_notes", r.FormValue("benefits_notes"))
+	record.Set("negotiation_notes", r.FormValue("negotiation_notes"))
+
+	return nil
+}
+
+func (h *OffersHandler) List(w http.ResponseWriter, r *http.Request) error {
+	records, err := h.app.FindRecordsByFilter(util.CollectionOffers, "", "deadline", -1, 0)
+	if err != nil {
+		http.Error(w, "Failed to fetch offers", http.StatusInternalServerError)
+		return err
+	}
+
+	offers, err := h.fetchOffers(records)
+	if err != nil {
+		http.Error(w, "Failed to fetch roles", http.StatusInternalServerError)
+		return err
+	}
+
+	return templates.OffersList(offers).Render(r.Context(), w)
+}
+
+func (h *OffersHandler) New(w http.ResponseWriter, r *http.Request) error {
+	roles, err := util.FetchRolesForDropdown(h.app)
+	if err != nil {
+		http.Error(w, "Failed to fetch roles", http.StatusInternalServerError)
+		return err
+	}
+
+	// Preselect the role when coming from a role page
+	offer := models.Offer{RoleID: r.URL.Query().Get("role")}
+
+	return templates.OfferFormNew(offer, roles).Render(r.Context(), w)
+}
+
+func (h *OffersHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return err
+	}
+
+	collection, err := h.app.FindCollectionByNameOrId(util.CollectionOffers)
+	if err != nil {
+		http.Error(w, "Failed to find collection", http.StatusInternalServerError)
+		return err
+	}
+
+	record := core.NewRecord(collection)
+	if err := setOfferFields(r, record); err != nil {
+		http.Error(w, "Invalid offer: "+err.Error(), http.StatusBadRequest)
+		return err
+	}
+
+	if err := h.app.Save(record); err != nil {
+		http.Error(w, "Failed to create offer", http.StatusInternalServerError)
+		return err
+	}
+
+	http.Redirect(w, r, "/offers", http.StatusSeeOther)
+	return nil
+}
+
+func (h *OffersHandler) Edit(w http.ResponseWriter, r *http.Request) error {
+	// Extract ID from URL path parameter
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	record, err := h.app.FindRecordById(util.CollectionOffers, id)
+	if err != nil {
+		http.Error(w, "Offer not found", http.StatusNotFound)
+		return err
+	}
+
+	roles, err := util.FetchRolesForDropdown(h.app)
+	if err != nil {
+		http.Error(w, "Failed to fetch roles", http.StatusInternalServerError)
+		return err
+	}
+
+	return templates.OfferFormEdit(recordToOffer(record), roles).Render(r.Context(), w)
+}
+
+func (h *OffersHandler) Update(w http.ResponseWriter, r *http.Request) error {
+	// Extract ID from URL path parameter
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return err
+	}
+
+	record, err := h.app.FindRecordById(util.CollectionOffers, id)
+	if err != nil {
+		http.Error(w, "Offer not found", http.StatusNotFound)
+		return err
+	}
+
+	if err := setOfferFields(r, record); err != nil {
+		http.Error(w, "Invalid offer: "+err.Error(), http.StatusBadRequest)
+		return err
+	}
+
+	if err := h.app.Save(record); err != nil {
+		http.Error(w, "Failed to update offer", http.StatusInternalServerError)
+		return err
+	}
+
+	http.Redirect(w, r, "/offers", http.StatusSeeOther)
+	return nil
+}
+
+func (h *OffersHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	// Extract ID from URL path parameter
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	record, err := h.app.FindRecordById(util.CollectionOffers, id)
+	if err != nil {
+		http.Error(w, "Offer not found", http.StatusNotFound)
+		return err
+	}
+
+	if err := h.app.Delete(record); err != nil {
+		http.Error(w, "Failed to delete offer", http.StatusInternalServerError)
+		return err
+	}
+
+	// If HTMX request, return empty response (row will be removed)
+	if r.Header.Get("HX-Request") == "true" {
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+
+	// Otherwise redirect
+	http.Redirect(w, r, "/offers", http.StatusSeeOther)
+	return nil
+}
+
+// Compare shows the selected offers side by side, or every offer when none are selected
+func (h *OffersHandler) Compare(w http.ResponseWriter, r *http.Request) error {
+	var records []*core.Record
+	var err error
+
+	if ids := r.URL.Query()["offer"]; len(ids) > 0 {
+		records, err = h.app.FindRecordsByIds(util.CollectionOffers, ids)
+	} else {
+		records, err = h.app.FindRecordsByFilter(util.CollectionOffers, "", "deadline", -1, 0)
+	}
+	if err != nil {
+		http.Error(w, "Failed to fetch offers", http.StatusInternalServerError)
+		return err
+	}
+
+	offers, err := h.fetchOffers(records)
+	if err != nil {
+		http.Error(w, "Failed to fetch roles", http.StatusInternalServerError)
+		return err
+	}
+
+	return templates.OffersCompare(offers).Render(r.Context(), w)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/util"
+)
+
+// recordTags returns the expanded tags of a record
+func recordTags(record *core.Record) []models.Tag {
+	tagRecords := record.ExpandedAll("tags")
+	tags := make([]models.Tag, len(tagRecords))
+	for i, tagRecord := range tagRecords {
+		tags[i] = models.Tag{ID: tagRecord.Id, Name: tagRecord.GetString("name")}
+	}
+	return tags
+}
+
+// expandTags expands the tags of records so recordTags can read them
+func expandTags(app core.App, records []*core.Record) error {
+	if errs := app.ExpandRecords(records, []string{"tags"}, nil); len(errs) > 0 {
+		return fmt.Errorf("failed to expand tags: %v", errs)
+	}
+	return nil
+}
+
+// setRecordTags sets a record's tags from the comma-separated "tags" form field,
+// creating tags that don't exist yet. Forms without the field leave the tags as is.
+func setRecordTags(app core.App, r *http.Request, record *core.Record) error {
+	if _, ok := r.Form["tags"]; !ok {
+		return nil
+	}
+
+	ids, err := util.FindOrCreateTags(app, models.SplitTagNames(r.FormValue("tags")))
+	if err != nil {
+		return err
+	}
+
+	record.Set("tags", ids)
+	return nil
+}
+
+// listTagFilter resolves the ?tag= query param of a list page to a record filter.
+// An unknown tag yields a filter that matches nothing.
+func listTagFilter(app core.App, r *http.Request) (string, dbx.Params, models.TagFilter, error) {
+	tags, err := util.FetchTags(app)
+	if err != nil {
+		return "", nil, models.TagFilter{}, err
+	}
+
+	filter := models.TagFilter{Tags: tags}
+	name := strings.TrimSpace(r.URL.Query().Get("tag"))
+	if name == "" {
+		return "", nil, filter, nil
+	}
+
+	for _, tag := range tags {
+		if strings.EqualFold(tag.Name, name) {
+			filter.Active = tag.Name
+			return "tags.id ?= {:tag}", dbx.Params{"tag": tag.ID}, filter, nil
+		}
+	}
+
+	filter.Active = name
+	return "id = ''", nil, filter, nil
+}
@@ -0,0 +1,376 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/templates"
+	"reverse-ats/internal/util"
+)
+
+// taskRelations are the optional links of a task, expanded for display
+var taskRelations = []string{"company", "role", "contact", "interview"}
+
+type TasksHandler struct {
+	app *pocketbase.PocketBase
+}
+
+func NewTasksHandler(app *pocketbase.PocketBase) *TasksHandler {
+	return &TasksHandler{app: app}
+}
+
+func recordToTask(record *core.Record) models.Task {
+	// Format the due date as YYYY-MM-DD for HTML date input
+	dueDate := ""
+	if dt := record.GetDateTime("due_date"); !dt.IsZero() {
+		dueDate = dt.Time().Format("2006-01-02")
+	}
+
+	task := models.Task{
+		ID:          record.Id,
+		Title:       record.GetString("title"),
+		DueDate:     dueDate,
+		Done:        record.GetBool("done"),
+		CompletedAt: record.GetString("completed_at"),
+		Notes:       record.GetString("notes"),
+		CompanyID:   record.GetString("company"),
+		RoleID:      record.GetString("role"),
+		ContactID:   record.GetString("contact"),
+		InterviewID: record.GetString("interview"),
+		CreatedAt:   record.GetDateTime("created").String(),
+		UpdatedAt:   record.GetDateTime("updated").String(),
+	}
+
+	// Get names of linked records from expanded relations
+	if companyRecord := record.ExpandedOne("company"); companyRecord != nil {
+		task.CompanyName = companyRecord.GetString("name")
+	}
+	if roleRecord := record.ExpandedOne("role"); roleRecord != nil {
+		task.RoleName = roleRecord.GetString("name")
+	}
+	if contactRecord := record.ExpandedOne("contact"); contactRecord != nil {
+		task.ContactName = contactRecord.GetString("first_name") + " " + contactRecord.GetString("last_name")
+	}
+	if interviewRecord := record.ExpandedOne("interview"); interviewRecord != nil {
+		task.InterviewLabel = models.InterviewTypeLabel(interviewRecord.GetString("type")) + " on " +
+			util.FormatDateToText(interviewRecord.GetDateTime("date").String())
+	}
+
+	return task
+}
+
+// taskWeekBounds returns today and the last day (Sunday) of the current week.
+// Due dates are stored as midnight UTC of the picked day, so the local
+// calendar date is compared the same way.
+func taskWeekBounds(now time.Time) (time.Time, time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	daysLeft := (7 - int(today.Weekday())) % 7
+	return today, today.AddDate(0, 0, daysLeft)
+}
+
+// taskInView reports whether a task belongs to one of the task views
+func taskInView(task models.Task, view string, today, weekEnd time.Time) bool {
+	if view == models.TaskViewDone {
+		return task.Done
+	}
+	if task.Done {
+		return false
+	}
+
+	due, err := time.Parse("2006-01-02", task.DueDate)
+	switch view {
+	case models.TaskViewOverdue:
+		return err == nil && due.Before(today)
+	case models.TaskViewThisWeek:
+		return err == nil && !due.Before(today) && !due.After(weekEnd)
+	default:
+		return true
+	}
+}
+
+// sortTasksByDueDate sorts tasks by due date, tasks without one last
+func sortTasksByDueDate(tasks []models.Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].DueDate == "" || tasks[j].DueDate == "" {
+			return tasks[j].DueDate == "" && tasks[i].DueDate != ""
+		}
+		return tasks[i].DueDate < tasks[j].DueDate
+	})
+}
+
+// relationOptions fetches the records a task can be linked to
+func (h *TasksHandler) relationOptions() (models.TaskRelationOptions, error) {
+	var options models.TaskRelationOptions
+	var err error
+
+	if options.Companies, err = util.FetchCompaniesForDropdown(h.app); err != nil {
+		return options, err
+	}
+	if options.Roles, err = util.FetchRolesForDropdown(h.app); err != nil {
+		return options, err
+	}
+	if options.Contacts, err = util.FetchContactsForDropdown(h.app); err != nil {
+		return options, err
+	}
+
+	interviewRecords, err := h.app.FindRecordsByFilter(util.CollectionInterviews, "", "-date", -1, 0)
+	if err != nil {
+		return options, err
+	}
+	rolesMap, err := util.FetchRolesMap(h.app)
+	if err != nil {
+		return options, err
+	}
+	companiesMap, err := util.FetchCompaniesMap(h.app)
+	if err != nil {
+		return options, err
+	}
+
+	options.Interviews = make([]models.Interview, len(interviewRecords))
+	for i, record := range interviewRecords {
+		interview := recordToInterview(record)
+		if roleInfo, ok := rolesMap[interview.RoleID]; ok {
+			interview.RoleName = roleInfo.Name
+			interview.CompanyName = companiesMap[roleInfo.CompanyID]
+		}
+		options.Interviews[i] = interview
+	}
+
+	return options, nil
+}
+
+// setTaskFields copies the task form values onto a record
+func setTaskFields(r *http.Request, record *core.Record) {
+	record.Set("title", r.FormValue("title"))
+	record.Set("due_date", r.FormValue("due_date"))
+	record.Set("notes", r.FormValue("notes"))
+	for _, field := range taskRelations {
+		record.Set(field, r.FormValue(field))
+	}
+}
+
+func (h *TasksHandler) List(w http.ResponseWriter, r *http.Request) error {
+	view := r.URL.Query().Get("view")
+	validViews := map[string]bool{
+		models.TaskViewOpen:     true,
+		models.TaskViewOverdue:  true,
+		models.TaskViewThisWeek: true,
+		models.TaskViewDone:     true,
+	}
+	if !validViews[view] {
+		view = models.TaskViewOpen
+	}
+
+	records, err := h.app.FindRecordsByFilter(util.CollectionTasks, "", "", -1, 0)
+	if err != nil {
+		http.Error(w, "Failed to fetch tasks", http.StatusInternalServerError)
+		return err
+	}
+
+	if errs := h.app.ExpandRecords(records, taskRelations, nil); len(errs) > 0 {
+		http.Error(w, "Failed to fetch linked records", http.StatusInternalServerError)
+		return fmt.Errorf("failed to expand task relations: %v", errs)
+	}
+
+	// Filter in memory so every view's count can be shown in the tabs
+	today, weekEnd := taskWeekBounds(time.Now())
+	counts := make(map[string]int, len(validViews))
+	tasks := []models.Task{}
+	for _, record := range records {
+		task := recordToTask(record)
+		for v := range validViews {
+			if taskInView(task, v, today, weekEnd) {
+				counts[v]++
+			}
+		}
+		if taskInView(task, view, today, weekEnd) {
+			tasks = append(tasks, task)
+		}
+	}
+	sortTasksByDueDate(tasks)
+
+	roles, err := util.FetchRolesForDropdown(h.app)
+	if err != nil {
+		http.Error(w, "Failed to fetch roles", http.StatusInternalServerError)
+		return err
+	}
+
+	return templates.TasksList(tasks, view, counts, roles).Render(r.Context(), w)
+}
+
+func (h *TasksHandler) New(w http.ResponseWriter, r *http.Request) error {
+	options, err := h.relationOptions()
+	if err != nil {
+		http.Error(w, "Failed to fetch linked records", http.StatusInternalServerError)
+		return err
+	}
+
+	// Preselect links passed in the query, e.g. /tasks/new?role=abc
+	task := models.Task{
+		CompanyID:   r.URL.Query().Get("company"),
+		RoleID:      r.URL.Query().Get("role"),
+		ContactID:   r.URL.Query().Get("contact"),
+		InterviewID: r.URL.Query().Get("interview"),
+	}
+
+	return templates.TaskFormNew(task, options).Render(r.Context(), w)
+}
+
+func (h *TasksHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return err
+	}
+
+	collection, err := h.app.FindCollectionByNameOrId(util.CollectionTasks)
+	if err != nil {
+		http.Error(w, "Failed to find collection", http.StatusInternalServerError)
+		return err
+	}
+
+	record := core.NewRecord(collection)
+	setTaskFields(r, record)
+	record.Set("done", false)
+
+	if err := h.app.Save(record); err != nil {
+		http.Error(w, "Failed to create task", http.StatusInternalServerError)
+		return err
+	}
+
+	// If HTMX request, return just the new row
+	if r.Header.Get("HX-Request") == "true" {
+		h.app.ExpandRecord(record, taskRelations, nil)
+		return templates.TaskRow(recordToTask(record)).Render(r.Context(), w)
+	}
+
+	// Otherwise redirect
+	http.Redirect(w, r, "/tasks", http.StatusSeeOther)
+	return nil
+}
+
+func (h *TasksHandler) Edit(w http.ResponseWriter, r *http.Request) error {
+	// Extract ID from URL path parameter
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	record, err := h.app.FindRecordById(util.CollectionTasks, id)
+	if err != nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return err
+	}
+
+	options, err := h.relationOptions()
+	if err != nil {
+		http.Error(w, "Failed to fetch linked records", http.StatusInternalServerError)
+		return err
+	}
+
+	return templates.TaskFormEdit(recordToTask(record), options).Render(r.Context(), w)
+}
+
+func (h *TasksHandler) Update(w http.ResponseWriter, r *http.Request) error {
+	// Extract ID from URL path parameter
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return err
+	}
+
+	record, err := h.app.FindRecordById(util.CollectionTasks, id)
+	if err != nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return err
+	}
+
+	setTaskFields(r, record)
+
+	if err := h.app.Save(record); err != nil {
+		http.Error(w, "Failed to update task", http.StatusInternalServerError)
+		return err
+	}
+
+	http.Redirect(w, r, "/tasks", http.StatusSeeOther)
+	return nil
+}
+
+// Toggle marks a task done or open again and returns the updated row
+func (h *TasksHandler) Toggle(w http.ResponseWriter, r *http.Request) error {
+	// Extract ID from URL path parameter
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	record, err := h.app.FindRecordById(util.CollectionTasks, id)
+	if err != nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return err
+	}
+
+	done := !record.GetBool("done")
+	record.Set("done", done)
+	if done {
+		record.Set("completed_at", types.NowDateTime())
+	} else {
+		record.Set("completed_at", "")
+	}
+
+	if err := h.app.Save(record); err != nil {
+		http.Error(w, "Failed to update task", http.StatusInternalServerError)
+		return err
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		h.app.ExpandRecord(record, taskRelations, nil)
+		return templates.TaskRow(recordToTask(record)).Render(r.Context(), w)
+	}
+
+	http.Redirect(w, r, "/tasks", http.StatusSeeOther)
+	return nil
+}
+
+func (h *TasksHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	// Extract ID from URL path parameter
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	record, err := h.app.FindRecordById(util.CollectionTasks, id)
+	if err != nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return err
+	}
+
+	if err := h.app.Delete(record); err != nil {
+		http.Error(w, "Failed to delete task", http.StatusInternalServerError)
+		return err
+	}
+
+	// If HTMX request, return empty response (row will be removed)
+	if r.Header.Get("HX-Request") == "true" {
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+
+	// Otherwise redirect
+	http.Redirect(w, r, "/tasks", http.StatusSeeOther)
+	return nil
+}
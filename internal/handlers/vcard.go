@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/templates"
+	"reverse-ats/internal/util"
+	"reverse-ats/internal/vcard"
+)
+
+// ExportVCard downloads a contact as a vCard. ?version=4.0 selects vCard 4.0
+// instead of the widely supported 3.0.
+func (h *ContactsHandler) ExportVCard(w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	record, err := h.app.FindRecordById(util.CollectionContacts, id)
+	if err != nil {
+		http.Error(w, "Contact not found", http.StatusNotFound)
+		return err
+	}
+
+	return h.writeVCards(w, r, fmt.Sprintf("contact-%s.vcf", id), []*core.Record{record})
+}
+
+// ExportCompanyVCards downloads every contact of a company as one vCard file
+func (h *ContactsHandler) ExportCompanyVCards(w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	if _, err := h.app.FindRecordById(util.CollectionCompanies, id); err != nil {
+		http.Error(w, "Company not found", http.StatusNotFound)
+		return err
+	}
+
+	records, err := h.app.FindRecordsByFilter(
+		util.CollectionContacts,
+		"company = {:company}",
+		"first_name,last_name",
+		-1,
+		0,
+		dbx.Params{"company": id},
+	)
+	if err != nil {
+		http.Error(w, "Failed to fetch contacts", http.StatusInternalServerError)
+		return err
+	}
+	if len(records) == 0 {
+		http.Error(w, "The company has no contacts", http.StatusNotFound)
+		return fmt.Errorf("company %s has no contacts", id)
+	}
+
+	return h.writeVCards(w, r, fmt.Sprintf("company-%s-contacts.vcf", id), records)
+}
+
+// writeVCards sends contacts as a vCard file download
+func (h *ContactsHandler) writeVCards(w http.ResponseWriter, r *http.Request, filename string, records []*core.Record) error {
+	version := r.URL.Query().Get("version")
+	if version == "" {
+		version = vcard.Version3
+	}
+	if version != vcard.Version3 && version != vcard.Version4 {
+		http.Error(w, "Unsupported vCard version, use 3.0 or 4.0", http.StatusBadRequest)
+		return fmt.Errorf("unsupported vCard version %q", version)
+	}
+
+	if errs := h.app.ExpandRecords(records, []string{"company", "tags"}, nil); len(errs) > 0 {
+		http.Error(w, "Failed to fetch contacts", http.StatusInternalServerError)
+		return fmt.Errorf("failed to expand contacts: %v", errs)
+	}
+
+	cards := make([]vcard.Card, len(records))
+	for i, record := range records {
+		cards[i] = contactCard(recordToContact(record))
+	}
+
+	w.Header().Set("Content-Type", "text/vcard; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	return vcard.Write(w, version, cards)
+}
+
+// contactCard turns a contact into a vCard
+func contactCard(contact models.Contact) vcard.Card {
+	card := vcard.Card{
+		UID:          contact.ID + "@reverse-ats",
+		FirstName:    contact.FirstName,
+		LastName:     contact.LastName,
+		Organization: contact.CompanyName,
+		Title:        contact.Role,
+		LinkedIn:     contact.Linkedin,
+		Note:         contact.Notes,
+	}
+	if contact.Email != "" {
+		card.Emails = []string{contact.Email}
+	}
+	if contact.Phone != "" {
+		card.Phones = []string{contact.Phone}
+	}
+	for _, tag := range contact.Tags {
+		card.Categories = append(card.Categories, tag.Name)
+	}
+	return card
+}
+
+// ImportVCard creates contacts from an uploaded vCard file. Every contact goes
+// to the company picked in the form or, when none is, to the company named by
+// the card's organization or whose URL matches the card's email domain.
+// Cards that can't be imported are skipped and reported.
+func (h *ContactsHandler) ImportVCard(w http.ResponseWriter, r *http.Request) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		http.Error(w, "The file is too large or the upload failed", http.StatusBadRequest)
+		return err
+	}
+
+	file, _, err := r.FormFile("vcard")
+	if err != nil {
+		http.Error(w, "No vCard file uploaded", http.StatusBadRequest)
+		return err
+	}
+	defer file.Close()
+
+	cards, err := vcard.Parse(file)
+	if err != nil {
+		http.Error(w, "Failed to read vCard file: "+err.Error(), http.StatusBadRequest)
+		return err
+	}
+	if len(cards) == 0 {
+		http.Error(w, "The file holds no vCards", http.StatusBadRequest)
+		return fmt.Errorf("no vCards in upload")
+	}
+
+	companyID := r.FormValue("company")
+	if companyID != "" {
+		if _, err := h.app.FindRecordById(util.CollectionCompanies, companyID); err != nil {
+			http.Error(w, "Company not found", http.StatusBadRequest)
+			return err
+		}
+	}
+
+	// One transaction, so a failed import adds none of the contacts
+	var result models.VCardImport
+	err = h.app.RunInTransaction(func(txApp core.App) error {
+		result, err = importCards(txApp, cards, companyID)
+		return err
+	})
+	if err != nil {
+		http.Error(w, "Failed to import contacts", http.StatusInternalServerError)
+		return err
+	}
+
+	return templates.VCardImportResult(result).Render(r.Context(), w)
+}
+
+// importCards creates a contact for each card, into companyID or the matching company
+func importCards(app core.App, cards []vcard.Card, companyID string) (models.VCardImport, error) {
+	var result models.VCardImport
+
+	collection, err := app.FindCollectionByNameOrId(util.CollectionContacts)
+	if err != nil {
+		return result, err
+	}
+	companyRecords, err := app.FindRecordsByFilter(util.CollectionCompanies, "", "name", -1, 0)
+	if err != nil {
+		return result, err
+	}
+	companyNames := make(map[string]string, len(companyRecords))
+	for _, record := range companyRecords {
+		companyNames[record.Id] = record.GetString("name")
+	}
+
+	ctx := util.WithImport(context.Background())
+	for i, card := range cards {
+		skip := func(format string, args ...any) {
+			result.Skipped = append(result.Skipped, models.VCardSkip{
+				Card:   i + 1,
+				Name:   card.Name(),
+				Reason: fmt.Sprintf(format, args...),
+			})
+		}
+
+		email := ""
+		if len(card.Emails) > 0 {
+			email = card.Emails[0]
+		}
+
+		firstName, lastName := strings.TrimSpace(card.FirstName), strings.TrimSpace(card.LastName)
+		if firstName == "" && lastName == "" {
+			firstName, lastName = splitName(card.FullName, email)
+		}
+		if firstName == "" || lastName == "" {
+			skip("needs a first and a last name")
+			continue
+		}
+
+		if email != "" {
+			existing := &core.Record{}
+			err := app.RecordQuery(util.CollectionContacts).
+				AndWhere(dbx.NewExp("email = {:email} COLLATE NOCASE", dbx.Params{"email": email})).
+				Limit(1).
+				One(existing)
+			if err == nil {
+				skip("%s is already the email of %s %s", email, existing.GetString("first_name"), existing.GetString("last_name"))
+				continue
+			}
+			if !errors.Is(err, sql.ErrNoRows) {
+				return result, err
+			}
+		}
+
+		contactCompany := companyID
+		if contactCompany == "" {
+			contactCompany = matchCardCompany(card, companyRecords)
+		}
+		if contactCompany == "" {
+			if card.Organization != "" {
+				skip("no company matches %q; pick the company to import into", card.Organization)
+			} else {
+				skip("no company matches; pick the company to import into")
+			}
+			continue
+		}
+
+		record := core.NewRecord(collection)
+		record.Set("company", contactCompany)
+		record.Set("first_name", firstName)
+		record.Set("last_name", lastName)
+		record.Set("role", card.Title)
+		record.Set("email", email)
+		if len(card.Phones) > 0 {
+			record.Set("phone", card.Phones[0])
+		}
+		record.Set("linkedin", card.LinkedIn)
+		record.Set("notes", card.Note)
+
+		// Only categories that are already tags are kept; phones add their own,
+		// like "myContacts", that would clutter the tags
+		var tagIDs []string
+		for _, category := range card.Categories {
+			tag, err := util.FindTagByName(app, category)
+			if err != nil {
+				return result, err
+			}
+			if tag != nil {
+				tagIDs = append(tagIDs, tag.ID)
+			}
+		}
+		record.Set("tags", tagIDs)
+
+		if err := app.SaveWithContext(ctx, record); err != nil {
+			skip("%v", err)
+			continue
+		}
+
+		contact := recordToContact(record)
+		contact.CompanyName = companyNames[contactCompany]
+		result.Created = append(result.Created, contact)
+	}
+
+	return result, nil
+}
+
+// matchCardCompany returns the company named by a card's organization or,
+// failing that, the company whose URL matches one of its email domains
+func matchCardCompany(card vcard.Card, companyRecords []*core.Record) string {
+	if organization := strings.TrimSpace(card.Organization); organization != "" {
+		for _, record := range companyRecords {
+			if strings.EqualFold(strings.TrimSpace(record.GetString("name")), organization) {
+				return record.Id
+			}
+		}
+	}
+
+	for _, email := range card.Emails {
+		for _, record := range companyRecords {
+			if matchesAnyDomain(emailDomain(email), []string{urlHost(record.GetString("url"))}) {
+				return record.Id
+			}
+		}
+	}
+
+	return ""
+}
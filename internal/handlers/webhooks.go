@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/templates"
+	"reverse-ats/internal/util"
+	"reverse-ats/internal/webhooks"
+)
+
+// maxDeliveriesShown caps the delivery log page
+const maxDeliveriesShown = 200
+
+type WebhooksHandler struct {
+	app        *pocketbase.PocketBase
+	dispatcher *webhooks.Dispatcher
+}
+
+func NewWebhooksHandler(app *pocketbase.PocketBase, dispatcher *webhooks.Dispatcher) *WebhooksHandler {
+	return &WebhooksHandler{app: app, dispatcher: dispatcher}
+}
+
+// WatchWebhookEvents sends webhooks the events of roles, interviews and
+// contacts, with the records as the JSON API returns them
+func WatchWebhookEvents(dispatcher *webhooks.Dispatcher) {
+	dispatcher.Watch(util.CollectionRoles, "role", webhookConverter(apiRoles))
+	dispatcher.Watch(util.CollectionInterviews, "interview", webhookConverter(apiInterviews))
+	dispatcher.Watch(util.CollectionContacts, "contact", webhookConverter(apiContacts))
+}
+
+// webhookConverter converts records for webhook payloads like an API resource does
+func webhookConverter[T any](res apiResource[T]) webhooks.Converter {
+	return func(app core.App, record *core.Record) (any, error) {
+		items, err := res.convert(app, []*core.Record{record})
+		if err != nil {
+			return nil, err
+		}
+		return items[0], nil
+	}
+}
+
+func recordToWebhook(record *core.Record) models.Webhook {
+	return models.Webhook{
+		ID:       record.Id,
+		Name:     record.GetString("name"),
+		URL:      record.GetString("url"),
+		Secret:   record.GetString("secret"),
+		Events:   record.GetStringSlice("events"),
+		Statuses: record.GetStringSlice("statuses"),
+		Active:   record.GetBool("active"),
+	}
+}
+
+func recordToWebhookDelivery(record *core.Record) models.WebhookDelivery {
+	delivery := models.WebhookDelivery{
+		ID:             record.Id,
+		WebhookID:      record.GetString("webhook"),
+		Event:          record.GetString("event"),
+		Payload:        record.GetString("payload"),
+		Status:         record.GetString("status"),
+		Attempts:       record.GetInt("attempts"),
+		ResponseStatus: record.GetInt("response_status"),
+		ResponseBody:   record.GetString("response_body"),
+		Error:          record.GetString("error"),
+		CreatedAt:      record.GetDateTime("created_at").String(),
+		LastAttemptAt:  record.GetDateTime("last_attempt_at").String(),
+		NextAttemptAt:  record.GetDateTime("next_attempt_at").String(),
+	}
+
+	// Get the webhook from the expanded relation
+	if webhookRecord := record.ExpandedOne("webhook"); webhookRecord != nil {
+		delivery.WebhookName = webhookRecord.GetString("name")
+		delivery.WebhookURL = webhookRecord.GetString("url")
+	}
+
+	return delivery
+}
+
+// setWebhookFields sets a webhook's fields from the form
+func setWebhookFields(r *http.Request, record *core.Record) error {
+	webhookURL := strings.TrimSpace(r.FormValue("url"))
+	if u, err := url.Parse(webhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("the URL must start with http:// or https://")
+	}
+
+	var events []string
+	for _, event := range r.Form["events"] {
+		if slices.Contains(models.WebhookEvents, event) {
+			events = append(events, event)
+		}
+	}
+	if len(events) == 0 {
+		return fmt.Errorf("choose at least one event")
+	}
+
+	var statuses []string
+	for _, status := range r.Form["statuses"] {
+		if slices.Contains(models.RoleStatuses, status) {
+			statuses = append(statuses, status)
+		}
+	}
+
+	record.Set("name", strings.TrimSpace(r.FormValue("name")))
+	record.Set("url", webhookURL)
+	record.Set("secret", strings.TrimSpace(r.FormValue("secret")))
+	record.Set("events", events)
+	record.Set("statuses", statuses)
+	record.Set("active", r.FormValue("active") == "true")
+	return nil
+}
+
+func (h *WebhooksHandler) List(w http.ResponseWriter, r *http.Request) error {
+	records, err := h.app.FindRecordsByFilter(util.CollectionWebhooks, "", "name,url", -1, 0)
+	if err != nil {
+		http.Error(w, "Failed to fetch webhooks", http.StatusInternalServerError)
+		return err
+	}
+
+	webhookList := make([]models.Webhook, len(records))
+	for i, record := range records {
+		webhookList[i] = recordToWebhook(record)
+	}
+
+	return templates.WebhooksList(webhookList).Render(r.Context(), w)
+}
+
+func (h *WebhooksHandler) New(w http.ResponseWriter, r *http.Request) error {
+	webhook := models.Webhook{
+		Events: []string{models.WebhookEventRoleStatusChanged, models.WebhookEventInterviewCreated},
+		Active: true,
+	}
+	return templates.WebhookFormNew(webhook).Render(r.Context(), w)
+}
+
+func (h *WebhooksHandler) Create(w http.ResponseWriter, r *http.Request) error {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return err
+	}
+
+	collection, err := h.app.FindCollectionByNameOrId(util.CollectionWebhooks)
+	if err != nil {
+		http.Error(w, "Failed to find collection", http.StatusInternalServerError)
+		return err
+	}
+
+	record := core.NewRecord(collection)
+	if err := setWebhookFields(r, record); err != nil {
+		http.Error(w, "Invalid webhook: "+err.Error(), http.StatusBadRequest)
+		return err
+	}
+
+	if err := h.app.Save(record); err != nil {
+		http.Error(w, "Failed to create webhook", http.StatusInternalServerError)
+		return err
+	}
+
+	http.Redirect(w, r, "/webhooks", http.StatusSeeOther)
+	return nil
+}
+
+func (h *WebhooksHandler) Edit(w http.ResponseWriter, r *http.Request) error {
+	// Extract ID from URL path parameter
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	record, err := h.app.FindRecordById(util.CollectionWebhooks, id)
+	if err != nil {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return err
+	}
+
+	return templates.WebhookFormEdit(recordToWebhook(record)).Render(r.Context(), w)
+}
+
+func (h *WebhooksHandler) Update(w http.ResponseWriter, r *http.Request) error {
+	// Extract ID from URL path parameter
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return err
+	}
+
+	record, err := h.app.FindRecordById(util.CollectionWebhooks, id)
+	if err != nil {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return err
+	}
+
+	if err := setWebhookFields(r, record); err != nil {
+		http.Error(w, "Invalid webhook: "+err.Error(), http.StatusBadRequest)
+		return err
+	}
+
+	if err := h.app.Save(record); err != nil {
+		http.Error(w, "Failed to update webhook", http.StatusInternalServerError)
+		return err
+	}
+
+	http.Redirect(w, r, "/webhooks", http.StatusSeeOther)
+	return nil
+}
+
+func (h *WebhooksHandler) Delete(w http.ResponseWriter, r *http.Request) error {
+	// Extract ID from URL path parameter
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return fmt.Errorf("missing id parameter")
+	}
+
+	record, err := h.app.FindRecordById(util.CollectionWebhooks, id)
+	if err != nil {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return err
+	}
+
+	// Its delivery log is deleted with it
+	if err := h.app.Delete(record); err != nil {
+		http.Error(w, "Failed to delete webhook", http.StatusInternalServerError)
+		return err
+	}
+
+	// If HTMX request, return empty response (row will be removed)
+	if r.Header.Get("HX-Request") == "true" {
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+
+	// Otherwise redirect
+	http.Redirect(w, r, "/webhooks", http.StatusSeeOther)
+	return nil
+}
+
+// Test sends a ping event to a webhook and shows its delivery log, where
+// the outcome is on top
+func (h *WebhooksHandler) Test(w http.ResponseWriter, r *http.Request) error {
+	record, err := h.app.FindRecordById(util.CollectionWebhooks, r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return err
+	}
+
+	if _, err := h.dispatcher.Ping(record.Id); err != nil {
+		http.Error(w, "Failed to send the test event", http.StatusInternalServerError)
+		return err
+	}
+
+	http.Redirect(w, r, "/webhooks/deliveries?webhook="+record.Id, http.StatusSeeOther)
+	return nil
+}
+
+// Deliveries shows the delivery log, newest first, of all webhooks or of
+// the one in ?webhook=
+func (h *WebhooksHandler) Deliveries(w http.ResponseWriter, r *http.Request) error {
+	filter := ""
+	params := dbx.Params{}
+	var webhook *models.Webhook
+	if id := r.URL.Query().Get("webhook"); id != "" {
+		record, err := h.app.FindRecordById(util.CollectionWebhooks, id)
+		if err != nil {
+			http.Error(w, "Webhook not found", http.StatusNotFound)
+			return err
+		}
+		found := recordToWebhook(record)
+		webhook = &found
+		filter = "webhook = {:webhook}"
+		params["webhook"] = id
+	}
+	if status := r.URL.Query().Get("status"); status != "" {
+		if filter != "" {
+			filter += " && "
+		}
+		filter += "status = {:status}"
+		params["status"] = status
+	}
+
+	records, err := h.app.FindRecordsByFilter(util.CollectionWebhookDeliveries, filter, "-created_at,-id", maxDeliveriesShown, 0, params)
+	if err != nil {
+		http.Error(w, "Failed to fetch deliveries", http.StatusInternalServerError)
+		return err
+	}
+	if errs := h.app.ExpandRecords(records, []string{"webhook"}, nil); len(errs) > 0 {
+		http.Error(w, "Failed to fetch webhooks", http.StatusInternalServerError)
+		return fmt.Errorf("failed to expand webhooks: %v", errs)
+	}
+
+	deliveries := make([]models.WebhookDelivery, len(records))
+	for i, record := range records {
+		deliveries[i] = recordToWebhookDelivery(record)
+	}
+
+	return templates.WebhookDeliveries(deliveries, webhook, r.URL.Query().Get("status")).Render(r.Context(), w)
+}
+
+// Redeliver sends a delivery again right away and returns its updated row
+func (h *WebhooksHandler) Redeliver(w http.ResponseWriter, r *http.Request) error {
+	if _, err := h.app.FindRecordById(util.CollectionWebhookDeliveries, r.PathValue("id")); err != nil {
+		http.Error(w, "Delivery not found", http.StatusNotFound)
+		return err
+	}
+
+	delivery, err := h.dispatcher.Redeliver(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Failed to send the delivery", http.StatusInternalServerError)
+		return err
+	}
+
+	if errs := h.app.ExpandRecord(delivery, []string{"webhook"}, nil); len(errs) > 0 {
+		http.Error(w, "Failed to fetch webhook", http.StatusInternalServerError)
+		return fmt.Errorf("failed to expand webhook: %v", errs)
+	}
+
+	return templates.WebhookDeliveryRow(recordToWebhookDelivery(delivery)).Render(r.Context(), w)
+}
@@ -0,0 +1,122 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"reverse-ats/internal/contentline"
+)
+
+// Layouts of iCalendar DATE, floating DATE-TIME and UTC DATE-TIME values
+const (
+	dateLayout  = "20060102"
+	localLayout = "20060102T150405"
+	utcLayout   = "20060102T150405Z"
+)
+
+const productID = "-//reverse-ats//Interviews//EN"
+
+// Attendee is a participant of an event
+type Attendee struct {
+	Name  string
+	Email string
+}
+
+// Event is a calendar event. Start and End are floating times, shown in
+// the calendar's own time zone, since interview times are stored without one.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool // Start and End are dates, End being the day after the event
+	Organizer   Attendee
+	Attendees   []Attendee
+	Categories  []string
+}
+
+// Write writes a calendar holding the given events
+func Write(w io.Writer, name string, events []Event) error {
+	cw := contentline.NewWriter(w)
+	stamp := time.Now().UTC().Format(utcLayout)
+
+	cw.Line("BEGIN:VCALENDAR")
+	cw.Line("VERSION:2.0")
+	cw.Line("PRODID:" + productID)
+	cw.Line("CALSCALE:GREGORIAN")
+	cw.Line("METHOD:PUBLISH")
+	if name != "" {
+		cw.Line("X-WR-CALNAME:" + escapeText(name))
+	}
+
+	for _, event := range events {
+		cw.Line("BEGIN:VEVENT")
+		cw.Line("UID:" + escapeText(event.UID))
+		cw.Line("DTSTAMP:" + stamp)
+		if event.AllDay {
+			cw.Line("DTSTART;VALUE=DATE:" + event.Start.Format(dateLayout))
+			cw.Line("DTEND;VALUE=DATE:" + event.End.Format(dateLayout))
+		} else {
+			cw.Line("DTSTART:" + event.Start.Format(localLayout))
+			if !event.End.IsZero() {
+				cw.Line("DTEND:" + event.End.Format(localLayout))
+			}
+		}
+		cw.Line("SUMMARY:" + escapeText(event.Summary))
+		if event.Description != "" {
+			cw.Line("DESCRIPTION:" + escapeText(event.Description))
+		}
+		if event.Location != "" {
+			cw.Line("LOCATION:" + escapeText(event.Location))
+		}
+		if event.URL != "" {
+			cw.Line("URL:" + event.URL)
+		}
+		if len(event.Categories) > 0 {
+			categories := make([]string, len(event.Categories))
+			for i, category := range event.Categories {
+				categories[i] = escapeText(category)
+			}
+			cw.Line("CATEGORIES:" + strings.Join(categories, ","))
+		}
+		if event.Organizer.Email != "" {
+			cw.Line(fmt.Sprintf("ORGANIZER;CN=%s:mailto:%s", quoteParam(event.Organizer.Name), event.Organizer.Email))
+		}
+		for _, attendee := range event.Attendees {
+			if attendee.Email == "" {
+				continue
+			}
+			cw.Line(fmt.Sprintf("ATTENDEE;CN=%s;ROLE=REQ-PARTICIPANT:mailto:%s", quoteParam(attendee.Name), attendee.Email))
+		}
+		cw.Line("END:VEVENT")
+	}
+
+	cw.Line("END:VCALENDAR")
+	return cw.Flush()
+}
+
+// textEscaper escapes the characters TEXT values can't hold as is
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// escapeText escapes a TEXT value
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// quoteParam quotes a parameter value, which can't hold double quotes or line breaks
+func quoteParam(s string) string {
+	s = strings.NewReplacer(`"`, "'", "\r", " ", "\n", " ").Replace(s)
+	return `"` + s + `"`
+}
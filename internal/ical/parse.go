@@ -0,0 +1,283 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// property is one content line: NAME;PARAM=value:VALUE
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads the events of an iCalendar file, such as an emailed invite.
+// Times with a time zone are converted to loc, using the calendar's VTIMEZONE
+// definitions for zones the Go time zone database doesn't know; floating
+// times and dates are read as they are written in loc. Unknown properties and
+// components are ignored.
+func Parse(r io.Reader, loc *time.Location) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	zones := parseTimezones(lines)
+
+	var events []Event
+	var event *Event
+	var duration time.Duration
+	depth := 0 // Components nested inside the current event, like alarms
+
+	for i, line := range lines {
+		prop, err := parseProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT") && event == nil:
+			event = &Event{}
+			duration = 0
+			continue
+		case prop.name == "BEGIN" && event != nil:
+			depth++
+			continue
+		case prop.name == "END" && event != nil && depth > 0:
+			depth--
+			continue
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT") && event != nil:
+			if event.End.IsZero() && !event.Start.IsZero() {
+				switch {
+				case duration != 0:
+					event.End = event.Start.Add(duration)
+				case event.AllDay:
+					event.End = event.Start.AddDate(0, 0, 1)
+				}
+			}
+			events = append(events, *event)
+			event = nil
+			continue
+		}
+		if event == nil || depth > 0 {
+			continue
+		}
+
+		switch prop.name {
+		case "UID":
+			event.UID = unescapeText(prop.value)
+		case "SUMMARY":
+			event.Summary = unescapeText(prop.value)
+		case "DESCRIPTION":
+			event.Description = unescapeText(prop.value)
+		case "LOCATION":
+			event.Location = unescapeText(prop.value)
+		case "URL":
+			event.URL = prop.value
+		case "DTSTART":
+			event.Start, event.AllDay, err = parseTime(prop, loc, zones)
+		case "DTEND":
+			event.End, _, err = parseTime(prop, loc, zones)
+		case "DURATION":
+			duration, err = parseDuration(prop.value)
+		case "ORGANIZER":
+			event.Organizer = parseAttendee(prop)
+		case "ATTENDEE":
+			event.Attendees = append(event.Attendees, parseAttendee(prop))
+		case "CATEGORIES":
+			for _, category := range splitText(prop.value) {
+				event.Categories = append(event.Categories, unescapeText(category))
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", i+1, prop.name, err)
+		}
+	}
+
+	return events, nil
+}
+
+// unfold reads the content lines of a file, joining lines continued on
+// lines that start with a space or tab
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// parseProperty splits a content line into its name, parameters and value.
+// Quoted parameter values may hold ':' and ';'.
+func parseProperty(line string) (property, error) {
+	prop := property{params: make(map[string]string)}
+
+	quoted := false
+	start := 0
+	paramName := ""
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '=' && prop.name != "" && paramName == "":
+			paramName = strings.ToUpper(line[start:i])
+			start = i + 1
+		case c == ';' || c == ':':
+			part := line[start:i]
+			if prop.name == "" {
+				prop.name = strings.ToUpper(part)
+			} else if paramName != "" {
+				prop.params[paramName] = strings.Trim(part, `"`)
+				paramName = ""
+			}
+			start = i + 1
+			if c == ':' {
+				prop.value = line[start:]
+				return prop, nil
+			}
+		}
+	}
+
+	return prop, fmt.Errorf("invalid content line %q", line)
+}
+
+// parseTime reads a DATE or DATE-TIME value, reporting whether it is a date
+func parseTime(prop property, loc *time.Location, zones map[string]timezone) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == len(dateLayout) {
+		t, err := time.ParseInLocation(dateLayout, value, loc)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(utcLayout, value)
+		return t.In(loc), false, err
+	}
+
+	tzid := prop.params["TZID"]
+	if tzid != "" {
+		if zone, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			t, err := time.ParseInLocation(localLayout, value, zone)
+			return t.In(loc), false, err
+		}
+	}
+
+	// Zones the Go time zone database doesn't know, like the Windows names
+	// used by Outlook, are read with the calendar's definition of them, and
+	// as floating times without one
+	wall, err := time.Parse(localLayout, value)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if offset, ok := zones[tzid].offset(wall); ok {
+		return wall.Add(-time.Duration(offset) * time.Second).In(loc), false, nil
+	}
+	t := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, loc)
+	return t, false, nil
+}
+
+// parseDuration reads a DURATION value such as PT1H30M or P1D
+func parseDuration(value string) (time.Duration, error) {
+	s := strings.TrimPrefix(strings.TrimPrefix(value, "+"), "-")
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	s = s[1:]
+
+	var total time.Duration
+	inTime := false
+	number := ""
+	for _, c := range s {
+		switch {
+		case c == 'T':
+			inTime = true
+		case c >= '0' && c <= '9':
+			number += string(c)
+		default:
+			n, err := strconv.Atoi(number)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			number = ""
+
+			switch {
+			case c == 'W':
+				total += time.Duration(n) * 7 * 24 * time.Hour
+			case c == 'D':
+				total += time.Duration(n) * 24 * time.Hour
+			case c == 'H' && inTime:
+				total += time.Duration(n) * time.Hour
+			case c == 'M' && inTime:
+				total += time.Duration(n) * time.Minute
+			case c == 'S' && inTime:
+				total += time.Duration(n) * time.Second
+			default:
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+		}
+	}
+
+	if strings.HasPrefix(value, "-") {
+		total = -total
+	}
+	return total, nil
+}
+
+// parseAttendee reads an ATTENDEE or ORGANIZER value and its CN parameter
+func parseAttendee(prop property) Attendee {
+	attendee := Attendee{Name: prop.params["CN"]}
+	if address := strings.TrimSpace(prop.value); len(address) > len("mailto:") && strings.EqualFold(address[:len("mailto:")], "mailto:") {
+		attendee.Email = address[len("mailto:"):]
+	}
+	return attendee
+}
+
+// splitText splits a list of TEXT values on the commas that aren't escaped
+func splitText(value string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ',':
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
+
+// textUnescaper reverses escapeText
+var textUnescaper = strings.NewReplacer(
+	`\\`, `\`,
+	`\;`, ";",
+	`\,`, ",",
+	`\n`, "\n",
+	`\N`, "\n",
+)
+
+// unescapeText reads a TEXT value
+func unescapeText(s string) string {
+	return textUnescaper.Replace(s)
+}
@@ -0,0 +1,196 @@
+package ical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"reverse-ats/internal/ical"
+)
+
+// An invite sent by Google Calendar
+const googleInvite = `BEGIN:VCALENDAR
+PRODID:-//Google Inc//Google Calendar 70.9054//EN
+VERSION:2.0
+CALSCALE:GREGORIAN
+METHOD:REQUEST
+BEGIN:VTIMEZONE
+TZID:America/Los_Angeles
+X-LIC-LOCATION:America/Los_Angeles
+BEGIN:DAYLIGHT
+TZOFFSETFROM:-0800
+TZOFFSETTO:-0700
+TZNAME:PDT
+DTSTART:19700308T020000
+RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU
+END:DAYLIGHT
+BEGIN:STANDARD
+TZOFFSETFROM:-0700
+TZOFFSETTO:-0800
+TZNAME:PST
+DTSTART:19701101T020000
+RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU
+END:STANDARD
+END:VTIMEZONE
+BEGIN:VEVENT
+DTSTART;TZID=America/Los_Angeles:20251027T093000
+DTEND;TZID=America/Los_Angeles:20251027T101500
+DTSTAMP:20251020T170405Z
+ORGANIZER;CN=Pat Recruiter:mailto:pat@acme.example.com
+UID:7kukuqrfedlm2f9t3vjrd6kh6b@google.com
+ATTENDEE;CUTYPE=INDIVIDUAL;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=
+ TRUE;CN=Sam Candidate;X-NUM-GUESTS=0:mailto:sam@example.com
+ATTENDEE;CUTYPE=INDIVIDUAL;ROLE=REQ-PARTICIPANT;PARTSTAT=ACCEPTED;RSVP=TRUE
+ ;CN=Pat Recruiter;X-NUM-GUESTS=0:mailto:pat@acme.example.com
+DESCRIPTION:Technical screen\, 45 minutes.\nJoin with Google Meet: https://
+ meet.google.com/abc-defg-hij
+LOCATION:
+SEQUENCE:0
+STATUS:CONFIRMED
+SUMMARY:Acme technical screen
+TRANSP:OPAQUE
+BEGIN:VALARM
+ACTION:DISPLAY
+DESCRIPTION:This is an event reminder
+TRIGGER:-P0DT0H10M0S
+END:VALARM
+END:VEVENT
+END:VCALENDAR
+`
+
+// An invite sent by Outlook, its time zone named after Windows
+const outlookInvite = `BEGIN:VCALENDAR
+METHOD:REQUEST
+PRODID:Microsoft Exchange Server 2010
+VERSION:2.0
+BEGIN:VTIMEZONE
+TZID:Pacific Standard Time
+BEGIN:STANDARD
+DTSTART:16010101T020000
+TZOFFSETFROM:-0700
+TZOFFSETTO:-0800
+RRULE:FREQ=YEARLY;INTERVAL=1;BYDAY=1SU;BYMONTH=11
+END:STANDARD
+BEGIN:DAYLIGHT
+DTSTART:16010101T020000
+TZOFFSETFROM:-0800
+TZOFFSETTO:-0700
+RRULE:FREQ=YEARLY;INTERVAL=1;BYDAY=2SU;BYMONTH=3
+END:DAYLIGHT
+END:VTIMEZONE
+BEGIN:VEVENT
+ORGANIZER;CN=Pat Recruiter:mailto:pat@acme.example.com
+ATTENDEE;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE;CN=Sam Candid
+ ate:mailto:sam@example.com
+DESCRIPTION;LANGUAGE=en-US:Onsite loop with the platform team\n
+UID:040000008200E00074C5B7101A82E00800000000D0C1A0F5E43FDC01000000000000000
+ 010000000B8D1B8F6A2D6E5489A7A4C2B6F0E1F2A
+SUMMARY;LANGUAGE=en-US:Acme onsite loop
+DTSTART;TZID=Pacific Standard Time:%s
+DTEND;TZID=Pacific Standard Time:%s
+CLASS:PUBLIC
+PRIORITY:5
+DTSTAMP:20251020T170405Z
+TRANSP:OPAQUE
+STATUS:CONFIRMED
+SEQUENCE:0
+LOCATION;LANGUAGE=en-US:Building 4\, Room 210
+END:VEVENT
+END:VCALENDAR
+`
+
+// An all-day event, with no end
+const allDayInvite = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Apple Inc.//macOS 15.1//EN
+BEGIN:VEVENT
+UID:9F3A2B1C-ALLDAY@example.com
+DTSTART;VALUE=DATE:20251103
+SUMMARY:Take-home assignment due
+END:VEVENT
+END:VCALENDAR
+`
+
+// parseOne parses a calendar holding a single event
+func parseOne(t *testing.T, calendar string, loc *time.Location) ical.Event {
+	t.Helper()
+
+	events, err := ical.Parse(strings.NewReader(strings.ReplaceAll(calendar, "\n", "\r\n")), loc)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	return events[0]
+}
+
+func TestParseGoogleInvite(t *testing.T) {
+	event := parseOne(t, googleInvite, time.UTC)
+
+	wantStart := time.Date(2025, 10, 27, 16, 30, 0, 0, time.UTC)
+	if !event.Start.Equal(wantStart) || !event.End.Equal(wantStart.Add(45*time.Minute)) || event.AllDay {
+		t.Errorf("event runs %s to %s (all day %t), want %s to %s", event.Start, event.End, event.AllDay, wantStart, wantStart.Add(45*time.Minute))
+	}
+	if event.Summary != "Acme technical screen" {
+		t.Errorf("Summary = %q", event.Summary)
+	}
+	if want := "Technical screen, 45 minutes.\nJoin with Google Meet: https://meet.google.com/abc-defg-hij"; event.Description != want {
+		t.Errorf("Description = %q, want %q", event.Description, want)
+	}
+	if event.Organizer != (ical.Attendee{Name: "Pat Recruiter", Email: "pat@acme.example.com"}) {
+		t.Errorf("Organizer = %+v", event.Organizer)
+	}
+	if len(event.Attendees) != 2 || event.Attendees[0] != (ical.Attendee{Name: "Sam Candidate", Email: "sam@example.com"}) {
+		t.Errorf("Attendees = %+v", event.Attendees)
+	}
+}
+
+func TestParseOutlookInvite(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end string
+		wantStart  time.Time
+	}{
+		{"daylight saving time", "20251027T093000", "20251027T150000", time.Date(2025, 10, 27, 16, 30, 0, 0, time.UTC)},
+		{"standard time", "20251103T093000", "20251103T150000", time.Date(2025, 11, 3, 17, 30, 0, 0, time.UTC)},
+		{"day daylight saving time starts", "20260308T093000", "20260308T150000", time.Date(2026, 3, 8, 16, 30, 0, 0, time.UTC)},
+		{"day before it starts", "20260307T093000", "20260307T150000", time.Date(2026, 3, 7, 17, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := strings.Replace(strings.Replace(outlookInvite, "%s", tt.start, 1), "%s", tt.end, 1)
+			event := parseOne(t, calendar, time.UTC)
+
+			wantEnd := tt.wantStart.Add(5*time.Hour + 30*time.Minute)
+			if !event.Start.Equal(tt.wantStart) || !event.End.Equal(wantEnd) {
+				t.Errorf("event runs %s to %s, want %s to %s", event.Start, event.End, tt.wantStart, wantEnd)
+			}
+			if event.Location != "Building 4, Room 210" || event.Summary != "Acme onsite loop" {
+				t.Errorf("got %q at %q", event.Summary, event.Location)
+			}
+		})
+	}
+}
+
+func TestParseAllDayEvent(t *testing.T) {
+	loc := time.FixedZone("EST", -5*60*60)
+	event := parseOne(t, allDayInvite, loc)
+
+	wantStart := time.Date(2025, 11, 3, 0, 0, 0, 0, loc)
+	if !event.AllDay || !event.Start.Equal(wantStart) || !event.End.Equal(wantStart.AddDate(0, 0, 1)) {
+		t.Errorf("event runs %s to %s (all day %t), want all day on %s", event.Start, event.End, event.AllDay, wantStart.Format(time.DateOnly))
+	}
+}
+
+func TestParseUnknownZoneIsFloating(t *testing.T) {
+	calendar := strings.Replace(strings.Replace(outlookInvite, "%s", "20251027T093000", 1), "%s", "20251027T150000", 1)
+	calendar = strings.Replace(calendar, "TZID:Pacific Standard Time", "TZID:Somewhere Else", 1)
+	loc := time.FixedZone("EST", -5*60*60)
+	event := parseOne(t, calendar, loc)
+
+	if want := time.Date(2025, 10, 27, 9, 30, 0, 0, loc); !event.Start.Equal(want) {
+		t.Errorf("Start = %s, want %s", event.Start, want)
+	}
+}
@@ -0,0 +1,192 @@
+package ical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timezone is a VTIMEZONE: the offsets from UTC a TZID observes, like
+// standard and daylight saving time. Outlook names zones after Windows
+// ("Pacific Standard Time"), which the Go time zone database doesn't know,
+// so invites are read with the offsets they define.
+type timezone []observance
+
+// observance is a STANDARD or DAYLIGHT component of a VTIMEZONE
+type observance struct {
+	start  time.Time // Wall clock time of the first onset, in UTC
+	offset int       // TZOFFSETTO in seconds
+
+	// Yearly onsets from the RRULE: the week'th weekday of month, counting
+	// from the end when week is negative. Without one, start is the only onset.
+	month   time.Month
+	week    int
+	weekday time.Weekday
+	until   time.Time
+}
+
+// parseTimezones reads the VTIMEZONE components of a calendar by TZID
+func parseTimezones(lines []string) map[string]timezone {
+	zones := make(map[string]timezone)
+
+	var tzid string
+	var zone timezone
+	var obs *observance
+	for _, line := range lines {
+		prop, err := parseProperty(line)
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VTIMEZONE"):
+			tzid, zone = "", nil
+		case prop.name == "END" && strings.EqualFold(prop.value, "VTIMEZONE"):
+			if tzid != "" && len(zone) > 0 {
+				zones[tzid] = zone
+			}
+		case prop.name == "BEGIN" && (strings.EqualFold(prop.value, "STANDARD") || strings.EqualFold(prop.value, "DAYLIGHT")):
+			obs = &observance{}
+		case prop.name == "END" && obs != nil:
+			if !obs.start.IsZero() {
+				zone = append(zone, *obs)
+			}
+			obs = nil
+		case prop.name == "TZID" && obs == nil:
+			tzid = prop.value
+		case prop.name == "DTSTART" && obs != nil:
+			obs.start, _ = time.Parse(localLayout, strings.TrimSpace(prop.value))
+		case prop.name == "TZOFFSETTO" && obs != nil:
+			if obs.offset, err = parseOffset(prop.value); err != nil {
+				obs.start = time.Time{} // Drop the observance
+			}
+		case prop.name == "RRULE" && obs != nil:
+			obs.parseRule(prop.value)
+		}
+	}
+
+	return zones
+}
+
+// parseOffset reads a UTC offset such as -0800 or +053000 in seconds
+func parseOffset(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if len(value) != 5 && len(value) != 7 || value[0] != '+' && value[0] != '-' {
+		return 0, fmt.Errorf("invalid UTC offset %q", value)
+	}
+
+	seconds := 0
+	for i, unit := range []int{3600, 60, 1} {
+		if 1+2*i >= len(value) {
+			break
+		}
+		n, err := strconv.Atoi(value[1+2*i : 3+2*i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid UTC offset %q", value)
+		}
+		seconds += n * unit
+	}
+
+	if value[0] == '-' {
+		seconds = -seconds
+	}
+	return seconds, nil
+}
+
+// weekdays are the BYDAY abbreviations
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// parseRule reads the yearly RRULE of an observance, such as
+// FREQ=YEARLY;BYMONTH=3;BYDAY=2SU. Other rules leave start as the only onset.
+func (obs *observance) parseRule(value string) {
+	var month time.Month
+	var week int
+	var weekday time.Weekday
+	var until time.Time
+	yearly := false
+
+	for _, part := range strings.Split(value, ";") {
+		name, v, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			yearly = strings.EqualFold(v, "YEARLY")
+		case "BYMONTH":
+			n, _ := strconv.Atoi(v)
+			month = time.Month(n)
+		case "BYDAY":
+			if len(v) < 2 {
+				return
+			}
+			day, ok := weekdays[strings.ToUpper(v[len(v)-2:])]
+			if !ok {
+				return
+			}
+			weekday = day
+			week = 1
+			if n := v[:len(v)-2]; n != "" {
+				week, _ = strconv.Atoi(strings.TrimPrefix(n, "+"))
+			}
+		case "UNTIL":
+			until, _ = time.Parse(utcLayout, v)
+			if until.IsZero() {
+				until, _ = time.Parse(localLayout, v)
+			}
+		}
+	}
+
+	if yearly && month >= time.January && month <= time.December && week != 0 && week >= -5 && week <= 5 {
+		obs.month, obs.week, obs.weekday, obs.until = month, week, weekday, until
+	}
+}
+
+// onset returns the observance's onset in a year
+func (obs observance) onset(year int) time.Time {
+	clock := obs.start.Sub(obs.start.Truncate(24 * time.Hour))
+	if obs.week > 0 {
+		first := time.Date(year, obs.month, 1, 0, 0, 0, 0, time.UTC)
+		days := (int(obs.weekday) - int(first.Weekday()) + 7) % 7
+		return first.AddDate(0, 0, days+7*(obs.week-1)).Add(clock)
+	}
+	last := time.Date(year, obs.month+1, 0, 0, 0, 0, 0, time.UTC)
+	days := (int(last.Weekday()) - int(obs.weekday) + 7) % 7
+	return last.AddDate(0, 0, -days-7*(-obs.week-1)).Add(clock)
+}
+
+// latestOnset returns the observance's last onset at or before a wall clock time
+func (obs observance) latestOnset(wall time.Time) (time.Time, bool) {
+	if obs.start.After(wall) {
+		return time.Time{}, false
+	}
+	if obs.week == 0 {
+		return obs.start, true
+	}
+
+	for year := wall.Year(); year >= wall.Year()-1; year-- {
+		onset := obs.onset(year)
+		if onset.After(wall) || onset.Before(obs.start) {
+			continue
+		}
+		if !obs.until.IsZero() && onset.After(obs.until) {
+			continue
+		}
+		return onset, true
+	}
+	return time.Time{}, false
+}
+
+// offset returns the UTC offset in seconds in effect at a wall clock time,
+// that of the observance with the latest onset before it
+func (zone timezone) offset(wall time.Time) (int, bool) {
+	var latest time.Time
+	offset, found := 0, false
+	for _, obs := range zone {
+		if onset, ok := obs.latestOnset(wall); ok && (!found || onset.After(latest)) {
+			latest, offset, found = onset, obs.offset, true
+		}
+	}
+	return offset, found
+}
@@ -0,0 +1,114 @@
+package importer_test
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"reverse-ats/internal/importer"
+)
+
+// Rows of the generated import, 60,000 in all
+const (
+	benchCompanies  = 4000
+	benchRoles      = 5 // Per company
+	benchContacts   = 3 // Per company
+	benchInterviews = 3 // Per company, each with one contact
+)
+
+// writeCSV writes the rows of a CSV file, header first
+func writeCSV(b *testing.B, path string, rows [][]string) {
+	b.Helper()
+
+	file, err := os.Create(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.WriteAll(rows); err != nil {
+		b.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+// writeBenchFiles generates the CSV files of a large job search in a directory
+func writeBenchFiles(b *testing.B, dir string) {
+	b.Helper()
+
+	tags := []string{"remote", "urgent", "referral", ""}
+
+	companies := [][]string{{"id", "name", "description", "url", "hq_city", "hq_state", "tags"}}
+	roles := [][]string{{"id", "company", "name", "url", "applied_date", "posted_range_min", "posted_range_max", "location", "status", "notes", "tags"}}
+	contacts := [][]string{{"id", "company", "first_name", "last_name", "role", "email", "tags"}}
+	interviews := [][]string{{"id", "role", "date", "start", "end", "type", "notes", "tags"}}
+	interviewContacts := [][]string{{"interview", "contact"}}
+
+	for c := range benchCompanies {
+		company := fmt.Sprintf("c%d", c)
+		companies = append(companies, []string{
+			company, fmt.Sprintf("Company %d", c), "A company\nthat is hiring", fmt.Sprintf("https://c%d.example.com", c), "Austin", "TX", tags[c%len(tags)],
+		})
+
+		for r := range benchRoles {
+			role := fmt.Sprintf("%s-r%d", company, r)
+			roles = append(roles, []string{
+				role, company, fmt.Sprintf("Engineer %d", r), fmt.Sprintf("https://c%d.example.com/jobs/%d", c, r), "2025-10-20", "150000", "200000", "REMOTE", "APPLIED", "Applied online", tags[(c+r)%len(tags)],
+			})
+		}
+
+		for n := range benchContacts {
+			contacts = append(contacts, []string{
+				fmt.Sprintf("%s-p%d", company, n), company, "Sam", fmt.Sprintf("Lee %d", n), "Recruiter", fmt.Sprintf("sam%d@c%d.example.com", n, c), tags[(c+n)%len(tags)],
+			})
+		}
+
+		for i := range benchInterviews {
+			interview := fmt.Sprintf("%s-i%d", company, i)
+			interviews = append(interviews, []string{
+				interview, fmt.Sprintf("%s-r%d", company, i%benchRoles), "2025-10-27", "09:30", "10:15", "TECH_SCREEN", "Bring questions", tags[(c+i)%len(tags)],
+			})
+			interviewContacts = append(interviewContacts, []string{interview, fmt.Sprintf("%s-p%d", company, i%benchContacts)})
+		}
+	}
+
+	for _, step := range importer.GetImportSteps() {
+		var rows [][]string
+		switch step.Name {
+		case "companies":
+			rows = companies
+		case "roles":
+			rows = roles
+		case "contacts":
+			rows = contacts
+		case "interviews":
+			rows = interviews
+		case "interviews-contacts":
+			rows = interviewContacts
+		}
+		writeCSV(b, filepath.Join(dir, step.Filename), rows)
+	}
+}
+
+// BenchmarkImportFromSteps imports 60,000 rows into an empty app
+func BenchmarkImportFromSteps(b *testing.B) {
+	dir := b.TempDir()
+	writeBenchFiles(b, dir)
+
+	steps := importer.GetImportSteps()
+	for i := range steps {
+		steps[i].Filepath = filepath.Join(dir, steps[i].Filename)
+	}
+
+	for b.Loop() {
+		b.StopTimer()
+		app := newTestApp(b)
+		b.StartTimer()
+
+		if _, errs := importer.ImportFromSteps(app, steps, false, false); len(errs) > 0 {
+			b.Fatalf("import failed: %v", errs)
+		}
+	}
+}
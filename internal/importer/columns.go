@@ -0,0 +1,165 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// column describes a CSV column the importer understands.
+// Headers are matched ignoring case, spaces, underscores and dashes, so
+// "companyID", "company_id" and "Company ID" all name the same column.
+type column struct {
+	Name     string   // Name used by the importer (the PocketBase field where there is one)
+	Aliases  []string // Other accepted header names
+	Required bool
+	Text     bool // Free text, read as is instead of trimmed
+}
+
+// Columns accepted in each CSV file
+var (
+	companyColumns = []column{
+		{Name: "id", Aliases: []string{"companyID"}},
+		{Name: "name", Required: true, Text: true},
+		{Name: "description", Text: true},
+		{Name: "url"},
+		{Name: "linkedin"},
+		{Name: "hq_city", Text: true},
+		{Name: "hq_state", Text: true},
+		{Name: "tags"},
+	}
+
+	roleColumns = []column{
+		{Name: "id", Aliases: []string{"roleID"}},
+		{Name: "company", Aliases: []string{"companyID"}, Required: true},
+		{Name: "name", Required: true, Text: true},
+		{Name: "url"},
+		{Name: "description", Text: true},
+		{Name: "cover_letter", Text: true},
+		{Name: "application_location", Text: true},
+		{Name: "applied_date"},
+		{Name: "closed_date"},
+		{Name: "posted_range_min"},
+		{Name: "posted_range_max"},
+		{Name: "equity"},
+		{Name: "work_city", Text: true},
+		{Name: "work_state", Text: true},
+		{Name: "location"},
+		{Name: "status"},
+		{Name: "discovery", Text: true},
+		{Name: "referral"},
+		{Name: "notes", Text: true},
+		{Name: "tags"},
+	}
+
+	contactColumns = []column{
+		{Name: "id", Aliases: []string{"contactID"}},
+		{Name: "company", Aliases: []string{"companyID"}, Required: true},
+		{Name: "first_name", Required: true, Text: true},
+		{Name: "last_name", Required: true, Text: true},
+		{Name: "role", Text: true},
+		{Name: "email"},
+		{Name: "phone", Text: true},
+		{Name: "linkedin"},
+		{Name: "notes", Text: true},
+		{Name: "tags"},
+	}
+
+	interviewColumns = []column{
+		{Name: "id", Aliases: []string{"interviewID"}},
+		{Name: "role", Aliases: []string{"roleID"}, Required: true},
+		{Name: "date", Required: true},
+		{Name: "start", Required: true},
+		{Name: "end", Required: true},
+		{Name: "type", Required: true},
+		{Name: "notes", Text: true},
+		{Name: "tags"},
+	}
+
+	interviewContactColumns = []column{
+		{Name: "interview", Aliases: []string{"interviewID"}, Required: true},
+		{Name: "contact", Aliases: []string{"contactID"}, Required: true},
+	}
+)
+
+// normalizeHeader reduces a header to lowercase letters and digits
+func normalizeHeader(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// csvHeader maps the columns of a CSV file to their positions
+type csvHeader struct {
+	positions map[string]int
+	text      map[string]bool // Free text columns
+}
+
+// readHeader reads the header row and maps it to the known columns.
+// Unknown columns are ignored; missing required columns are reported together.
+func readHeader(reader *csv.Reader, columns []column) (*csvHeader, error) {
+	row, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	// Spreadsheet apps often start the file with a byte order mark
+	if len(row) > 0 {
+		row[0] = strings.TrimPrefix(row[0], "\ufeff")
+	}
+
+	found := make(map[string]int, len(row))
+	for i, name := range row {
+		key := normalizeHeader(name)
+		if _, ok := found[key]; !ok {
+			found[key] = i
+		}
+	}
+
+	header := &csvHeader{positions: make(map[string]int), text: make(map[string]bool)}
+	var missing []string
+	for _, col := range columns {
+		for _, name := range append([]string{col.Name}, col.Aliases...) {
+			if i, ok := found[normalizeHeader(name)]; ok {
+				header.positions[col.Name] = i
+				break
+			}
+		}
+		header.text[col.Name] = col.Text
+		if _, ok := header.positions[col.Name]; !ok && col.Required {
+			missing = append(missing, strings.Join(append([]string{col.Name}, col.Aliases...), "/"))
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required columns: %s", strings.Join(missing, ", "))
+	}
+
+	return header, nil
+}
+
+// has reports whether the file has a column
+func (h *csvHeader) has(name string) bool {
+	_, ok := h.positions[name]
+	return ok
+}
+
+// value returns the value of a column in a row, or "" if the file doesn't have it.
+// Values are trimmed, except free text.
+func (h *csvHeader) value(record []string, name string) string {
+	i, ok := h.positions[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+
+	v := strings.ReplaceAll(record[i], quotedCR, "\r")
+	if h.text[name] {
+		return v
+	}
+	return strings.TrimSpace(v)
+}
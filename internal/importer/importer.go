@@ -1,529 +1,842 @@
-package importer
-
-import (
-	"encoding/csv"
-	"fmt"
-	"io"
-	"os"
-	"strings"
-	"time"
-
-	"github.com/pocketbase/pocketbase"
-	"github.com/pocketbase/pocketbase/core"
-)
-
-// ID mapping tables to handle conversion from old integer IDs to PocketBase string IDs
-type IDMappings struct {
-	Companies map[string]string // old ID -> new ID
-	Roles     map[string]string
-	Contacts  map[string]string
-	Interviews map[string]string
-}
-
-func NewIDMappings() *IDMappings {
-	return &IDMappings{
-		Companies: make(map[string]string),
-		Roles:     make(map[string]string),
-		Contacts:  make(map[string]string),
-		Interviews: make(map[string]string),
-	}
-}
-
-// emptyToNull converts empty string or "NULL" to empty string for PocketBase
-func emptyToNull(s string) string {
-	if s == "NULL" {
-		return ""
-	}
-	return s
-}
-
-// parseBool converts string to boolean
-func parseBool(s string) bool {
-	if s == "" || s == "NULL" {
-		return false
-	}
-	s = strings.ToLower(strings.TrimSpace(s))
-	return s == "true" || s == "yes" || s == "1" || s == "t" || s == "y"
-}
-
-// parseInt64 parses int64 from string, handling currency format
-func parseInt64(s string) int64 {
-	if s == "" || s == "NULL" {
-		return 0
-	}
-
-	// Remove currency symbols, commas, and decimal portions
-	s = strings.TrimPrefix(s, "$")
-	s = strings.ReplaceAll(s, ",", "")
-
-	// If there's a decimal point, take only the integer part
-	if idx := strings.Index(s, "."); idx != -1 {
-		s = s[:idx]
-	}
-
-	var result int64
-	fmt.Sscanf(s, "%d", &result)
-	return result
-}
-
-// parseDate converts date string to ISO format (YYYY-MM-DD)
-// Handles both "April 14, 2025" and "2025-04-14" formats
-func parseDate(s string) string {
-	if s == "" || s == "NULL" {
-		return ""
-	}
-
-	// Try ISO format first (YYYY-MM-DD)
-	if t, err := time.Parse("2006-01-02", s); err == nil {
-		return t.Format("2006-01-02")
-	}
-
-	// Try text format (January 2, 2006)
-	if t, err := time.Parse("January 2, 2006", s); err == nil {
-		return t.Format("2006-01-02")
-	}
-
-	// Return as-is if can't parse (will likely fail validation)
-	return s
-}
-
-// ImportCompanies imports companies from CSV file
-func ImportCompanies(app *pocketbase.PocketBase, filepath string, mappings *IDMappings) error {
-	file, err := os.Open(filepath)
-	if err != nil {
-		return fmt.Errorf("failed to open companies CSV: %w", err)
-	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-
-	// Skip header
-	if _, err := reader.Read(); err != nil {
-		return fmt.Errorf("failed to read header: %w", err)
-	}
-
-	collection, err := app.FindCollectionByNameOrId("companies")
-	if err != nil {
-		return fmt.Errorf("failed to find companies collection: %w", err)
-	}
-
-	count := 0
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return fmt.Errorf("failed to read CSV record: %w", err)
-		}
-
-		// Skip if not enough fields
-		if len(record) < 7 {
-			continue
-		}
-
-		// CSV columns: companyID,name,description,url,linkedin,hqCity,hqState
-		oldID := record[0]
-
-		pbRecord := core.NewRecord(collection)
-		pbRecord.Set("name", record[1])
-		pbRecord.Set("description", emptyToNull(record[2]))
-		pbRecord.Set("url", emptyToNull(record[3]))
-		pbRecord.Set("linkedin", emptyToNull(record[4]))
-		pbRecord.Set("hq_city", emptyToNull(record[5]))
-		pbRecord.Set("hq_state", emptyToNull(record[6]))
-
-		if err := app.Save(pbRecord); err != nil {
-			return fmt.Errorf("failed to insert company %s: %w", record[1], err)
-		}
-
-		// Store ID mapping
-		mappings.Companies[oldID] = pbRecord.Id
-		count++
-	}
-
-	fmt.Printf("Imported %d companies\n", count)
-	return nil
-}
-
-// ImportContacts imports contacts from CSV file
-func ImportContacts(app *pocketbase.PocketBase, filepath string, mappings *IDMappings) error {
-	file, err := os.Open(filepath)
-	if err != nil {
-		return fmt.Errorf("failed to open contacts CSV: %w", err)
-	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-
-	// Skip header
-	if _, err := reader.Read(); err != nil {
-		return fmt.Errorf("failed to read header: %w", err)
-	}
-
-	collection, err := app.FindCollectionByNameOrId("contacts")
-	if err != nil {
-		return fmt.Errorf("failed to find contacts collection: %w", err)
-	}
-
-	count := 0
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return fmt.Errorf("failed to read CSV record: %w", err)
-		}
-
-		// Skip if not enough fields
-		if len(record) < 9 {
-			continue
-		}
-
-		// CSV columns: contactID,companyID,firstName,lastName,role,email,phone,linkedin,notes
-		oldID := record[0]
-		oldCompanyID := record[1]
-
-		// Map old company ID to new PocketBase ID
-		newCompanyID, ok := mappings.Companies[oldCompanyID]
-		if !ok {
-			return fmt.Errorf("company ID %s not found in mapping for contact %s %s", oldCompanyID, record[2], record[3])
-		}
-
-		pbRecord := core.NewRecord(collection)
-		pbRecord.Set("company", newCompanyID)
-		pbRecord.Set("first_name", record[2])
-		pbRecord.Set("last_name", record[3])
-		pbRecord.Set("role", emptyToNull(record[4]))
-		pbRecord.Set("email", emptyToNull(record[5]))
-		pbRecord.Set("phone", emptyToNull(record[6]))
-		pbRecord.Set("linkedin", emptyToNull(record[7]))
-		pbRecord.Set("notes", emptyToNull(record[8]))
-
-		if err := app.Save(pbRecord); err != nil {
-			return fmt.Errorf("failed to insert contact %s %s: %w", record[2], record[3], err)
-		}
-
-		// Store ID mapping
-		mappings.Contacts[oldID] = pbRecord.Id
-		count++
-	}
-
-	fmt.Printf("Imported %d contacts\n", count)
-	return nil
-}
-
-// ImportRoles imports roles from CSV file
-func ImportRoles(app *pocketbase.PocketBase, filepath string, mappings *IDMappings) error {
-	file, err := os.Open(filepath)
-	if err != nil {
-		return fmt.Errorf("failed to open roles CSV: %w", err)
-	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-
-	// Skip header
-	if _, err := reader.Read(); err != nil {
-		return fmt.Errorf("failed to read header: %w", err)
-	}
-
-	collection, err := app.FindCollectionByNameOrId("roles")
-	if err != nil {
-		return fmt.Errorf("failed to find roles collection: %w", err)
-	}
-
-	count := 0
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return fmt.Errorf("failed to read CSV record: %w", err)
-		}
-
-		// Skip if not enough fields
-		if len(record) < 19 {
-			continue
-		}
-
-		// CSV columns: roleID,companyID,name,url,description,coverLetter,applicationLocation,
-		// appliedDate,closedDate,postedRangeMin,postedRangeMax,equity,workCity,workState,
-		// location,status,discovery,referral,notes
-
-		// Skip rows with empty company ID (malformed CSV)
-		if record[1] == "" || record[1] == "NULL" {
-			continue
-		}
-
-		oldID := record[0]
-		oldCompanyID := record[1]
-
-		// Map old company ID to new PocketBase ID
-		newCompanyID, ok := mappings.Companies[oldCompanyID]
-		if !ok {
-			return fmt.Errorf("company ID %s not found in mapping for role %s", oldCompanyID, record[2])
-		}
-
-		pbRecord := core.NewRecord(collection)
-		pbRecord.Set("company", newCompanyID)
-		pbRecord.Set("name", record[2])
-		pbRecord.Set("url", emptyToNull(record[3]))
-		pbRecord.Set("description", emptyToNull(record[4]))
-		pbRecord.Set("cover_letter", emptyToNull(record[5]))
-		pbRecord.Set("application_location", emptyToNull(record[6]))
-		pbRecord.Set("applied_date", parseDate(record[7]))
-		pbRecord.Set("closed_date", parseDate(record[8]))
-		pbRecord.Set("posted_range_min", parseInt64(record[9]))
-		pbRecord.Set("posted_range_max", parseInt64(record[10]))
-		pbRecord.Set("equity", parseBool(record[11]))
-		pbRecord.Set("work_city", emptyToNull(record[12]))
-		pbRecord.Set("work_state", emptyToNull(record[13]))
-		pbRecord.Set("location", emptyToNull(record[14]))
-		pbRecord.Set("status", emptyToNull(record[15]))
-		pbRecord.Set("discovery", emptyToNull(record[16]))
-		pbRecord.Set("referral", parseBool(record[17]))
-		pbRecord.Set("notes", emptyToNull(record[18]))
-
-		if err := app.Save(pbRecord); err != nil {
-			return fmt.Errorf("failed to insert role %s: %w", record[2], err)
-		}
-
-		// Store ID mapping
-		mappings.Roles[oldID] = pbRecord.Id
-		count++
-	}
-
-	fmt.Printf("Imported %d roles\n", count)
-	return nil
-}
-
-// ImportInterviews imports interviews from CSV file
-func ImportInterviews(app *pocketbase.PocketBase, filepath string, mappings *IDMappings) error {
-	file, err := os.Open(filepath)
-	if err != nil {
-		return fmt.Errorf("failed to open interviews CSV: %w", err)
-	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-
-	// Skip header
-	if _, err := reader.Read(); err != nil {
-		return fmt.Errorf("failed to read header: %w", err)
-	}
-
-	collection, err := app.FindCollectionByNameOrId("interviews")
-	if err != nil {
-		return fmt.Errorf("failed to find interviews collection: %w", err)
-	}
-
-	count := 0
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return fmt.Errorf("failed to read CSV record: %w", err)
-		}
-
-		// Skip if not enough fields
-		if len(record) < 7 {
-			continue
-		}
-
-		// CSV columns: interviewID,roleID,date,start,end,notes,type
-		oldID := record[0]
-		oldRoleID := record[1]
-
-		// Map old role ID to new PocketBase ID
-		newRoleID, ok := mappings.Roles[oldRoleID]
-		if !ok {
-			return fmt.Errorf("role ID %s not found in mapping for interview on %s", oldRoleID, record[2])
-		}
-
-		pbRecord := core.NewRecord(collection)
-		pbRecord.Set("role", newRoleID)
-		pbRecord.Set("date", parseDate(record[2]))
-		pbRecord.Set("start", record[3])
-		pbRecord.Set("end", record[4])
-		pbRecord.Set("notes", emptyToNull(record[5]))
-		pbRecord.Set("type", record[6])
-
-		if err := app.Save(pbRecord); err != nil {
-			return fmt.Errorf("failed to insert interview on %s: %w", record[2], err)
-		}
-
-		// Store ID mapping
-		mappings.Interviews[oldID] = pbRecord.Id
-		count++
-	}
-
-	fmt.Printf("Imported %d interviews\n", count)
-	return nil
-}
-
-// ImportInterviewsContacts imports interview-contact relationships from CSV file
-func ImportInterviewsContacts(app *pocketbase.PocketBase, filepath string, mappings *IDMappings) error {
-	file, err := os.Open(filepath)
-	if err != nil {
-		return fmt.Errorf("failed to open interviews-contacts CSV: %w", err)
-	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-
-	// Skip header
-	if _, err := reader.Read(); err != nil {
-		return fmt.Errorf("failed to read header: %w", err)
-	}
-
-	count := 0
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return fmt.Errorf("failed to read CSV record: %w", err)
-		}
-
-		// Skip if not enough fields
-		if len(record) < 3 {
-			continue
-		}
-
-		// CSV columns: interviewsContactId,interviewId,contactId
-		oldInterviewID := record[1]
-		oldContactID := record[2]
-
-		// Map old IDs to new PocketBase IDs
-		newInterviewID, ok := mappings.Interviews[oldInterviewID]
-		if !ok {
-			return fmt.Errorf("interview ID %s not found in mapping", oldInterviewID)
-		}
-
-		newContactID, ok := mappings.Contacts[oldContactID]
-		if !ok {
-			return fmt.Errorf("contact ID %s not found in mapping", oldContactID)
-		}
-
-		// Fetch the interview record and update its contacts field
-		interview, err := app.FindRecordById("interviews", newInterviewID)
-		if err != nil {
-			return fmt.Errorf("failed to find interview %s: %w", newInterviewID, err)
-		}
-
-		// Get existing contacts (if any)
-		existingContacts := interview.GetStringSlice("contacts")
-		// Add new contact if not already present
-		found := false
-		for _, c := range existingContacts {
-			if c == newContactID {
-				found = true
-				break
-			}
-		}
-		if !found {
-			existingContacts = append(existingContacts, newContactID)
-			interview.Set("contacts", existingContacts)
-			if err := app.Save(interview); err != nil {
-				return fmt.Errorf("failed to update interview %s with contact %s: %w", newInterviewID, newContactID, err)
-			}
-		}
-
-		count++
-	}
-
-	fmt.Printf("Imported %d interview-contact links\n", count)
-	return nil
-}
-
-// ImportStep represents a single import operation
-type ImportStep struct {
-	Name     string
-	Filename string
-	Filepath string
-	Fn       func(*pocketbase.PocketBase, string, *IDMappings) error
-}
-
-// GetImportSteps returns the import steps in the correct order (respecting foreign keys)
-func GetImportSteps() []ImportStep {
-	return []ImportStep{
-		{"companies", "reverse-ats - Companies.csv", "", ImportCompanies},
-		{"roles", "reverse-ats - Roles.csv", "", ImportRoles},
-		{"contacts", "reverse-ats - Contacts.csv", "", ImportContacts},
-		{"interviews", "reverse-ats - Interviews.csv", "", ImportInterviews},
-		{"interviews-contacts", "reverse-ats - InterviewsContacts.csv", "", ImportInterviewsContacts},
-	}
-}
-
-// ImportFromSteps imports data from a list of steps, skipping missing files
-// Returns a list of errors encountered (doesn't stop on first error)
-func ImportFromSteps(app *pocketbase.PocketBase, steps []ImportStep, skipMissing bool) []error {
-	var errors []error
-	mappings := NewIDMappings()
-
-	for _, step := range steps {
-		if step.Filepath == "" {
-			if skipMissing {
-				continue
-			}
-			err := fmt.Errorf("%s: no file provided", step.Name)
-			fmt.Printf("ERROR: %v\n", err)
-			errors = append(errors, err)
-			continue
-		}
-
-		// Check if file exists
-		if _, err := os.Stat(step.Filepath); os.IsNotExist(err) {
-			if skipMissing {
-				continue
-			}
-			err := fmt.Errorf("%s: file not found: %s", step.Name, step.Filepath)
-			fmt.Printf("ERROR: %v\n", err)
-			errors = append(errors, err)
-			continue
-		}
-
-		fmt.Printf("Importing %s from %s...\n", step.Name, step.Filepath)
-		if err := step.Fn(app, step.Filepath, mappings); err != nil {
-			wrappedErr := fmt.Errorf("%s: %w", step.Name, err)
-			fmt.Printf("ERROR: %v\n", wrappedErr)
-			errors = append(errors, wrappedErr)
-			continue
-		}
-	}
-
-	return errors
-}
-
-func ImportAll(app *pocketbase.PocketBase, dir string) error {
-	steps := GetImportSteps()
-
-	// Set filepaths for all steps
-	for i := range steps {
-		filepath := dir + "/" + steps[i].Filename
-		// Check if file exists
-		if _, err := os.Stat(filepath); os.IsNotExist(err) {
-			// Try without space in filename
-			filepath = strings.ReplaceAll(filepath, " ", "")
-			if _, err := os.Stat(filepath); os.IsNotExist(err) {
-				return fmt.Errorf("file not found: %s", steps[i].Filename)
-			}
-		}
-		steps[i].Filepath = filepath
-	}
-
-	// Import with error collection (but fail on first error for CLI compatibility)
-	errors := ImportFromSteps(app, steps, false)
-	if len(errors) > 0 {
-		return errors[0]
-	}
-
-	fmt.Println("\n✅ All data imported successfully!")
-	return nil
-}
+package importer
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+
+	"reverse-ats/internal/exporter"
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/util"
+)
+
+// ID mapping tables to handle conversion from old integer IDs to PocketBase string IDs
+type IDMappings struct {
+	Companies map[string]string // old ID -> new ID
+	Roles     map[string]string
+	Contacts  map[string]string
+	Interviews map[string]string
+}
+
+func NewIDMappings() *IDMappings {
+	return &IDMappings{
+		Companies: make(map[string]string),
+		Roles:     make(map[string]string),
+		Contacts:  make(map[string]string),
+		Interviews: make(map[string]string),
+	}
+}
+
+// emptyToNull converts "NULL" to empty string for PocketBase, and unescapes
+// text that is literally NULL
+func emptyToNull(s string) string {
+	return exporter.NullToEmpty(s)
+}
+
+// parseBool converts string to boolean
+func parseBool(s string) bool {
+	if s == "" || s == "NULL" {
+		return false
+	}
+	s = strings.ToLower(strings.TrimSpace(s))
+	return s == "true" || s == "yes" || s == "1" || s == "t" || s == "y"
+}
+
+// parseNumber parses a number from string, handling currency format
+func parseNumber(s string) float64 {
+	if s == "" || s == "NULL" {
+		return 0
+	}
+
+	// Remove currency symbols and commas
+	s = strings.TrimPrefix(s, "$")
+	s = strings.ReplaceAll(s, ",", "")
+
+	result, _ := strconv.ParseFloat(s, 64)
+	return result
+}
+
+// parseDate converts date string to ISO format (YYYY-MM-DD)
+// Handles "April 14, 2025", "2025-04-14" and exported "2025-04-14 00:00:00.000Z" formats.
+// Exported datetimes that aren't midnight UTC keep their time.
+func parseDate(s string) string {
+	if s == "" || s == "NULL" {
+		return ""
+	}
+
+	// Try ISO format first (YYYY-MM-DD)
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t.Format("2006-01-02")
+	}
+
+	// Try text format (January 2, 2006)
+	if t, err := time.Parse("January 2, 2006", s); err == nil {
+		return t.Format("2006-01-02")
+	}
+
+	// Try PocketBase datetime format, as written by the exporter
+	if t, err := time.Parse(types.DefaultDateLayout, s); err == nil {
+		if t.Equal(t.Truncate(24 * time.Hour)) {
+			return t.Format("2006-01-02")
+		}
+		return t.Format(types.DefaultDateLayout)
+	}
+
+	// Return as-is if can't parse (will likely fail validation)
+	return s
+}
+
+// dryRunID stands in for the PocketBase ID of a record a dry run didn't create
+const dryRunID = "dry-run"
+
+// ImportRun carries the state shared by the steps of one import
+type ImportRun struct {
+	App      core.App // The transaction of a real import
+	Mappings *IDMappings
+	DryRun   bool                     // Validate every row without saving anything
+	Report   *models.ImportFileReport // Report of the step being run
+
+	ctx      context.Context   // Saves are marked as an import, so they send no webhook events
+	tagIDs   map[string]string // Lowercased tag name -> ID, so each tag is looked up once
+	existing map[string]string // "collection:old ID" -> ID of an existing record, or ""
+}
+
+// NewImportRun creates the state of an import
+func NewImportRun(app core.App, dryRun bool) *ImportRun {
+	return &ImportRun{
+		App:      app,
+		Mappings: NewIDMappings(),
+		DryRun:   dryRun,
+		ctx:      util.WithImport(context.Background()),
+		tagIDs:   make(map[string]string),
+		existing: make(map[string]string),
+	}
+}
+
+// openCSV opens a CSV file and maps its header to the given columns
+func openCSV(filepath string, columns []column) (*os.File, *csv.Reader, *csvHeader, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to open CSV: %w", err)
+	}
+
+	reader := csv.NewReader(&quotedCRReader{r: bufio.NewReader(file)})
+	reader.FieldsPerRecord = -1
+
+	header, err := readHeader(reader, columns)
+	if err != nil {
+		file.Close()
+		return nil, nil, nil, err
+	}
+
+	return file, reader, header, nil
+}
+
+// quotedCR stands in for a carriage return inside a quoted field
+const quotedCR = "\uE000"
+
+// quotedCRReader replaces carriage returns inside quoted fields with quotedCR,
+// as encoding/csv drops the "\r" of "\r\n" line breaks in text. A quote
+// starts or ends a quoted field, or comes in pairs inside one, so flipping
+// on each quote tracks whether a byte is inside a field.
+type quotedCRReader struct {
+	r       *bufio.Reader
+	quoted  bool
+	pending string
+}
+
+func (q *quotedCRReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if q.pending != "" {
+			c := copy(p[n:], q.pending)
+			q.pending = q.pending[c:]
+			n += c
+			continue
+		}
+
+		b, err := q.r.ReadByte()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+
+		switch {
+		case b == '"':
+			q.quoted = !q.quoted
+		case b == '\r' && q.quoted:
+			q.pending = quotedCR
+			continue
+		}
+		p[n] = b
+		n++
+	}
+	return n, nil
+}
+
+// nextRow reads the next CSV row, returning nil at the end of the file
+func (run *ImportRun) nextRow(reader *csv.Reader, header *csvHeader) (*rowCheck, error) {
+	record, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV record: %w", err)
+	}
+
+	line, _ := reader.FieldPos(0)
+	return &rowCheck{header: header, record: record, line: line}, nil
+}
+
+// finishRow adds a row's problems to the report. A dry run carries on with the
+// next row, while a real import stops at the first invalid row.
+func (run *ImportRun) finishRow(row *rowCheck) error {
+	run.Report.Rows++
+	run.Report.Problems = append(run.Report.Problems, row.problems...)
+
+	if len(row.problems) > 0 && !run.DryRun {
+		p := row.problems[0]
+		return fmt.Errorf("line %d, %s: %s", p.Line, p.Column, p.Problem)
+	}
+	return nil
+}
+
+// rowID returns the old ID of a row: its id column, or its 1-based row number
+// when the file has no id column. Duplicate IDs are reported.
+func rowID(row *rowCheck, number int, mapping map[string]string) string {
+	oldID := fmt.Sprintf("%d", number)
+	if row.header.has("id") {
+		oldID = row.required("id")
+	}
+	if _, dup := mapping[oldID]; dup && oldID != "" {
+		row.problem("id", "duplicate ID %s", oldID)
+	}
+	return oldID
+}
+
+// setImportedTags tags a record with the comma-separated names in its tags column,
+// creating missing tags. Files without the column leave tags untouched.
+// A dry run only looks tags up, standing in for the ones it would create.
+func (run *ImportRun) setImportedTags(pbRecord *core.Record, row *rowCheck, names []string) error {
+	if !row.header.has("tags") {
+		return nil
+	}
+
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		key := strings.ToLower(name)
+		id, ok := run.tagIDs[key]
+		if !ok && run.DryRun {
+			tag, err := util.FindTagByName(run.App, name)
+			if err != nil {
+				return fmt.Errorf("failed to look up tag %q: %w", name, err)
+			}
+			id = dryRunID + ":" + key
+			if tag != nil {
+				id = tag.ID
+			}
+			run.tagIDs[key] = id
+		}
+		if !ok && !run.DryRun {
+			found, err := util.FindOrCreateTags(run.App, []string{name})
+			if err != nil {
+				return fmt.Errorf("failed to import tag %q: %w", name, err)
+			}
+			id = found[0]
+			run.tagIDs[key] = id
+		}
+		ids = append(ids, id)
+	}
+
+	pbRecord.Set("tags", ids)
+	return nil
+}
+
+// ImportCompanies imports companies from CSV file
+func ImportCompanies(run *ImportRun, filepath string) error {
+	file, reader, header, err := openCSV(filepath, companyColumns)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	collection, err := run.App.FindCollectionByNameOrId("companies")
+	if err != nil {
+		return fmt.Errorf("failed to find companies collection: %w", err)
+	}
+
+	for number := 1; ; number++ {
+		row, err := run.nextRow(reader, header)
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			break
+		}
+
+		oldID := rowID(row, number, run.Mappings.Companies)
+		name := row.required("name")
+		description := row.value("description")
+		companyURL := row.url("url")
+		linkedin := row.url("linkedin")
+		hqCity := row.value("hq_city")
+		hqState := row.value("hq_state")
+		tags := row.tags()
+
+		if err := run.finishRow(row); err != nil {
+			return err
+		}
+		if row.failed() {
+			run.Mappings.Companies[oldID] = dryRunID
+			continue
+		}
+
+		pbRecord, err := run.findImported(collection, row)
+		if err != nil {
+			return err
+		}
+		row.set(pbRecord, "name", name)
+		row.set(pbRecord, "description", description)
+		row.set(pbRecord, "url", companyURL)
+		row.set(pbRecord, "linkedin", linkedin)
+		row.set(pbRecord, "hq_city", hqCity)
+		row.set(pbRecord, "hq_state", hqState)
+
+		if err := run.setImportedTags(pbRecord, row, tags); err != nil {
+			return err
+		}
+
+		if _, err := run.saveImported(pbRecord); err != nil {
+			return fmt.Errorf("failed to save company %s: %w", name, err)
+		}
+
+		// Store ID mapping
+		run.Mappings.Companies[oldID] = mappedID(pbRecord)
+	}
+
+	return nil
+}
+
+// ImportContacts imports contacts from CSV file
+func ImportContacts(run *ImportRun, filepath string) error {
+	file, reader, header, err := openCSV(filepath, contactColumns)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	collection, err := run.App.FindCollectionByNameOrId("contacts")
+	if err != nil {
+		return fmt.Errorf("failed to find contacts collection: %w", err)
+	}
+
+	for number := 1; ; number++ {
+		row, err := run.nextRow(reader, header)
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			break
+		}
+
+		oldID := rowID(row, number, run.Mappings.Contacts)
+		companyID := run.reference(row, "company", run.Mappings.Companies, "companies")
+		firstName := row.required("first_name")
+		lastName := row.required("last_name")
+		role := row.value("role")
+		email := row.email("email")
+		phone := row.value("phone")
+		linkedin := row.url("linkedin")
+		notes := row.value("notes")
+		tags := row.tags()
+
+		if err := run.finishRow(row); err != nil {
+			return err
+		}
+		if row.failed() {
+			run.Mappings.Contacts[oldID] = dryRunID
+			continue
+		}
+
+		pbRecord, err := run.findImported(collection, row)
+		if err != nil {
+			return err
+		}
+		row.set(pbRecord, "company", companyID)
+		row.set(pbRecord, "first_name", firstName)
+		row.set(pbRecord, "last_name", lastName)
+		row.set(pbRecord, "role", role)
+		row.set(pbRecord, "email", email)
+		row.set(pbRecord, "phone", phone)
+		row.set(pbRecord, "linkedin", linkedin)
+		row.set(pbRecord, "notes", notes)
+
+		if err := run.setImportedTags(pbRecord, row, tags); err != nil {
+			return err
+		}
+
+		if _, err := run.saveImported(pbRecord); err != nil {
+			return fmt.Errorf("failed to save contact %s %s: %w", firstName, lastName, err)
+		}
+
+		// Store ID mapping
+		run.Mappings.Contacts[oldID] = mappedID(pbRecord)
+	}
+
+	return nil
+}
+
+// ImportRoles imports roles from CSV file
+func ImportRoles(run *ImportRun, filepath string) error {
+	file, reader, header, err := openCSV(filepath, roleColumns)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	collection, err := run.App.FindCollectionByNameOrId("roles")
+	if err != nil {
+		return fmt.Errorf("failed to find roles collection: %w", err)
+	}
+
+	for number := 1; ; number++ {
+		row, err := run.nextRow(reader, header)
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			break
+		}
+
+		oldID := rowID(row, number, run.Mappings.Roles)
+		companyID := run.reference(row, "company", run.Mappings.Companies, "companies")
+		name := row.required("name")
+
+		// Map legacy spellings (e.g. OFFERED, ON_SITE) to canonical values
+		status := models.NormalizeRoleStatus(row.value("status"))
+		if !models.IsValidRoleStatus(status) {
+			row.problem("status", "unknown status %q", row.value("status"))
+		}
+		location := models.NormalizeLocation(row.value("location"))
+		if !models.IsValidLocation(location) {
+			row.problem("location", "unknown location %q", row.value("location"))
+		}
+
+		roleURL := row.url("url")
+		description := row.value("description")
+		coverLetter := row.value("cover_letter")
+		applicationLocation := row.value("application_location")
+		appliedDate := row.date("applied_date", false)
+		closedDate := row.date("closed_date", false)
+		postedRangeMin := row.number("posted_range_min")
+		postedRangeMax := row.number("posted_range_max")
+		equity := row.boolean("equity")
+		workCity := row.value("work_city")
+		workState := row.value("work_state")
+		discovery := row.value("discovery")
+		referral := row.boolean("referral")
+		notes := row.value("notes")
+		tags := row.tags()
+
+		if err := run.finishRow(row); err != nil {
+			return err
+		}
+		if row.failed() {
+			run.Mappings.Roles[oldID] = dryRunID
+			continue
+		}
+
+		pbRecord, err := run.findImported(collection, row)
+		if err != nil {
+			return err
+		}
+		isNew := pbRecord.IsNew()
+		oldStatus := pbRecord.GetString("status")
+
+		row.set(pbRecord, "company", companyID)
+		row.set(pbRecord, "name", name)
+		row.set(pbRecord, "url", roleURL)
+		row.set(pbRecord, "description", description)
+		row.set(pbRecord, "cover_letter", coverLetter)
+		row.set(pbRecord, "application_location", applicationLocation)
+		row.set(pbRecord, "applied_date", appliedDate)
+		row.set(pbRecord, "closed_date", closedDate)
+		row.set(pbRecord, "posted_range_min", postedRangeMin)
+		row.set(pbRecord, "posted_range_max", postedRangeMax)
+		row.set(pbRecord, "equity", equity)
+		row.set(pbRecord, "work_city", workCity)
+		row.set(pbRecord, "work_state", workState)
+		row.set(pbRecord, "location", location)
+		row.set(pbRecord, "status", status)
+		row.set(pbRecord, "discovery", discovery)
+		row.set(pbRecord, "referral", referral)
+		row.set(pbRecord, "notes", notes)
+
+		if err := run.setImportedTags(pbRecord, row, tags); err != nil {
+			return err
+		}
+
+		saved, err := run.saveImported(pbRecord)
+		if err != nil {
+			return fmt.Errorf("failed to save role %s: %w", name, err)
+		}
+
+		// Record status changes as events. A new role's first status is dated
+		// to the application when known.
+		if saved && !run.DryRun {
+			changedAt := time.Now()
+			if applied, err := types.ParseDateTime(appliedDate); err == nil && !applied.IsZero() && isNew {
+				changedAt = applied.Time()
+			}
+			if err := util.RecordRoleStatusChange(run.App, pbRecord.Id, oldStatus, pbRecord.GetString("status"), util.StatusSourceImport, changedAt); err != nil {
+				return fmt.Errorf("failed to record status for role %s: %w", name, err)
+			}
+		}
+
+		// Store ID mapping
+		run.Mappings.Roles[oldID] = mappedID(pbRecord)
+	}
+
+	return nil
+}
+
+// ImportInterviews imports interviews from CSV file
+func ImportInterviews(run *ImportRun, filepath string) error {
+	file, reader, header, err := openCSV(filepath, interviewColumns)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	collection, err := run.App.FindCollectionByNameOrId("interviews")
+	if err != nil {
+		return fmt.Errorf("failed to find interviews collection: %w", err)
+	}
+
+	for number := 1; ; number++ {
+		row, err := run.nextRow(reader, header)
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			break
+		}
+
+		oldID := rowID(row, number, run.Mappings.Interviews)
+		roleID := run.reference(row, "role", run.Mappings.Roles, "roles")
+		date := row.date("date", true)
+		start := row.timeOfDay("start")
+		end := row.timeOfDay("end")
+		notes := row.value("notes")
+		tags := row.tags()
+
+		interviewType := models.NormalizeInterviewType(row.value("type"))
+		if !models.IsValidInterviewType(interviewType) {
+			row.problem("type", "unknown interview type %q", row.value("type"))
+		}
+
+		if err := run.finishRow(row); err != nil {
+			return err
+		}
+		if row.failed() {
+			run.Mappings.Interviews[oldID] = dryRunID
+			continue
+		}
+
+		pbRecord, err := run.findImported(collection, row)
+		if err != nil {
+			return err
+		}
+		row.set(pbRecord, "role", roleID)
+		row.set(pbRecord, "date", date)
+		row.set(pbRecord, "start", start)
+		row.set(pbRecord, "end", end)
+		row.set(pbRecord, "notes", notes)
+		row.set(pbRecord, "type", interviewType)
+
+		if err := run.setImportedTags(pbRecord, row, tags); err != nil {
+			return err
+		}
+
+		if _, err := run.saveImported(pbRecord); err != nil {
+			return fmt.Errorf("failed to save interview on %s: %w", date, err)
+		}
+
+		// Store ID mapping
+		run.Mappings.Interviews[oldID] = mappedID(pbRecord)
+	}
+
+	return nil
+}
+
+// ImportInterviewsContacts imports interview-contact relationships from CSV file.
+// Links are grouped by interview so each interview is saved once. Links that
+// already exist count as unchanged.
+func ImportInterviewsContacts(run *ImportRun, filepath string) error {
+	file, reader, header, err := openCSV(filepath, interviewContactColumns)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var interviewIDs []string
+	links := make(map[string][]string) // interview ID -> contact IDs, in file order
+	for {
+		row, err := run.nextRow(reader, header)
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			break
+		}
+
+		newInterviewID := run.reference(row, "interview", run.Mappings.Interviews, "interviews")
+		newContactID := run.reference(row, "contact", run.Mappings.Contacts, "contacts")
+
+		if err := run.finishRow(row); err != nil {
+			return err
+		}
+		if row.failed() {
+			continue
+		}
+
+		// Interviews a dry run would create have no contacts yet
+		if newInterviewID == dryRunID || newContactID == dryRunID {
+			run.Report.Created++
+			continue
+		}
+
+		if _, ok := links[newInterviewID]; !ok {
+			interviewIDs = append(interviewIDs, newInterviewID)
+		}
+		links[newInterviewID] = append(links[newInterviewID], newContactID)
+	}
+
+	if len(interviewIDs) == 0 {
+		return nil
+	}
+
+	interviews, err := run.App.FindRecordsByIds("interviews", interviewIDs)
+	if err != nil {
+		return fmt.Errorf("failed to find interviews: %w", err)
+	}
+
+	for _, interview := range interviews {
+		// Add the new contacts to the existing ones (if any), skipping duplicates
+		contacts := interview.GetStringSlice("contacts")
+		changed := false
+		for _, contactID := range links[interview.Id] {
+			if slices.Contains(contacts, contactID) {
+				run.Report.Unchanged++
+				continue
+			}
+			contacts = append(contacts, contactID)
+			run.Report.Created++
+			changed = true
+		}
+		if !changed || run.DryRun {
+			continue
+		}
+
+		interview.Set("contacts", contacts)
+		if err := run.App.SaveWithContext(run.ctx, interview); err != nil {
+			return fmt.Errorf("failed to update contacts of interview %s: %w", interview.Id, err)
+		}
+	}
+
+	return nil
+}
+
+// ImportStep represents a single import operation
+type ImportStep struct {
+	Name     string
+	Filename string
+	Filepath string
+	Fn       func(*ImportRun, string) error
+}
+
+// GetImportSteps returns the import steps in the correct order (respecting foreign keys)
+func GetImportSteps() []ImportStep {
+	return []ImportStep{
+		{"companies", "reverse-ats - Companies.csv", "", ImportCompanies},
+		{"roles", "reverse-ats - Roles.csv", "", ImportRoles},
+		{"contacts", "reverse-ats - Contacts.csv", "", ImportContacts},
+		{"interviews", "reverse-ats - Interviews.csv", "", ImportInterviews},
+		{"interviews-contacts", "reverse-ats - InterviewsContacts.csv", "", ImportInterviewsContacts},
+	}
+}
+
+// runStep runs one import step and adds its file report to the report
+func (run *ImportRun) runStep(step ImportStep, report *models.ImportReport) error {
+	if run.DryRun {
+		fmt.Printf("Checking %s from %s...\n", step.Name, step.Filepath)
+	} else {
+		fmt.Printf("Importing %s from %s...\n", step.Name, step.Filepath)
+	}
+
+	run.Report = &models.ImportFileReport{Step: step.Name}
+	err := step.Fn(run, step.Filepath)
+	if err != nil {
+		run.Report.Error = err.Error()
+	}
+	report.Files = append(report.Files, *run.Report)
+
+	if err != nil {
+		wrappedErr := fmt.Errorf("%s: %w", step.Name, err)
+		fmt.Printf("ERROR: %v\n", wrappedErr)
+		return wrappedErr
+	}
+
+	if run.DryRun {
+		fmt.Printf("Checked %d %s rows, %d problems\n", run.Report.Rows, step.Name, len(run.Report.Problems))
+	}
+	fmt.Printf("%s: %d created, %d updated, %d unchanged\n", step.Name, run.Report.Created, run.Report.Updated, run.Report.Unchanged)
+	return nil
+}
+
+// ImportFromSteps imports data from a list of steps, skipping missing files.
+// A real import runs in a single transaction and stops at the first error,
+// leaving the database untouched. With dryRun every file is validated and
+// reported on without saving anything.
+// Returns the per-file report and the errors encountered.
+func ImportFromSteps(app *pocketbase.PocketBase, steps []ImportStep, skipMissing bool, dryRun bool) (*models.ImportReport, []error) {
+	var errors []error
+	report := &models.ImportReport{DryRun: dryRun}
+
+	// Check all files up front so nothing is imported when one is missing
+	var present []ImportStep
+	for _, step := range steps {
+		if step.Filepath == "" {
+			if skipMissing {
+				continue
+			}
+			err := fmt.Errorf("%s: no file provided", step.Name)
+			fmt.Printf("ERROR: %v\n", err)
+			errors = append(errors, err)
+			continue
+		}
+
+		// Check if file exists
+		if _, err := os.Stat(step.Filepath); os.IsNotExist(err) {
+			if skipMissing {
+				continue
+			}
+			err := fmt.Errorf("%s: file not found: %s", step.Name, step.Filepath)
+			fmt.Printf("ERROR: %v\n", err)
+			errors = append(errors, err)
+			continue
+		}
+
+		present = append(present, step)
+	}
+	if len(errors) > 0 {
+		return report, errors
+	}
+
+	// A dry run checks every file, even after one fails
+	if dryRun {
+		run := NewImportRun(app, true)
+		for _, step := range present {
+			if err := run.runStep(step, report); err != nil {
+				errors = append(errors, err)
+			}
+		}
+		return report, errors
+	}
+
+	err := app.RunInTransaction(func(txApp core.App) error {
+		run := NewImportRun(txApp, false)
+		for _, step := range present {
+			if err := run.runStep(step, report); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Println("Import rolled back, nothing was saved")
+		errors = append(errors, err)
+	}
+
+	return report, errors
+}
+
+// ImportAll imports every CSV file of a directory, all of which must be there
+func ImportAll(app *pocketbase.PocketBase, dir string) error {
+	if _, err := ImportDir(app, dir, false); err != nil {
+		return err
+	}
+
+	fmt.Println("\n✅ All data imported successfully!")
+	return nil
+}
+
+// ImportDir imports or, with dryRun, checks every CSV file of a directory,
+// all of which must be there
+func ImportDir(app *pocketbase.PocketBase, dir string, dryRun bool) (*models.ImportReport, error) {
+	steps := GetImportSteps()
+
+	// Set filepaths for all steps
+	for i := range steps {
+		path, ok := findStepFile(dir, steps[i].Filename)
+		if !ok {
+			return nil, fmt.Errorf("file not found: %s", steps[i].Filename)
+		}
+		steps[i].Filepath = path
+	}
+
+	// Import everything or nothing
+	report, errors := ImportFromSteps(app, steps, false, dryRun)
+	if len(errors) > 0 {
+		return report, errors[0]
+	}
+	return report, nil
+}
+
+// ImportFile imports or, with dryRun, checks a single CSV file. Its name
+// tells which file of an export it is, as in "reverse-ats - Roles.csv".
+func ImportFile(app *pocketbase.PocketBase, path string, dryRun bool) (*models.ImportReport, error) {
+	steps := GetImportSteps()
+
+	name := filepath.Base(path)
+	var names []string
+	for i := range steps {
+		if name == steps[i].Filename || name == strings.ReplaceAll(steps[i].Filename, " ", "") {
+			steps[i].Filepath = path
+			report, errors := ImportFromSteps(app, steps, true, dryRun)
+			if len(errors) > 0 {
+				return report, errors[0]
+			}
+			return report, nil
+		}
+		names = append(names, steps[i].Filename)
+	}
+
+	return nil, fmt.Errorf("unknown CSV file %q, expected one of: %s", name, strings.Join(names, ", "))
+}
+
+// findStepFile returns the path of a step's file in dir, also trying the
+// filename without spaces
+func findStepFile(dir, filename string) (string, bool) {
+	for _, name := range []string{filename, strings.ReplaceAll(filename, " ", "")} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
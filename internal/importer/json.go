@@ -0,0 +1,203 @@
+package importer
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/pocketbase/pocketbase/tools/list"
+
+	"reverse-ats/internal/exporter"
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/util"
+)
+
+// ImportJSON restores a JSON export. Records keep their IDs so relations stay
+// intact; records that already exist are updated. Uploaded files are read from
+// the files folder next to the JSON file. Like the CSV import it runs in a
+// single transaction, and a dry run only checks and counts.
+func ImportJSON(app *pocketbase.PocketBase, path string, dryRun bool) (*models.ImportReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON export: %w", err)
+	}
+
+	var doc exporter.JSONDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON export: %w", err)
+	}
+	if doc.SchemaVersion < 1 || doc.SchemaVersion > exporter.JSONSchemaVersion {
+		return nil, fmt.Errorf("unsupported JSON export schema version %d (this version reads up to %d)", doc.SchemaVersion, exporter.JSONSchemaVersion)
+	}
+
+	report := &models.ImportReport{DryRun: dryRun}
+	filesDir := filepath.Join(filepath.Dir(path), exporter.FilesDir)
+
+	restore := func(run *ImportRun) error {
+		// Tags matched by name to an existing tag with another ID
+		tagIDs := make(map[string]string)
+
+		for _, name := range exporter.JSONCollections {
+			run.Report = &models.ImportFileReport{Step: name}
+			err := run.restoreCollection(name, doc.Collections[name], filesDir, tagIDs)
+			report.Files = append(report.Files, *run.Report)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			fmt.Printf("%s: %d created, %d updated, %d unchanged\n", name, run.Report.Created, run.Report.Updated, run.Report.Unchanged)
+		}
+		return nil
+	}
+
+	if dryRun {
+		return report, restore(NewImportRun(app, true))
+	}
+
+	err = app.RunInTransaction(func(txApp core.App) error {
+		return restore(NewImportRun(txApp, false))
+	})
+	if err != nil {
+		fmt.Println("Import rolled back, nothing was saved")
+	}
+
+	return report, err
+}
+
+// restoreCollection creates or updates the records of one collection of a JSON export
+func (run *ImportRun) restoreCollection(name string, rows []map[string]any, filesDir string, tagIDs map[string]string) error {
+	collection, err := run.App.FindCollectionByNameOrId(name)
+	if err != nil {
+		return fmt.Errorf("failed to find %s collection: %w", name, err)
+	}
+
+	for i, fields := range rows {
+		row := &rowCheck{line: i + 1}
+
+		id, _ := fields["id"].(string)
+		if id == "" || !isPathElement(id) {
+			if id == "" {
+				row.problem("id", "record has no id")
+			} else {
+				row.problem("id", "invalid id %q", id)
+			}
+			if err := run.finishRow(row); err != nil {
+				return err
+			}
+			continue
+		}
+
+		record, err := run.App.FindRecordById(collection, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			record = core.NewRecord(collection)
+			record.Id = id
+		} else if err != nil {
+			return fmt.Errorf("failed to look up %s: %w", id, err)
+		}
+
+		// The tags name index is case-insensitive, so a tag may already exist under another ID
+		if name == util.CollectionTags && record.IsNew() {
+			tagName, _ := fields["name"].(string)
+			tag, err := util.FindTagByName(run.App, tagName)
+			if err != nil {
+				return fmt.Errorf("failed to look up tag %q: %w", tagName, err)
+			}
+			if tag != nil {
+				tagIDs[id] = tag.ID
+				run.Report.Rows++
+				run.Report.Unchanged++
+				continue
+			}
+		}
+
+		for _, field := range collection.Fields {
+			fieldName := field.GetName()
+			value, ok := fields[fieldName]
+			if !ok || fieldName == "id" || field.Type() == core.FieldTypeAutodate {
+				continue
+			}
+
+			switch {
+			case field.Type() == core.FieldTypeFile:
+				value = run.restoreFiles(row, record, fieldName, value, filepath.Join(filesDir, name, id))
+			case fieldName == "tags" && len(tagIDs) > 0:
+				value = remapIDs(value, tagIDs)
+			}
+			record.Set(fieldName, value)
+		}
+
+		if err := run.finishRow(row); err != nil {
+			return err
+		}
+		if row.failed() {
+			continue
+		}
+
+		if _, err := run.saveImported(record); err != nil {
+			return fmt.Errorf("failed to save %s: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// restoreFiles returns the value of a file field, attaching the exported
+// files the record doesn't have yet. Missing files are reported.
+func (run *ImportRun) restoreFiles(row *rowCheck, record *core.Record, field string, value any, dir string) any {
+	current := record.GetStringSlice(field)
+
+	var files []any
+	for _, filename := range list.ToUniqueStringSlice(value) {
+		if slices.Contains(current, filename) {
+			files = append(files, filename)
+			continue
+		}
+		if !isPathElement(filename) {
+			row.problem(field, "invalid file name %q", filename)
+			continue
+		}
+
+		path := filepath.Join(dir, filename)
+		if _, err := os.Stat(path); err != nil {
+			row.problem(field, "file %s is missing from the export", filename)
+			continue
+		}
+		if run.DryRun {
+			continue
+		}
+
+		file, err := filesystem.NewFileFromPath(path)
+		if err != nil {
+			row.problem(field, "failed to read file %s: %v", filename, err)
+			continue
+		}
+		file.Name = filename // Keep the stored name so the export round-trips
+		files = append(files, file)
+	}
+
+	return files
+}
+
+// isPathElement reports whether a name from the JSON file is a single path
+// element, so joining it to the files folder can't reach outside of it
+func isPathElement(name string) bool {
+	return name != "." && !strings.Contains(name, "..") && !strings.ContainsAny(name, `/\`) && filepath.Base(name) == name
+}
+
+// remapIDs replaces the IDs in a relation value that were matched to other records
+func remapIDs(value any, remap map[string]string) []string {
+	ids := list.ToUniqueStringSlice(value)
+	for i, id := range ids {
+		if newID, ok := remap[id]; ok {
+			ids[i] = newID
+		}
+	}
+	return ids
+}
@@ -0,0 +1,80 @@
+package importer_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"reverse-ats/internal/exporter"
+	"reverse-ats/internal/importer"
+)
+
+func TestJSONRestoreStaysInFilesFolder(t *testing.T) {
+	tests := []struct {
+		name     string
+		document map[string]any
+		want     string
+	}{
+		{"id", map[string]any{"id": "../../..", "label": "Resume", "kind": "RESUME", "file": []string{"secret.txt"}}, `invalid id "../../.."`},
+		{"file name", map[string]any{"id": "doc1abcdefghijk", "label": "Resume", "kind": "RESUME", "file": []string{"../../../secret.txt"}}, `invalid file name "../../../secret.txt"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("not part of the export"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			doc := exporter.JSONDocument{
+				SchemaVersion: exporter.JSONSchemaVersion,
+				Collections:   map[string][]map[string]any{"documents": {tt.document}},
+			}
+			data, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(dir, exporter.JSONFilename)
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			app := newTestApp(t)
+			if _, err := importer.ImportJSON(app, path, false); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ImportJSON error = %v, want %s", err, tt.want)
+			}
+			if n, _ := app.CountRecords("documents"); n != 0 {
+				t.Errorf("restored %d documents, want none", n)
+			}
+		})
+	}
+}
+
+func TestJSONExportLeavesOutWebhookSecrets(t *testing.T) {
+	app := newTestApp(t)
+	create(t, app, "webhooks", map[string]any{
+		"url":    "https://hooks.example.com/reverse-ats",
+		"secret": "s3cret",
+		"events": []string{"role.created"},
+		"active": true,
+	})
+
+	var b strings.Builder
+	if err := exporter.WriteJSON(app, &b); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+
+	var doc exporter.JSONDocument
+	if err := json.Unmarshal([]byte(b.String()), &doc); err != nil {
+		t.Fatal(err)
+	}
+	webhooks := doc.Collections["webhooks"]
+	if len(webhooks) != 1 || webhooks[0]["url"] != "https://hooks.example.com/reverse-ats" {
+		t.Fatalf("exported webhooks %v, want the one webhook", webhooks)
+	}
+	if _, ok := webhooks[0]["secret"]; ok || strings.Contains(b.String(), "s3cret") {
+		t.Error("the export holds the webhook secret")
+	}
+}
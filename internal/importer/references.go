@@ -0,0 +1,90 @@
+package importer
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// reference maps the old ID in a column to a PocketBase ID. IDs missing from
+// the files of this import are looked up among existing records, so a file
+// can be imported on its own.
+func (run *ImportRun) reference(row *rowCheck, column string, mapping map[string]string, collection string) string {
+	oldID := row.value(column)
+	if oldID == "" {
+		row.problem(column, "value is required")
+		return ""
+	}
+
+	if newID, ok := mapping[oldID]; ok {
+		return newID
+	}
+
+	newID, err := run.findExisting(collection, oldID)
+	if err != nil {
+		row.problem(column, "%v", err)
+		return ""
+	}
+	if newID == "" {
+		row.problem(column, "%s ID %s not found in the imported files or the database", column, oldID)
+	}
+	return newID
+}
+
+// findExisting looks up an existing record by PocketBase ID, external ID or,
+// for companies, exact name. Returns "" if there is none.
+func (run *ImportRun) findExisting(collection, value string) (string, error) {
+	key := collection + ":" + value
+	if id, ok := run.existing[key]; ok {
+		return id, nil
+	}
+
+	id, err := run.queryExisting(collection, value)
+	if err != nil {
+		return "", err
+	}
+
+	run.existing[key] = id
+	return id, nil
+}
+
+func (run *ImportRun) queryExisting(collection, value string) (string, error) {
+	if record, err := run.App.FindRecordById(collection, value); err == nil {
+		return record.Id, nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("failed to look up %s %s: %w", collection, value, err)
+	}
+
+	record := &core.Record{}
+	err := run.App.RecordQuery(collection).
+		AndWhere(dbx.HashExp{"external_id": value}).
+		AndWhere(dbx.NewExp("external_id != ''")). // Lets SQLite use the external_id index
+		Limit(1).
+		One(record)
+	if err == nil {
+		return record.Id, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("failed to look up %s %s: %w", collection, value, err)
+	}
+
+	if collection != "companies" {
+		return "", nil
+	}
+
+	// Spreadsheets often refer to companies by name
+	records, err := run.App.FindRecordsByFilter(collection, "name = {:name}", "", 2, 0, dbx.Params{"name": value})
+	if err != nil {
+		return "", fmt.Errorf("failed to look up company %s: %w", value, err)
+	}
+	switch len(records) {
+	case 0:
+		return "", nil
+	case 1:
+		return records[0].Id, nil
+	}
+	return "", fmt.Errorf("more than one company is named %q", value)
+}
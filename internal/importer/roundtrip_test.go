@@ -0,0 +1,269 @@
+package importer_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+
+	"reverse-ats/internal/exporter"
+	"reverse-ats/internal/importer"
+	_ "reverse-ats/pb_migrations"
+)
+
+// newTestApp creates an empty app with the reverse-ats schema
+func newTestApp(t testing.TB) *pocketbase.PocketBase {
+	t.Helper()
+
+	app, err := tests.NewTestAppWithConfig(core.BaseAppConfig{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create test app: %v", err)
+	}
+	t.Cleanup(app.Cleanup)
+
+	return &pocketbase.PocketBase{App: app}
+}
+
+// create saves a record with the given fields
+func create(t *testing.T, app core.App, collection string, fields map[string]any) *core.Record {
+	t.Helper()
+
+	coll, err := app.FindCollectionByNameOrId(collection)
+	if err != nil {
+		t.Fatalf("failed to find %s: %v", collection, err)
+	}
+
+	record := core.NewRecord(coll)
+	for name, value := range fields {
+		record.Set(name, value)
+	}
+	if err := app.Save(record); err != nil {
+		t.Fatalf("failed to save %s %v: %v", collection, fields, err)
+	}
+	return record
+}
+
+// seed fills an app with records holding the values that are easy to lose in a CSV
+func seed(t *testing.T, app core.App) {
+	t.Helper()
+
+	remote := create(t, app, "tags", map[string]any{"name": "remote"})
+	urgent := create(t, app, "tags", map[string]any{"name": "Urgent"})
+
+	acme := create(t, app, "companies", map[string]any{
+		"name":        `Acme, "Inc."`,
+		"description": "Line one\r\nLine two\nLine three\r\n",
+		"url":         "https://acme.example.com/?a=1&b=2",
+		"hq_city":     "  São Paulo ",
+		"hq_state":    "NULL",
+		"tags":        []string{urgent.Id, remote.Id},
+	})
+	bare := create(t, app, "companies", map[string]any{
+		"name":     "NULL",
+		"linkedin": "https://www.linkedin.com/company/bare",
+		"hq_state": `\NULL`,
+	})
+
+	engineer := create(t, app, "roles", map[string]any{
+		"company":              acme.Id,
+		"name":                 "Staff Engineer",
+		"url":                  "https://acme.example.com/jobs/1",
+		"description":          "\tIndented\n\n\"quoted\" text, with commas",
+		"cover_letter":         "Dear Acme,\r\n\r\nHire me.",
+		"application_location": "Careers page",
+		"applied_date":         "2025-10-20 00:00:00.000Z",
+		"closed_date":          "2025-11-02 15:30:00.000Z",
+		"posted_range_min":     0,
+		"posted_range_max":     187500.5,
+		"equity":               true,
+		"work_city":            "Austin",
+		"work_state":           "TX",
+		"location":             "HYBRID",
+		"status":               "INTERVIEWING",
+		"discovery":            "LinkedIn",
+		"referral":             true,
+		"notes":                "",
+		"tags":                 []string{remote.Id},
+	})
+	create(t, app, "roles", map[string]any{
+		"company": bare.Id,
+		"name":    "Anything",
+	})
+
+	recruiter := create(t, app, "contacts", map[string]any{
+		"company":    acme.Id,
+		"first_name": "Zoë",
+		"last_name":  "O'Brien",
+		"role":       "Recruiter",
+		"email":      "zoe@acme.example.com",
+		"phone":      "+1 (555) 010-0000",
+		"linkedin":   "https://www.linkedin.com/in/zoe",
+		"notes":      "NULL",
+		"tags":       []string{urgent.Id},
+	})
+	manager := create(t, app, "contacts", map[string]any{
+		"company":    bare.Id,
+		"first_name": "Sam",
+		"last_name":  "Lee",
+	})
+
+	create(t, app, "interviews", map[string]any{
+		"role":     engineer.Id,
+		"date":     "2025-10-27 00:00:00.000Z",
+		"start":    "09:30",
+		"end":      "10:15",
+		"type":     "TECH_SCREEN",
+		"notes":    "Bring \"examples\"\nand questions",
+		"contacts": []string{manager.Id, recruiter.Id},
+		"tags":     []string{urgent.Id},
+	})
+	create(t, app, "interviews", map[string]any{
+		"role":  engineer.Id,
+		"date":  "2025-10-28 17:00:00.000Z",
+		"start": "2:00 PM",
+		"end":   "3:00 PM",
+		"type":  "LOOP",
+	})
+}
+
+// roundTrip exports an app to CSV files and imports them into a fresh app
+func roundTrip(t *testing.T, src *pocketbase.PocketBase) *pocketbase.PocketBase {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := exporter.ExportAll(src, dir); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	dst := newTestApp(t)
+	if err := importer.ImportAll(dst, dir); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	return dst
+}
+
+// skippedFields are not part of the CSV round trip: IDs are mapped to new
+// records, and documents and uploaded files are only restored from JSON exports
+var skippedFields = map[string]bool{
+	"id":                    true,
+	"external_id":           true,
+	"resume_file":           true,
+	"cover_letter_file":     true,
+	"resume_document":       true,
+	"cover_letter_document": true,
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	src := newTestApp(t)
+	seed(t, src)
+
+	dst := roundTrip(t, src)
+
+	// Old ID -> new ID, per collection, from the external IDs set by the import
+	newIDs := make(map[string]map[string]string)
+
+	for _, collection := range []string{"companies", "roles", "contacts", "interviews"} {
+		t.Run(collection, func(t *testing.T) {
+			want, err := src.FindRecordsByFilter(collection, "", "id", -1, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := dst.FindRecordsByFilter(collection, "", "id", -1, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("imported %d records, want %d", len(got), len(want))
+			}
+
+			imported := make(map[string]*core.Record)
+			newIDs[collection] = make(map[string]string)
+			for _, record := range got {
+				imported[record.GetString("external_id")] = record
+				newIDs[collection][record.GetString("external_id")] = record.Id
+			}
+
+			for _, original := range want {
+				record, ok := imported[original.Id]
+				if !ok {
+					t.Errorf("%s was not imported", original.Id)
+					continue
+				}
+
+				for _, field := range original.Collection().Fields {
+					name := field.GetName()
+					if skippedFields[name] {
+						continue
+					}
+
+					wantValue := original.Get(name)
+					gotValue := record.Get(name)
+					if relation, ok := field.(*core.RelationField); ok {
+						wantValue = relatedValues(t, src, relation, original, newIDs)
+						gotValue = relatedValues(t, dst, relation, record, nil)
+					}
+
+					if !reflect.DeepEqual(gotValue, wantValue) {
+						t.Errorf("%s %s: got %#v, want %#v", original.Id, name, gotValue, wantValue)
+					}
+				}
+			}
+		})
+	}
+}
+
+// relatedValues returns comparable values for a relation: tag names, or
+// record IDs translated to the imported records when newIDs is given
+func relatedValues(t *testing.T, app core.App, relation *core.RelationField, record *core.Record, newIDs map[string]map[string]string) []string {
+	t.Helper()
+
+	related, err := app.FindCollectionByNameOrId(relation.CollectionId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := []string{}
+	for _, id := range record.GetStringSlice(relation.Name) {
+		switch {
+		case related.Name == "tags":
+			tag, err := app.FindRecordById("tags", id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			values = append(values, tag.GetString("name"))
+		case newIDs != nil:
+			values = append(values, newIDs[related.Name][id])
+		default:
+			values = append(values, id)
+		}
+	}
+	return values
+}
+
+func TestCSVReimportIsUnchanged(t *testing.T) {
+	src := newTestApp(t)
+	seed(t, src)
+
+	dir := t.TempDir()
+	if err := exporter.ExportAll(src, dir); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	// Importing an export into the app it came from matches every row to its record
+	steps := importer.GetImportSteps()
+	for i := range steps {
+		steps[i].Filepath = dir + "/" + steps[i].Filename
+	}
+	report, errs := importer.ImportFromSteps(src, steps, false, false)
+	if len(errs) > 0 {
+		t.Fatalf("import failed: %v", errs)
+	}
+
+	for _, file := range report.Files {
+		if file.Created != 0 || file.Updated != 0 {
+			t.Errorf("%s: %d created, %d updated, want every row unchanged", file.Step, file.Created, file.Updated)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package importer
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// findImported returns the record a row was imported into before, or a new record.
+// Rows are matched on the external_id saved from the file's id column; files
+// written by the exporter carry PocketBase IDs, so those are matched too.
+// Files without an id column always create records.
+func (run *ImportRun) findImported(collection *core.Collection, row *rowCheck) (*core.Record, error) {
+	externalID := ""
+	if row.header.has("id") {
+		externalID = row.value("id")
+	}
+	if externalID == "" {
+		return core.NewRecord(collection), nil
+	}
+
+	// The external_id index only holds non-empty values, and SQLite only uses
+	// it when the query says so
+	record := &core.Record{}
+	err := run.App.RecordQuery(collection).
+		AndWhere(dbx.HashExp{"external_id": externalID}).
+		AndWhere(dbx.NewExp("external_id != ''")).
+		Limit(1).
+		One(record)
+	if err == nil {
+		return record, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to look up %s %s: %w", collection.Name, externalID, err)
+	}
+
+	record, err = run.App.FindRecordById(collection, externalID)
+	if err == nil {
+		return record, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to look up %s %s: %w", collection.Name, externalID, err)
+	}
+
+	record = core.NewRecord(collection)
+	record.Set("external_id", externalID)
+	return record, nil
+}
+
+// recordChanged reports whether any field of an existing record differs from its stored value
+func recordChanged(record *core.Record) bool {
+	original := record.Original()
+	for _, field := range record.Collection().Fields {
+		name := field.GetName()
+		if fmt.Sprint(original.Get(name)) != fmt.Sprint(record.Get(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// saveImported saves an imported record unless none of its fields changed, and
+// counts it as created, updated or unchanged. A dry run only counts.
+// Returns whether the record was created or updated.
+func (run *ImportRun) saveImported(record *core.Record) (bool, error) {
+	switch {
+	case record.IsNew():
+		run.Report.Created++
+	case recordChanged(record):
+		run.Report.Updated++
+	default:
+		run.Report.Unchanged++
+		return false, nil
+	}
+
+	if run.DryRun {
+		return true, nil
+	}
+	return true, run.App.SaveWithContext(run.ctx, record)
+}
+
+// mappedID returns the ID to map a row's old ID to. New records
+// of a dry run are never saved so they have no ID.
+func mappedID(record *core.Record) string {
+	if record.Id == "" {
+		return dryRunID
+	}
+	return record.Id
+}
@@ -0,0 +1,161 @@
+package importer
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+
+	"reverse-ats/internal/models"
+)
+
+const maxTagNameLength = 100 // Matches the tags collection's name field
+
+// rowCheck reads the values of one CSV row and collects the problems found in them
+type rowCheck struct {
+	header   *csvHeader
+	record   []string
+	line     int
+	problems []models.ImportProblem
+}
+
+// problem records a problem with a column of the row
+func (c *rowCheck) problem(column, format string, args ...any) {
+	c.problems = append(c.problems, models.ImportProblem{
+		Line:    c.line,
+		Column:  column,
+		Problem: fmt.Sprintf(format, args...),
+	})
+}
+
+// failed reports whether any problem was found in the row
+func (c *rowCheck) failed() bool {
+	return len(c.problems) > 0
+}
+
+// set sets a record field from a column. Columns missing from the file
+// leave the fields of existing records untouched.
+func (c *rowCheck) set(record *core.Record, column string, value any) {
+	if record.IsNew() || c.header.has(column) {
+		record.Set(column, value)
+	}
+}
+
+// value returns a column's value, with "NULL" read as empty
+func (c *rowCheck) value(column string) string {
+	return emptyToNull(c.header.value(c.record, column))
+}
+
+// required returns a column's value, reporting it when empty
+func (c *rowCheck) required(column string) string {
+	v := c.value(column)
+	if strings.TrimSpace(v) == "" {
+		c.problem(column, "value is required")
+	}
+	return v
+}
+
+// date returns a column's value as YYYY-MM-DD, or as a PocketBase datetime
+// when an exported date has a time
+func (c *rowCheck) date(column string, required bool) string {
+	raw := c.value(column)
+	if raw == "" {
+		if required {
+			c.problem(column, "value is required")
+		}
+		return ""
+	}
+
+	v := parseDate(raw)
+	_, dateErr := time.Parse("2006-01-02", v)
+	_, datetimeErr := time.Parse(types.DefaultDateLayout, v)
+	if dateErr != nil && datetimeErr != nil {
+		c.problem(column, "invalid date %q (use YYYY-MM-DD or \"January 2, 2006\")", raw)
+	}
+	return v
+}
+
+// timeOfDay returns a required HH:MM column value
+func (c *rowCheck) timeOfDay(column string) string {
+	v := c.required(column)
+	if v == "" {
+		return ""
+	}
+
+	for _, layout := range []string{"15:04", "15:04:05", time.Kitchen, "3:04 PM"} {
+		if _, err := time.Parse(layout, v); err == nil {
+			return v
+		}
+	}
+	c.problem(column, "invalid time %q (use HH:MM)", v)
+	return v
+}
+
+// url returns a column's value, reporting anything but an http(s) URL
+func (c *rowCheck) url(column string) string {
+	v := c.value(column)
+	if v == "" {
+		return ""
+	}
+
+	u, err := url.Parse(v)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		c.problem(column, "invalid URL %q", v)
+	}
+	return v
+}
+
+// email returns a column's value, reporting malformed addresses
+func (c *rowCheck) email(column string) string {
+	v := c.value(column)
+	if v == "" {
+		return ""
+	}
+
+	if addr, err := mail.ParseAddress(v); err != nil || addr.Address != v {
+		c.problem(column, "invalid email %q", v)
+	}
+	return v
+}
+
+// number returns a column's value, accepting "$150,000.00" style amounts
+func (c *rowCheck) number(column string) float64 {
+	v := c.value(column)
+	if v == "" {
+		return 0
+	}
+
+	digits := strings.ReplaceAll(strings.TrimPrefix(v, "$"), ",", "")
+	if _, err := strconv.ParseFloat(digits, 64); err != nil {
+		c.problem(column, "invalid number %q", v)
+		return 0
+	}
+	return parseNumber(v)
+}
+
+// boolean returns a column's value as a boolean
+func (c *rowCheck) boolean(column string) bool {
+	v := c.value(column)
+	switch strings.ToLower(v) {
+	case "", "false", "no", "0", "f", "n", "true", "yes", "1", "t", "y":
+		return parseBool(v)
+	}
+	c.problem(column, "invalid boolean %q (use true or false)", v)
+	return false
+}
+
+// tags returns the tag names in the tags column
+func (c *rowCheck) tags() []string {
+	names := models.SplitTagNames(c.header.value(c.record, "tags"))
+	for _, name := range names {
+		if len([]rune(name)) > maxTagNameLength {
+			c.problem("tags", "tag %q is longer than %d characters", name, maxTagNameLength)
+		}
+	}
+	return names
+}
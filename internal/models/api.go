@@ -0,0 +1,17 @@
+package models
+
+// APIList is one page of a list returned by the JSON API
+type APIList[T any] struct {
+	Items      []T
+	Page       int // From 1
+	PerPage    int
+	TotalItems int
+	TotalPages int
+}
+
+// APIError is the body of every JSON API error response
+type APIError struct {
+	Status  int
+	Message string
+	Fields  map[string]string `json:",omitempty"` // Problems with the fields of a request body, by field name
+}
@@ -0,0 +1,34 @@
+package models
+
+// Bulk actions on the rows picked in a list
+const (
+	BulkActionStatus     = "status"
+	BulkActionClosedDate = "closed_date"
+	BulkActionTag        = "tag"
+	BulkActionExport     = "export"
+	BulkActionDelete     = "delete"
+)
+
+// BulkActions lists the bulk actions of the companies, contacts and interviews lists
+var BulkActions = []string{BulkActionTag, BulkActionExport, BulkActionDelete}
+
+// RoleBulkActions lists the bulk actions of the roles list
+var RoleBulkActions = []string{BulkActionStatus, BulkActionClosedDate, BulkActionTag, BulkActionExport, BulkActionDelete}
+
+// BulkActionLabel returns a human-readable label for a bulk action
+func BulkActionLabel(action string) string {
+	switch action {
+	case BulkActionStatus:
+		return "Change status"
+	case BulkActionClosedDate:
+		return "Set closed date"
+	case BulkActionTag:
+		return "Add tag"
+	case BulkActionExport:
+		return "Export selected"
+	case BulkActionDelete:
+		return "Delete"
+	default:
+		return action
+	}
+}
@@ -0,0 +1,9 @@
+package models
+
+// Capture is the role and company a job posting sent by the bookmarklet was saved as
+type Capture struct {
+	Role           Role
+	Company        Company
+	RoleCreated    bool // False when a role with the posting's URL already existed
+	CompanyCreated bool
+}
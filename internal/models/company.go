@@ -1,14 +1,15 @@
-package models
-
-// Company represents a company for templating
-type Company struct {
-	ID          string
-	Name        string
-	Description string
-	Url         string
-	Linkedin    string
-	HqCity      string
-	HqState     string
-	CreatedAt   string
-	UpdatedAt   string
-}
+package models
+
+// Company represents a company for templating
+type Company struct {
+	ID          string
+	Name        string
+	Description string
+	Url         string
+	Linkedin    string
+	HqCity      string
+	HqState     string
+	Tags        []Tag
+	CreatedAt   string
+	UpdatedAt   string
+}
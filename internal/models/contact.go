@@ -1,17 +1,31 @@
-package models
-
-// Contact represents a person at a company
-type Contact struct {
-	ID          string
-	CompanyID   string
-	CompanyName string // For display purposes
-	FirstName   string
-	LastName    string
-	Role        string
-	Email       string
-	Phone       string
-	Linkedin    string
-	Notes       string
-	CreatedAt   string
-	UpdatedAt   string
-}
+package models
+
+// Contact represents a person at a company
+type Contact struct {
+	ID          string
+	CompanyID   string
+	CompanyName string // For display purposes
+	FirstName   string
+	LastName    string
+	Role        string
+	Email       string
+	Phone       string
+	Linkedin    string
+	Notes       string
+	Tags        []Tag
+	CreatedAt   string
+	UpdatedAt   string
+}
+
+// VCardImport is the result of importing a vCard file into contacts
+type VCardImport struct {
+	Created []Contact
+	Skipped []VCardSkip
+}
+
+// VCardSkip is a card that was not imported, and why
+type VCardSkip struct {
+	Card   int // Position of the card in the file, from 1
+	Name   string
+	Reason string
+}
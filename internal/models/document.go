@@ -0,0 +1,40 @@
+package models
+
+// Document kinds
+const (
+	DocumentKindResume      = "RESUME"
+	DocumentKindCoverLetter = "COVER_LETTER"
+	DocumentKindOther       = "OTHER"
+)
+
+// DocumentKinds lists the document kinds in display order
+var DocumentKinds = []string{
+	DocumentKindResume,
+	DocumentKindCoverLetter,
+	DocumentKindOther,
+}
+
+// Document represents a labelled resume or cover letter version in the library
+type Document struct {
+	ID        string
+	Label     string
+	Kind      string
+	File      string // Stored file name
+	Notes     string
+	RoleCount int // For display purposes - roles referencing this document
+	CreatedAt string
+	UpdatedAt string
+}
+
+// DocumentKindLabel returns a human-readable label for a document kind
+func DocumentKindLabel(kind string) string {
+	switch kind {
+	case DocumentKindResume:
+		return "Resume"
+	case DocumentKindCoverLetter:
+		return "Cover Letter"
+	case DocumentKindOther:
+		return "Other"
+	}
+	return kind
+}
@@ -0,0 +1,139 @@
+package models
+
+import "strings"
+
+// Role location types
+const (
+	LocationRemote = "REMOTE"
+	LocationHybrid = "HYBRID"
+	LocationOnsite = "ONSITE"
+)
+
+// Locations lists every role location type (used for dropdowns)
+var Locations = []string{
+	LocationRemote,
+	LocationHybrid,
+	LocationOnsite,
+}
+
+// locationAliases maps legacy spellings to canonical locations
+var locationAliases = map[string]string{
+	"WFH":       LocationRemote,
+	"ON_SITE":   LocationOnsite,
+	"IN_OFFICE": LocationOnsite,
+	"IN_PERSON": LocationOnsite,
+	"OFFICE":    LocationOnsite,
+}
+
+// Interview types
+const (
+	InterviewTypeRecruiter  = "RECRUITER"
+	InterviewTypeTechScreen = "TECH_SCREEN"
+	InterviewTypeManager    = "MANAGER"
+	InterviewTypeLoop       = "LOOP"
+	InterviewTypeMisc       = "MISC"
+)
+
+// InterviewTypes lists every interview type (used for dropdowns)
+var InterviewTypes = []string{
+	InterviewTypeRecruiter,
+	InterviewTypeTechScreen,
+	InterviewTypeManager,
+	InterviewTypeLoop,
+	InterviewTypeMisc,
+}
+
+// interviewTypeAliases maps legacy spellings to canonical interview types
+var interviewTypeAliases = map[string]string{
+	"RECRUITER_SCREEN": InterviewTypeRecruiter,
+	"PHONE_SCREEN":     InterviewTypeRecruiter,
+	"TECHNICAL":        InterviewTypeTechScreen,
+	"TECHNICAL_SCREEN": InterviewTypeTechScreen,
+	"TECHSCREEN":       InterviewTypeTechScreen,
+	"HIRING_MANAGER":   InterviewTypeManager,
+	"ONSITE":           InterviewTypeLoop,
+	"ON_SITE":          InterviewTypeLoop,
+	"FINAL":            InterviewTypeLoop,
+	"OTHER":            InterviewTypeMisc,
+}
+
+// LocationLabel returns a human-readable label for a location
+func LocationLabel(location string) string {
+	switch location {
+	case LocationRemote:
+		return "Remote"
+	case LocationHybrid:
+		return "Hybrid"
+	case LocationOnsite:
+		return "Onsite"
+	default:
+		return location
+	}
+}
+
+// NormalizeLocation maps a location in any legacy spelling to its canonical value
+func NormalizeLocation(location string) string {
+	return normalizeEnum(location, Locations, locationAliases)
+}
+
+// IsValidLocation reports whether location is a known location (empty is allowed)
+func IsValidLocation(location string) bool {
+	return location == "" || contains(Locations, location)
+}
+
+// InterviewTypeLabel returns a human-readable label for an interview type
+func InterviewTypeLabel(interviewType string) string {
+	switch interviewType {
+	case InterviewTypeRecruiter:
+		return "Recruiter Screen"
+	case InterviewTypeTechScreen:
+		return "Technical Screen"
+	case InterviewTypeManager:
+		return "Manager Interview"
+	case InterviewTypeLoop:
+		return "Interview Loop"
+	case InterviewTypeMisc:
+		return "Other"
+	default:
+		return interviewType
+	}
+}
+
+// NormalizeInterviewType maps an interview type in any legacy spelling to its canonical value
+func NormalizeInterviewType(interviewType string) string {
+	return normalizeEnum(interviewType, InterviewTypes, interviewTypeAliases)
+}
+
+// IsValidInterviewType reports whether interviewType is a known interview type
+func IsValidInterviewType(interviewType string) bool {
+	return contains(InterviewTypes, interviewType)
+}
+
+// normalizeEnum upper-cases a value, turns spaces and dashes into underscores
+// and resolves aliases. "NULL" and blank values become empty.
+func normalizeEnum(value string, canonical []string, aliases map[string]string) string {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "NULL" {
+		return ""
+	}
+
+	value = strings.ToUpper(value)
+	value = strings.NewReplacer(" ", "_", "-", "_").Replace(value)
+
+	if contains(canonical, value) {
+		return value
+	}
+	if alias, ok := aliases[value]; ok {
+		return alias
+	}
+	return value
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package models
+
+// ImportProblem is a validation problem found in one CSV row
+type ImportProblem struct {
+	Line    int // Line in the CSV file, the header being line 1
+	Column  string
+	Problem string
+}
+
+// ImportFileReport summarizes the import or dry run of one CSV file
+type ImportFileReport struct {
+	Step      string
+	Rows      int
+	Created   int
+	Updated   int
+	Unchanged int
+	Error     string // Set when the file could not be processed at all
+	Problems  []ImportProblem
+}
+
+// ImportReport is the result of an import or a dry run
+type ImportReport struct {
+	DryRun bool
+	Files  []ImportFileReport
+}
+
+// HasProblems reports whether any file failed or has invalid rows
+func (r ImportReport) HasProblems() bool {
+	for _, file := range r.Files {
+		if file.Error != "" || len(file.Problems) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Rows returns the number of rows read across all files
+func (r ImportReport) Rows() int {
+	total := 0
+	for _, file := range r.Files {
+		total += file.Rows
+	}
+	return total
+}
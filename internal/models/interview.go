@@ -1,19 +1,37 @@
-package models
-
-// Interview represents an interview for a role
-type Interview struct {
-	ID          string
-	RoleID      string
-	RoleName    string   // For display purposes
-	CompanyID   string   // For display purposes
-	CompanyName string   // For display purposes
-	Date        string
-	Start       string
-	End         string
-	Notes       string
-	Type        string
-	ContactIDs  []string // Multiple contacts can be associated
-	Contacts    []Contact // Expanded contacts for display
-	CreatedAt   string
-	UpdatedAt   string
-}
+package models
+
+// Interview represents an interview for a role
+type Interview struct {
+	ID          string
+	RoleID      string
+	RoleName    string   // For display purposes
+	CompanyID   string   // For display purposes
+	CompanyName string   // For display purposes
+	Date        string
+	Start       string
+	End         string
+	Notes       string
+	Type        string
+	ContactIDs  []string // Multiple contacts can be associated
+	Contacts    []Contact // Expanded contacts for display
+	Tags        []Tag
+	CreatedAt   string
+	UpdatedAt   string
+}
+
+// InterviewInvite describes a calendar invite read into the new interview form
+type InterviewInvite struct {
+	Filename    string
+	Summary     string
+	CompanyID   string // Company guessed from the attendees, empty when none matched
+	CompanyName string
+	Matched     []Contact        // Existing contacts found among the attendees
+	Unmatched   []InviteAttendee // Attendees of the guessed company that aren't contacts yet
+}
+
+// InviteAttendee is an invite attendee that isn't a contact yet
+type InviteAttendee struct {
+	FirstName string
+	LastName  string
+	Email     string
+}
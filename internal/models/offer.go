@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultVestingSchedule vests an equity grant evenly over four years
+const DefaultVestingSchedule = "25,25,25,25"
+
+// Offer represents a job offer for a role
+type Offer struct {
+	ID                 string
+	RoleID             string
+	RoleName           string // For display purposes
+	CompanyName        string // For display purposes
+	BaseSalary         int64
+	SignOnBonus        int64
+	BonusTargetPercent float64
+	EquityShares       int64
+	EquityValue        int64  // Total grant value in dollars
+	VestingSchedule    string // Yearly vesting percentages, e.g. "25,25,25,25"
+	CliffMonths        int64
+	BenefitsNotes      string
+	Deadline           string
+	NegotiationRounds  int64
+	NegotiationNotes   string
+	CreatedAt          string
+	UpdatedAt          string
+}
+
+// ParseVestingSchedule parses comma separated yearly vesting percentages.
+// An empty schedule falls back to DefaultVestingSchedule.
+func ParseVestingSchedule(schedule string) ([]float64, error) {
+	if strings.TrimSpace(schedule) == "" {
+		schedule = DefaultVestingSchedule
+	}
+
+	var percents []float64
+	total := 0.0
+	for _, part := range strings.FieldsFunc(schedule, func(r rune) bool { return r == ',' || r == '/' }) {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(part), "%"), 64)
+		if err != nil || percent < 0 {
+			return nil, fmt.Errorf("invalid vesting percentage %q", strings.TrimSpace(part))
+		}
+		percents = append(percents, percent)
+		total += percent
+	}
+
+	if total > 100.0001 {
+		return nil, fmt.Errorf("vesting schedule adds up to %.4g%%, more than 100%%", total)
+	}
+
+	return percents, nil
+}
+
+// AnnualBonus returns the target bonus for one year
+func (o Offer) AnnualBonus() float64 {
+	return float64(o.BaseSalary) * o.BonusTargetPercent / 100
+}
+
+// EquityByYear returns the equity value vesting in each of the first n years.
+// Anything scheduled before the cliff vests in the year the cliff is reached.
+func (o Offer) EquityByYear(years int) []float64 {
+	byYear := make([]float64, years)
+
+	percents, err := ParseVestingSchedule(o.VestingSchedule)
+	if err != nil {
+		return byYear
+	}
+
+	cliffYear := int((o.CliffMonths + 11) / 12)
+	for i, percent := range percents {
+		year := max(i, cliffYear-1)
+		if year < years {
+			byYear[year] += float64(o.EquityValue) * percent / 100
+		}
+	}
+
+	return byYear
+}
+
+// FirstYearTotal returns base, sign-on, target bonus and equity vesting in year one
+func (o Offer) FirstYearTotal() float64 {
+	return float64(o.BaseSalary) + float64(o.SignOnBonus) + o.AnnualBonus() + o.EquityByYear(1)[0]
+}
+
+// FourYearTotal returns four years of base and target bonus, the sign-on
+// bonus and the equity vesting over those four years
+func (o Offer) FourYearTotal() float64 {
+	total := 4*(float64(o.BaseSalary)+o.AnnualBonus()) + float64(o.SignOnBonus)
+	for _, equity := range o.EquityByYear(4) {
+		total += equity
+	}
+	return total
+}
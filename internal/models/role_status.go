@@ -0,0 +1,121 @@
+package models
+
+// Role statuses used across the application pipeline
+const (
+	RoleStatusResearch     = "RESEARCH"
+	RoleStatusApplied      = "APPLIED"
+	RoleStatusInterviewing = "INTERVIEWING"
+	RoleStatusOffer        = "OFFER"
+	RoleStatusAccepted     = "ACCEPTED"
+	RoleStatusRejected     = "REJECTED"
+	RoleStatusGhosted      = "GHOSTED"
+	RoleStatusFreeze       = "FREEZE"
+	RoleStatusWithdrew     = "WITHDREW"
+)
+
+// RoleStatuses lists every role status in pipeline order (used for dropdowns)
+var RoleStatuses = []string{
+	RoleStatusResearch,
+	RoleStatusApplied,
+	RoleStatusInterviewing,
+	RoleStatusOffer,
+	RoleStatusAccepted,
+	RoleStatusRejected,
+	RoleStatusGhosted,
+	RoleStatusFreeze,
+	RoleStatusWithdrew,
+}
+
+// roleStatusAliases maps legacy spellings to canonical statuses
+var roleStatusAliases = map[string]string{
+	"RESEARCHING":   RoleStatusResearch,
+	"INTERVIEW":     RoleStatusInterviewing,
+	"OFFERED":       RoleStatusOffer,
+	"ACCEPT":        RoleStatusAccepted,
+	"REJECT":        RoleStatusRejected,
+	"GHOST":         RoleStatusGhosted,
+	"FROZEN":        RoleStatusFreeze,
+	"HIRING_FREEZE": RoleStatusFreeze,
+	"WITHDRAW":      RoleStatusWithdrew,
+	"WITHDRAWN":     RoleStatusWithdrew,
+}
+
+// roleStatusTransitions defines the allowed moves between statuses.
+// Statuses without an entry (ACCEPTED, REJECTED, WITHDREW) are terminal.
+var roleStatusTransitions = map[string][]string{
+	RoleStatusResearch:     {RoleStatusApplied, RoleStatusFreeze, RoleStatusWithdrew},
+	RoleStatusApplied:      {RoleStatusInterviewing, RoleStatusOffer, RoleStatusRejected, RoleStatusGhosted, RoleStatusFreeze, RoleStatusWithdrew},
+	RoleStatusInterviewing: {RoleStatusOffer, RoleStatusRejected, RoleStatusGhosted, RoleStatusFreeze, RoleStatusWithdrew},
+	RoleStatusOffer:        {RoleStatusAccepted, RoleStatusRejected, RoleStatusWithdrew},
+	RoleStatusGhosted:      {RoleStatusInterviewing, RoleStatusRejected, RoleStatusWithdrew},
+	RoleStatusFreeze:       {RoleStatusApplied, RoleStatusInterviewing, RoleStatusRejected, RoleStatusWithdrew},
+}
+
+// RoleStatusLabel returns a human-readable label for a status
+func RoleStatusLabel(status string) string {
+	switch status {
+	case RoleStatusResearch:
+		return "Research"
+	case RoleStatusApplied:
+		return "Applied"
+	case RoleStatusInterviewing:
+		return "Interviewing"
+	case RoleStatusOffer:
+		return "Offer"
+	case RoleStatusAccepted:
+		return "Accepted"
+	case RoleStatusRejected:
+		return "Rejected"
+	case RoleStatusGhosted:
+		return "Ghosted"
+	case RoleStatusFreeze:
+		return "Freeze"
+	case RoleStatusWithdrew:
+		return "Withdrew"
+	default:
+		return status
+	}
+}
+
+// NormalizeRoleStatus maps a status in any legacy spelling to its canonical value.
+// Unknown values are returned cleaned up but otherwise unchanged.
+func NormalizeRoleStatus(status string) string {
+	return normalizeEnum(status, RoleStatuses, roleStatusAliases)
+}
+
+// IsValidRoleStatus reports whether status is a known status (empty is allowed)
+func IsValidRoleStatus(status string) bool {
+	return status == "" || contains(RoleStatuses, status)
+}
+
+// CanTransitionRoleStatus reports whether a role may move from one status to another.
+// Setting the same status, setting the first status, or moving away from a
+// legacy status that is not part of the graph is always allowed.
+func CanTransitionRoleStatus(from, to string) bool {
+	if from == to || from == "" {
+		return true
+	}
+	if !IsValidRoleStatus(to) {
+		return false
+	}
+	if !IsValidRoleStatus(from) {
+		return true
+	}
+	return contains(roleStatusTransitions[from], to)
+}
+
+// NextRoleStatuses returns the statuses reachable from the given status,
+// including the status itself so forms can keep the current value selected
+func NextRoleStatuses(from string) []string {
+	if from == "" || !IsValidRoleStatus(from) {
+		return RoleStatuses
+	}
+	return append([]string{from}, roleStatusTransitions[from]...)
+}
+
+// IsOpenRoleStatus reports whether a role is still in progress: it has no
+// status yet or one that can move on
+func IsOpenRoleStatus(status string) bool {
+	_, ok := roleStatusTransitions[status]
+	return ok || status == ""
+}
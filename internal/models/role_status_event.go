@@ -0,0 +1,11 @@
+package models
+
+// RoleStatusEvent records a single status change of a role
+type RoleStatusEvent struct {
+	ID         string
+	RoleID     string
+	FromStatus string
+	ToStatus   string
+	Source     string // form, inline, import, api, capture or bulk
+	ChangedAt  string
+}
@@ -0,0 +1,29 @@
+package models
+
+// Stats are the job search metrics of the stats page, over a date range
+type Stats struct {
+	RolesApplied         int
+	OffersReceived       int
+	Rejections           int
+	Interviewing         int
+	Ghosted              int
+	Freeze               int
+	Withdrew             int
+	AvgPostedMin         float64
+	AvgPostedMax         float64
+	AbsPostedMin         int64
+	AbsPostedMax         int64
+	RemoteRoles          int
+	HybridRoles          int
+	OnsiteRoles          int
+	TotalInterviews      int
+	RecruiterInterviews  int
+	ManagerInterviews    int
+	LoopInterviews       int
+	TechScreenInterviews int
+	DateRange            string
+	StartDate            string
+	EndDate              string
+	FirstApplicationDate string
+	LastApplicationDate  string
+}
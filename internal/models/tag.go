@@ -0,0 +1,42 @@
+package models
+
+import "strings"
+
+// Tag represents a label shared by companies, roles, contacts and interviews
+type Tag struct {
+	ID   string
+	Name string
+}
+
+// TagFilter holds the tags available to filter a list by and the active one
+type TagFilter struct {
+	Tags   []Tag
+	Active string
+}
+
+// TagNames joins tag names for a comma-separated tags input or CSV column
+func TagNames(tags []Tag) string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// SplitTagNames parses a comma-separated list of tag names.
+// Names are trimmed, a leading '#' is dropped and duplicates
+// (compared case-insensitively) are removed.
+func SplitTagNames(s string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+		key := strings.ToLower(name)
+		if name == "" || name == "NULL" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,38 @@
+package models
+
+// Task views on the tasks page
+const (
+	TaskViewOpen     = "open"
+	TaskViewOverdue  = "overdue"
+	TaskViewThisWeek = "week"
+	TaskViewDone     = "done"
+)
+
+// Task represents a follow-up action, optionally linked to a company,
+// role, contact or interview
+type Task struct {
+	ID             string
+	Title          string
+	DueDate        string
+	Done           bool
+	CompletedAt    string
+	Notes          string
+	CompanyID      string
+	CompanyName    string // For display purposes
+	RoleID         string
+	RoleName       string // For display purposes
+	ContactID      string
+	ContactName    string // For display purposes
+	InterviewID    string
+	InterviewLabel string // For display purposes
+	CreatedAt      string
+	UpdatedAt      string
+}
+
+// TaskRelationOptions holds the records a task can be linked to
+type TaskRelationOptions struct {
+	Companies  []Company
+	Roles      []Role
+	Contacts   []Contact
+	Interviews []Interview
+}
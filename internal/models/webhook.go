@@ -0,0 +1,74 @@
+package models
+
+// Webhook events
+const (
+	WebhookEventRoleCreated       = "role.created"
+	WebhookEventRoleUpdated       = "role.updated"
+	WebhookEventRoleStatusChanged = "role.status_changed"
+	WebhookEventRoleDeleted       = "role.deleted"
+	WebhookEventInterviewCreated  = "interview.created"
+	WebhookEventInterviewUpdated  = "interview.updated"
+	WebhookEventInterviewDeleted  = "interview.deleted"
+	WebhookEventContactCreated    = "contact.created"
+	WebhookEventContactUpdated    = "contact.updated"
+	WebhookEventContactDeleted    = "contact.deleted"
+	WebhookEventPing              = "ping" // Sent by the Test button only
+)
+
+// WebhookEvents lists the events a webhook can subscribe to
+var WebhookEvents = []string{
+	WebhookEventRoleCreated,
+	WebhookEventRoleUpdated,
+	WebhookEventRoleStatusChanged,
+	WebhookEventRoleDeleted,
+	WebhookEventInterviewCreated,
+	WebhookEventInterviewUpdated,
+	WebhookEventInterviewDeleted,
+	WebhookEventContactCreated,
+	WebhookEventContactUpdated,
+	WebhookEventContactDeleted,
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending" // Not delivered yet; retried at NextAttemptAt
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed" // Out of retries
+)
+
+// Webhook is a URL that gets a signed POST when a role, interview or contact changes
+type Webhook struct {
+	ID       string
+	Name     string
+	URL      string
+	Secret   string
+	Events   []string
+	Statuses []string // Role statuses role events are limited to; empty for all
+	Active   bool
+}
+
+// WebhookDelivery is one event sent, or being retried, to a webhook
+type WebhookDelivery struct {
+	ID             string
+	WebhookID      string
+	WebhookName    string // For display purposes
+	WebhookURL     string // For display purposes
+	Event          string
+	Payload        string // The JSON body sent
+	Status         string
+	Attempts       int
+	ResponseStatus int
+	ResponseBody   string // Start of the last response
+	Error          string
+	CreatedAt      string
+	LastAttemptAt  string
+	NextAttemptAt  string
+}
+
+// WebhookPayload is the JSON body POSTed to webhooks
+type WebhookPayload struct {
+	Event    string
+	Time     string // RFC 3339
+	Record   any    // The role, interview or contact, as the JSON API returns it
+	Previous any    `json:",omitempty"` // The record before an update
+}
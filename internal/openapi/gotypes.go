@@ -0,0 +1,93 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// TypeParam stands in for the type parameter of a generic type given to
+// WriteGoTypes: pass APIList[TypeParam] to write APIList[T any]
+type TypeParam struct{}
+
+var typeParam = reflect.TypeFor[TypeParam]()
+
+// WriteGoTypes writes Go declarations of struct types to w, as package pkg,
+// with the same field names and JSON tags, so a client decodes exactly what
+// the server encodes. The struct types they use are written too.
+func WriteGoTypes(w io.Writer, pkg, generator string, types ...reflect.Type) error {
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by %s; DO NOT EDIT.\n\n", generator)
+	fmt.Fprintf(&src, "package %s\n", pkg)
+
+	written := make(map[string]bool)
+	for len(types) > 0 {
+		t := types[0]
+		types = types[1:]
+
+		name, generic := goTypeName(t)
+		if written[name] {
+			continue
+		}
+		written[name] = true
+
+		decl := name
+		if generic {
+			decl += "[T any]"
+		}
+		fmt.Fprintf(&src, "\n// %s is generated from %s.%s\n", name, path.Base(t.PkgPath()), name)
+		fmt.Fprintf(&src, "type %s struct {\n", decl)
+		for _, field := range Fields(t) {
+			goType, used := goFieldType(field.Type)
+			types = append(types, used...)
+			fmt.Fprintf(&src, "\t%s %s", field.GoName, goType)
+			if field.Tag != "" {
+				fmt.Fprintf(&src, " `%s`", field.Tag)
+			}
+			src.WriteString("\n")
+		}
+		src.WriteString("}\n")
+	}
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format generated types: %w", err)
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// goTypeName returns the declared name of a struct type and whether it is an
+// instance of a generic type
+func goTypeName(t reflect.Type) (string, bool) {
+	name, _, generic := strings.Cut(t.Name(), "[")
+	return name, generic
+}
+
+// goFieldType returns the Go source of a field type and the struct types it uses
+func goFieldType(t reflect.Type) (string, []reflect.Type) {
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, used := goFieldType(t.Elem())
+		return "*" + elem, used
+	case reflect.Slice:
+		elem, used := goFieldType(t.Elem())
+		return "[]" + elem, used
+	case reflect.Map:
+		key, _ := goFieldType(t.Key())
+		elem, used := goFieldType(t.Elem())
+		return "map[" + key + "]" + elem, used
+	case reflect.Struct:
+		if t == typeParam {
+			return "T", nil
+		}
+		name, _ := goTypeName(t)
+		return name, []reflect.Type{t}
+	default:
+		return t.Kind().String(), nil
+	}
+}
@@ -0,0 +1,194 @@
+package openapi
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Version is the OpenAPI version of the documents
+const Version = "3.0.3"
+
+// Document is an OpenAPI document, with the parts the API uses
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Servers    []Server              `json:"servers,omitempty"`
+	Paths      map[string]*PathItem  `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []map[string][]string `json:"security,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem holds the operations of one path
+type PathItem struct {
+	Parameters []Parameter `json:"parameters,omitempty"` // Shared by every operation, like path params
+	Get        *Operation  `json:"get,omitempty"`
+	Post       *Operation  `json:"post,omitempty"`
+	Put        *Operation  `json:"put,omitempty"`
+	Patch      *Operation  `json:"patch,omitempty"`
+	Delete     *Operation  `json:"delete,omitempty"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // path or query
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Explode     *bool   `json:"explode,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// Schema is a JSON schema, or a reference to one of the component schemas
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	Maximum              *int               `json:"maximum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	ReadOnly             bool               `json:"readOnly,omitempty"`
+}
+
+// JSON is the media type of every body
+func JSON(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+// Schema returns the schema of a Go type as encoding/json writes it. Structs
+// are added to the component schemas, under their type name, and referenced.
+func (c *Components) Schema(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := c.Schema(t.Elem())
+		schema.Nullable = true
+		return schema
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		// nil slices are written as null
+		return &Schema{Type: "array", Items: c.Schema(t.Elem()), Nullable: t.Kind() == reflect.Slice}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: c.Schema(t.Elem()), Nullable: true}
+	case reflect.Struct:
+		name := SchemaName(t)
+		if c.Schemas == nil {
+			c.Schemas = make(map[string]*Schema)
+		}
+		if _, ok := c.Schemas[name]; !ok {
+			// Added before the fields so self references end
+			schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+			c.Schemas[name] = schema
+			for _, field := range Fields(t) {
+				schema.Properties[field.Name] = c.Schema(field.Type)
+			}
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		// Interfaces and other kinds can hold anything
+		return &Schema{}
+	}
+}
+
+// Field is a struct field as encoding/json writes it
+type Field struct {
+	Name   string // Name in JSON
+	GoName string
+	Type   reflect.Type
+	Tag    reflect.StructTag
+}
+
+// Fields returns the fields of a struct that encoding/json writes, in order
+func Fields(t reflect.Type) []Field {
+	var fields []Field
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, Field{Name: name, GoName: field.Name, Type: field.Type, Tag: field.Tag})
+	}
+	return fields
+}
+
+// typeArgPackage matches the package paths of generic type arguments
+var typeArgPackage = regexp.MustCompile(`[\w./-]+\.`)
+
+// SchemaName returns the component schema name of a struct type: its name,
+// with the names of its type arguments appended for generic types, so
+// APIList[models.Role] is APIListRole
+func SchemaName(t reflect.Type) string {
+	name, args, ok := strings.Cut(t.Name(), "[")
+	if !ok {
+		return name
+	}
+	args = typeArgPackage.ReplaceAllString(strings.TrimSuffix(args, "]"), "")
+	return name + strings.NewReplacer(",", "", "[", "", "]", "", "*", "").Replace(args)
+}
@@ -0,0 +1,397 @@
+package posting
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Posting is what the page of a job posting tells about the job
+type Posting struct {
+	URL         string
+	Title       string
+	Company     string
+	CompanyURL  string // Company website, when the page is on it or names it
+	Description string // Plain text
+	City        string
+	State       string
+	Remote      bool
+}
+
+// Parse reads a job posting from the URL, title and HTML of its page. The
+// schema.org JobPosting that most job boards embed is read first; the page
+// title, the URL and the site name fill in what it leaves out.
+func Parse(pageURL, title, page string) Posting {
+	p := Posting{URL: strings.TrimSpace(pageURL)}
+	meta := readPage(page)
+	if title = strings.TrimSpace(title); title == "" {
+		title = meta.title
+	}
+
+	if job := findJobPosting(meta.jsonLD); job != nil {
+		p.Title = text(job["title"])
+		p.Description = htmlText(text(job["description"]))
+		if org, ok := job["hiringOrganization"].(map[string]any); ok {
+			p.Company = text(org["name"])
+			p.CompanyURL = firstText(org["sameAs"], org["url"])
+		} else {
+			p.Company = text(job["hiringOrganization"])
+		}
+		p.City, p.State = jobLocation(job["jobLocation"])
+		p.Remote = strings.EqualFold(text(job["jobLocationType"]), "TELECOMMUTE")
+	}
+
+	titleName, titleCompany := splitTitle(title)
+
+	host := hostOf(p.URL)
+	if p.Company == "" {
+		p.Company = atsCompany(p.URL)
+	}
+	if site := meta.props["og:site_name"]; p.Company == "" && isCompanyName(site) {
+		p.Company = site
+	}
+	if p.Company == "" && isCompanyName(titleCompany) {
+		p.Company = titleCompany
+	}
+
+	// A posting on the company's own site gives its website, and its name last
+	if p.CompanyURL == "" && host != "" && !isJobBoard(host) && atsCompany(p.URL) == "" {
+		p.CompanyURL = "https://" + companyHost(host)
+	}
+	if p.Company == "" && p.CompanyURL != "" {
+		p.Company = domainName(hostOf(p.CompanyURL))
+	}
+
+	// Titles like "Acme - Staff Engineer" put the company first
+	if titleCompany != "" && SameName(titleName, p.Company) {
+		titleName = titleCompany
+	}
+	if p.Title == "" {
+		p.Title = titleName
+	}
+	if p.Title == "" {
+		p.Title = meta.props["og:title"]
+	}
+
+	return p
+}
+
+// pageMeta is what the head of a page holds
+type pageMeta struct {
+	title  string
+	props  map[string]string // <meta> content by property or name
+	jsonLD []string          // application/ld+json scripts
+}
+
+// readPage reads the title, meta tags and JSON-LD scripts of a page
+func readPage(page string) pageMeta {
+	meta := pageMeta{props: make(map[string]string)}
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		return meta
+	}
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Title:
+				if meta.title == "" {
+					meta.title = strings.TrimSpace(nodeText(n))
+				}
+			case atom.Meta:
+				key := attr(n, "property")
+				if key == "" {
+					key = attr(n, "name")
+				}
+				if key != "" && meta.props[strings.ToLower(key)] == "" {
+					meta.props[strings.ToLower(key)] = strings.TrimSpace(attr(n, "content"))
+				}
+			case atom.Script:
+				if strings.EqualFold(attr(n, "type"), "application/ld+json") {
+					meta.jsonLD = append(meta.jsonLD, nodeText(n))
+				}
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	return meta
+}
+
+// findJobPosting returns the first schema.org JobPosting of the JSON-LD
+// scripts, looking into arrays and @graph lists
+func findJobPosting(scripts []string) map[string]any {
+	var find func(any) map[string]any
+	find = func(v any) map[string]any {
+		switch v := v.(type) {
+		case []any:
+			for _, item := range v {
+				if job := find(item); job != nil {
+					return job
+				}
+			}
+		case map[string]any:
+			for _, t := range texts(v["@type"]) {
+				if t == "JobPosting" {
+					return v
+				}
+			}
+			return find(v["@graph"])
+		}
+		return nil
+	}
+
+	for _, script := range scripts {
+		var v any
+		if json.Unmarshal([]byte(script), &v) == nil {
+			if job := find(v); job != nil {
+				return job
+			}
+		}
+	}
+	return nil
+}
+
+// jobLocation returns the city and region of the first place of a JobPosting
+func jobLocation(v any) (string, string) {
+	if places, ok := v.([]any); ok && len(places) > 0 {
+		v = places[0]
+	}
+	place, _ := v.(map[string]any)
+	address, _ := place["address"].(map[string]any)
+	return text(address["addressLocality"]), text(address["addressRegion"])
+}
+
+// Page title patterns of job postings
+var (
+	applicationTitle = regexp.MustCompile(`(?i)^job application for (.+) at (.+)$`)
+	hiringTitle      = regexp.MustCompile(`(?i)^(.+?) hiring (.+?)(?: in .+)?(?: \| .+)?$`)
+	atTitle          = regexp.MustCompile(`(?i)^(.+) at (.+?)(?: [|–—-] .+)?$`)
+)
+
+// splitTitle reads the job title and company out of a page title like
+// "Staff Engineer at Acme" or "Staff Engineer - Acme | Careers"
+func splitTitle(title string) (string, string) {
+	if m := applicationTitle.FindStringSubmatch(title); m != nil {
+		return strings.TrimSpace(m[1]), strings.TrimSpace(m[2])
+	}
+	if m := hiringTitle.FindStringSubmatch(title); m != nil {
+		return strings.TrimSpace(m[2]), strings.TrimSpace(m[1])
+	}
+	if m := atTitle.FindStringSubmatch(title); m != nil {
+		return strings.TrimSpace(m[1]), strings.TrimSpace(m[2])
+	}
+
+	parts := strings.FieldsFunc(title, func(r rune) bool { return r == '|' || r == '–' || r == '—' })
+	if len(parts) == 1 {
+		parts = strings.Split(title, " - ")
+	}
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	if len(parts) > 1 {
+		return parts[0], parts[1]
+	}
+	return strings.TrimSpace(title), ""
+}
+
+// atsPaths are applicant tracking systems that host postings under a
+// path named after the company
+var atsPaths = map[string]bool{
+	"boards.greenhouse.io":     true,
+	"job-boards.greenhouse.io": true,
+	"jobs.lever.co":            true,
+	"jobs.eu.lever.co":         true,
+	"jobs.ashbyhq.com":         true,
+	"apply.workable.com":       true,
+	"jobs.smartrecruiters.com": true,
+}
+
+// atsSubdomains are applicant tracking systems that host postings on a
+// subdomain named after the company
+var atsSubdomains = []string{"myworkdayjobs.com", "bamboohr.com", "recruitee.com", "breezy.hr", "teamtailor.com"}
+
+// jobBoards list postings of many companies, so neither their host nor their
+// name is the company's
+var jobBoards = []string{
+	"linkedin", "indeed", "glassdoor", "wellfound", "angel.co", "ycombinator",
+	"builtin", "dice.com", "ziprecruiter", "monster", "simplyhired", "otta",
+	"greenhouse", "lever.co", "ashbyhq", "workable", "smartrecruiters", "workday",
+}
+
+// atsCompany returns the company named in the URL of an applicant tracking system
+func atsCompany(pageURL string) string {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+
+	if atsPaths[host] {
+		slug, _, _ := strings.Cut(strings.Trim(u.Path, "/"), "/")
+		return slugName(slug)
+	}
+	for _, domain := range atsSubdomains {
+		if sub, ok := strings.CutSuffix(host, "."+domain); ok {
+			slug, _, _ := strings.Cut(sub, ".")
+			return slugName(slug)
+		}
+	}
+	return ""
+}
+
+// isJobBoard reports whether a host or site name is a job board or tracking system
+func isJobBoard(s string) bool {
+	for _, board := range jobBoards {
+		if strings.Contains(s, board) {
+			return true
+		}
+	}
+	for _, domain := range atsSubdomains {
+		if strings.HasSuffix(s, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// genericNames are site names and title parts that name no company
+var genericNames = map[string]bool{"careers": true, "jobs": true, "job board": true, "home": true, "job details": true, "apply": true}
+
+// isCompanyName reports whether a site name or title part can be a company name
+func isCompanyName(s string) bool {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return s != "" && !genericNames[s] && !isJobBoard(s)
+}
+
+// SameName compares names ignoring case, spaces and punctuation
+func SameName(a, b string) bool {
+	key := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return unicode.ToLower(r)
+			}
+			return -1
+		}, s)
+	}
+	return key(a) != "" && key(a) == key(b)
+}
+
+// slugName turns a URL slug like "acme-labs" into a name like "Acme Labs"
+func slugName(slug string) string {
+	words := strings.FieldsFunc(slug, func(r rune) bool { return r == '-' || r == '_' || r == '.' })
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+// companyHost returns the company's own host of a careers site host
+func companyHost(host string) string {
+	for _, prefix := range []string{"www.", "careers.", "jobs."} {
+		host = strings.TrimPrefix(host, prefix)
+	}
+	return host
+}
+
+// domainName turns a domain like "acme-labs.co.uk" into a name like "Acme Labs"
+func domainName(host string) string {
+	labels := strings.Split(companyHost(host), ".")
+	switch {
+	case len(labels) >= 3 && len(labels[len(labels)-2]) <= 3:
+		// Second-level domains like .co.uk
+		return slugName(labels[len(labels)-3])
+	case len(labels) >= 2:
+		return slugName(labels[len(labels)-2])
+	default:
+		return slugName(labels[0])
+	}
+}
+
+// hostOf returns the lowercase host of a URL
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// text returns a JSON-LD value as a string: the value of a string, the
+// first string of a list, or the name of an object
+func text(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(html.UnescapeString(v))
+	case []any:
+		for _, item := range v {
+			if s := text(item); s != "" {
+				return s
+			}
+		}
+	case map[string]any:
+		return text(v["name"])
+	}
+	return ""
+}
+
+// texts returns the strings of a JSON-LD value that is a string or a list of them
+func texts(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var s []string
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				s = append(s, str)
+			}
+		}
+		return s
+	}
+	return nil
+}
+
+// firstText returns the first JSON-LD value that isn't empty
+func firstText(values ...any) string {
+	for _, v := range values {
+		if s := text(v); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func attr(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, name) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// nodeText returns the raw text inside a node
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+		} else {
+			b.WriteString(nodeText(c))
+		}
+	}
+	return b.String()
+}
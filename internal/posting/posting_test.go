@@ -0,0 +1,223 @@
+package posting_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"reverse-ats/internal/posting"
+)
+
+// jobPage returns a page embedding a schema.org JSON-LD value
+func jobPage(t *testing.T, jsonLD any) string {
+	t.Helper()
+
+	data, err := json.Marshal(jsonLD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return `<html><head><script type="application/ld+json">` + string(data) + `</script></head><body></body></html>`
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		url   string
+		title string
+		page  string
+		want  posting.Posting
+	}{
+		{
+			name: "JSON-LD JobPosting",
+			url:  "https://boards.greenhouse.io/acme-labs/jobs/123",
+			page: jobPage(t, map[string]any{
+				"@context":    "https://schema.org",
+				"@type":       "JobPosting",
+				"title":       "Staff Engineer",
+				"description": "&lt;p&gt;Build things.&lt;/p&gt;&lt;ul&gt;&lt;li&gt;Go&lt;/li&gt;&lt;/ul&gt;",
+				"hiringOrganization": map[string]any{
+					"@type":  "Organization",
+					"name":   "Acme Labs, Inc.",
+					"sameAs": "https://acme.example.com",
+				},
+				"jobLocation": map[string]any{
+					"@type":   "Place",
+					"address": map[string]any{"addressLocality": "Austin", "addressRegion": "TX"},
+				},
+				"jobLocationType": "TELECOMMUTE",
+			}),
+			want: posting.Posting{
+				URL:         "https://boards.greenhouse.io/acme-labs/jobs/123",
+				Title:       "Staff Engineer",
+				Company:     "Acme Labs, Inc.",
+				CompanyURL:  "https://acme.example.com",
+				Description: "Build things.\n- Go",
+				City:        "Austin",
+				State:       "TX",
+				Remote:      true,
+			},
+		},
+		{
+			name: "JSON-LD in a graph",
+			url:  "https://globex.com/careers/data-engineer",
+			page: jobPage(t, map[string]any{
+				"@graph": []any{
+					map[string]any{"@type": "WebPage", "name": "Careers"},
+					map[string]any{
+						"@type":              []any{"JobPosting"},
+						"title":              "Data Engineer",
+						"hiringOrganization": "Globex",
+						"jobLocation":        []any{map[string]any{"address": map[string]any{"addressLocality": "Denver", "addressRegion": "CO"}}},
+					},
+				},
+			}),
+			want: posting.Posting{
+				URL:        "https://globex.com/careers/data-engineer",
+				Title:      "Data Engineer",
+				Company:    "Globex",
+				CompanyURL: "https://globex.com",
+				City:       "Denver",
+				State:      "CO",
+			},
+		},
+		{
+			name:  "Greenhouse",
+			url:   "https://boards.greenhouse.io/acme-labs/jobs/123",
+			title: "Job Application for Staff Engineer at Acme Labs",
+			want:  posting.Posting{URL: "https://boards.greenhouse.io/acme-labs/jobs/123", Title: "Staff Engineer", Company: "Acme Labs"},
+		},
+		{
+			name:  "Lever",
+			url:   "https://jobs.lever.co/globex/0b7a4c1e-2f3d",
+			title: "Globex - Senior Designer",
+			want:  posting.Posting{URL: "https://jobs.lever.co/globex/0b7a4c1e-2f3d", Title: "Senior Designer", Company: "Globex"},
+		},
+		{
+			name:  "Ashby",
+			url:   "https://jobs.ashbyhq.com/initech/5f1e9c3a",
+			title: "Platform Engineer",
+			want:  posting.Posting{URL: "https://jobs.ashbyhq.com/initech/5f1e9c3a", Title: "Platform Engineer", Company: "Initech"},
+		},
+		{
+			name:  "Workday",
+			url:   "https://umbrella.wd5.myworkdayjobs.com/en-US/External/job/Remote/Staff-SRE_R123",
+			title: "Staff SRE",
+			want:  posting.Posting{URL: "https://umbrella.wd5.myworkdayjobs.com/en-US/External/job/Remote/Staff-SRE_R123", Title: "Staff SRE", Company: "Umbrella"},
+		},
+		{
+			name:  "job board title",
+			url:   "https://www.linkedin.com/jobs/view/123",
+			title: "Hooli hiring Backend Engineer in New York, NY | LinkedIn",
+			page:  `<meta property="og:site_name" content="LinkedIn">`,
+			want:  posting.Posting{URL: "https://www.linkedin.com/jobs/view/123", Title: "Backend Engineer", Company: "Hooli"},
+		},
+		{
+			name:  "site name",
+			url:   "https://careers.vandelay.com/jobs/42",
+			title: "Importer/Exporter | Careers",
+			page:  `<meta property="og:site_name" content="Vandelay Industries">`,
+			want: posting.Posting{
+				URL:        "https://careers.vandelay.com/jobs/42",
+				Title:      "Importer/Exporter",
+				Company:    "Vandelay Industries",
+				CompanyURL: "https://vandelay.com",
+			},
+		},
+		{
+			name:  "domain",
+			url:   "https://jobs.acme-labs.co.uk/openings/7",
+			title: "Senior Analyst",
+			page:  `<meta property="og:site_name" content="Careers">`,
+			want: posting.Posting{
+				URL:        "https://jobs.acme-labs.co.uk/openings/7",
+				Title:      "Senior Analyst",
+				Company:    "Acme Labs",
+				CompanyURL: "https://acme-labs.co.uk",
+			},
+		},
+		{
+			name: "page title",
+			page: `<html><head><title> Product Manager at Wayne Enterprises </title></head></html>`,
+			want: posting.Posting{Title: "Product Manager", Company: "Wayne Enterprises"},
+		},
+		{
+			name: "og:title",
+			page: `<meta property="og:title" content="Ops Lead">`,
+			want: posting.Posting{Title: "Ops Lead"},
+		},
+		{
+			name:  "malformed JSON-LD",
+			title: "Staff Engineer at Initrode",
+			page:  `<script type="application/ld+json">{"@type": "JobPosting", "title": </script>`,
+			want:  posting.Posting{Title: "Staff Engineer", Company: "Initrode"},
+		},
+		{
+			name:  "JSON-LD without a JobPosting",
+			title: "QA Engineer",
+			page:  `<script type="application/ld+json">[{"@type": "Organization", "name": "Nobody"}, 42, null]</script>`,
+			want:  posting.Posting{Title: "QA Engineer"},
+		},
+		{
+			name:  "broken HTML",
+			url:   "not a url",
+			title: "QA Engineer",
+			page:  `<<div <p>unclosed <script type="application/ld+json">{`,
+			want:  posting.Posting{URL: "not a url", Title: "QA Engineer"},
+		},
+		{
+			name: "empty",
+			want: posting.Posting{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := posting.Parse(tt.url, tt.title, tt.page); got != tt.want {
+				t.Errorf("Parse() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDescription(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		want        string
+	}{
+		{"plain text", "  Build things.  ", "Build things."},
+		{"paragraphs", "<p>One   two</p>\n<p>Three</p>", "One two\nThree"},
+		{"list", "<ul><li>Go</li><li> SQL </li></ul>", "- Go\n- SQL"},
+		{"line break", "Line<br>Next", "Line\nNext"},
+		{"scripts and styles", "<div>Keep<script>alert(1)</script><style>p { color: red }</style></div>", "Keep"},
+		{"empty list item", "<ul><li></li></ul>", ""},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := jobPage(t, map[string]any{"@type": "JobPosting", "title": "Engineer", "description": tt.description})
+			if got := posting.Parse("", "", page).Description; got != tt.want {
+				t.Errorf("Description = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameName(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Acme Labs", "acme-labs", true},
+		{"Acme, Inc.", "ACME INC", true},
+		{"Acme", "Acme Labs", false},
+		{"", "", false},
+		{"!!", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := posting.SameName(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameName(%q, %q) = %t, want %t", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
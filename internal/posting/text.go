@@ -0,0 +1,65 @@
+package posting
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// blocks are the elements that start on a line of their own
+var blocks = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Br: true, atom.Li: true, atom.Ul: true, atom.Ol: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Tr: true, atom.Table: true, atom.Section: true, atom.Article: true, atom.Blockquote: true,
+}
+
+// htmlText turns an HTML description into plain text: one line per
+// paragraph, list items starting with "- ", and whitespace collapsed
+func htmlText(s string) string {
+	if !strings.Contains(s, "<") {
+		return strings.TrimSpace(s)
+	}
+	nodes, err := html.ParseFragment(strings.NewReader(s), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return strings.TrimSpace(s)
+	}
+
+	var lines []string
+	var line strings.Builder
+	endLine := func() {
+		if text := strings.Join(strings.Fields(line.String()), " "); text != "" && text != "-" {
+			lines = append(lines, text)
+		}
+		line.Reset()
+	}
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			line.WriteString(n.Data)
+			return
+		case n.Type != html.ElementNode:
+		case n.DataAtom == atom.Script || n.DataAtom == atom.Style:
+			return
+		case blocks[n.DataAtom]:
+			endLine()
+			if n.DataAtom == atom.Li {
+				line.WriteString("- ")
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if n.Type == html.ElementNode && blocks[n.DataAtom] {
+			endLine()
+		}
+	}
+	for _, n := range nodes {
+		walk(n)
+	}
+	endLine()
+
+	return strings.Join(lines, "\n")
+}
@@ -1,10 +1,17 @@
-package util
-
-// Collection names
-const (
-	CollectionCompanies          = "companies"
-	CollectionRoles              = "roles"
-	CollectionContacts           = "contacts"
-	CollectionInterviews         = "interviews"
-	CollectionInterviewsContacts = "interviews_contacts"
-)
+package util
+
+// Collection names
+const (
+	CollectionCompanies          = "companies"
+	CollectionRoles              = "roles"
+	CollectionContacts           = "contacts"
+	CollectionInterviews         = "interviews"
+	CollectionInterviewsContacts = "interviews_contacts"
+	CollectionRoleStatusEvents   = "role_status_events"
+	CollectionOffers             = "offers"
+	CollectionTasks              = "tasks"
+	CollectionDocuments          = "documents"
+	CollectionTags               = "tags"
+	CollectionWebhooks           = "webhooks"
+	CollectionWebhookDeliveries  = "webhook_deliveries"
+)
@@ -0,0 +1,25 @@
+package util
+
+import "os"
+
+// Environment variables read by the server and the CLIs
+const (
+	EnvDataDir   = "REVERSE_ATS_DATA_DIR"
+	EnvAddr      = "REVERSE_ATS_ADDR"
+	EnvPort      = "REVERSE_ATS_PORT"
+	EnvImportDir = "REVERSE_ATS_IMPORT_DIR"
+	EnvExportDir = "REVERSE_ATS_EXPORT_DIR"
+
+	// Tokens that turn on the calendar feed, the JSON API and capture
+	EnvCalendarToken = "REVERSE_ATS_CALENDAR_TOKEN"
+	EnvAPIToken      = "REVERSE_ATS_API_TOKEN"
+	EnvCaptureToken  = "REVERSE_ATS_CAPTURE_TOKEN"
+)
+
+// Getenv returns the value of an environment variable, or fallback when it is unset or empty
+func Getenv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
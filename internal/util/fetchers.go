@@ -1,73 +1,181 @@
-package util
-
-import (
-	"reverse-ats/internal/models"
-
-	"github.com/pocketbase/pocketbase"
-)
-
-// FetchCompaniesForDropdown fetches all companies and converts them to models.Company
-// This is used by handlers that need to populate company dropdowns
-func FetchCompaniesForDropdown(app *pocketbase.PocketBase) ([]models.Company, error) {
-	// Fetch all companies sorted by name
-	companyRecords, err := app.FindRecordsByFilter(CollectionCompanies, "", "name", -1, 0)
-	if err != nil {
-		return nil, err
-	}
-
-	companies := make([]models.Company, len(companyRecords))
-	for i, record := range companyRecords {
-		companies[i] = models.Company{
-			ID:          record.Id,
-			Name:        record.GetString("name"),
-			Description: record.GetString("description"),
-			Url:         record.GetString("url"),
-			Linkedin:    record.GetString("linkedin"),
-			HqCity:      record.GetString("hqCity"),
-			HqState:     record.GetString("hqState"),
-		}
-	}
-
-	return companies, nil
-}
-
-// FetchCompaniesMap fetches all companies and returns them as a map[id]name
-// This is used to avoid N+1 queries when populating company names in lists
-func FetchCompaniesMap(app *pocketbase.PocketBase) (map[string]string, error) {
-	companyRecords, err := app.FindRecordsByFilter(CollectionCompanies, "", "", -1, 0)
-	if err != nil {
-		return nil, err
-	}
-
-	companiesMap := make(map[string]string, len(companyRecords))
-	for _, record := range companyRecords {
-		companiesMap[record.Id] = record.GetString("name")
-	}
-
-	return companiesMap, nil
-}
-
-// RoleInfo holds basic role information for map lookups
-type RoleInfo struct {
-	Name      string
-	CompanyID string
-}
-
-// FetchRolesMap fetches all roles and returns them as a map[id]RoleInfo
-// This is used to avoid N+1 queries when populating role names in lists
-func FetchRolesMap(app *pocketbase.PocketBase) (map[string]RoleInfo, error) {
-	roleRecords, err := app.FindRecordsByFilter(CollectionRoles, "", "", -1, 0)
-	if err != nil {
-		return nil, err
-	}
-
-	rolesMap := make(map[string]RoleInfo, len(roleRecords))
-	for _, record := range roleRecords {
-		rolesMap[record.Id] = RoleInfo{
-			Name:      record.GetString("name"),
-			CompanyID: record.GetString("company"),
-		}
-	}
-
-	return rolesMap, nil
-}
+package util
+
+import (
+	"reverse-ats/internal/models"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+)
+
+// FetchCompaniesForDropdown fetches all companies and converts them to models.Company
+// This is used by handlers that need to populate company dropdowns
+func FetchCompaniesForDropdown(app *pocketbase.PocketBase) ([]models.Company, error) {
+	// Fetch all companies sorted by name
+	companyRecords, err := app.FindRecordsByFilter(CollectionCompanies, "", "name", -1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	companies := make([]models.Company, len(companyRecords))
+	for i, record := range companyRecords {
+		companies[i] = models.Company{
+			ID:          record.Id,
+			Name:        record.GetString("name"),
+			Description: record.GetString("description"),
+			Url:         record.GetString("url"),
+			Linkedin:    record.GetString("linkedin"),
+			HqCity:      record.GetString("hqCity"),
+			HqState:     record.GetString("hqState"),
+		}
+	}
+
+	return companies, nil
+}
+
+// FetchCompaniesMap fetches all companies and returns them as a map[id]name
+// This is used to avoid N+1 queries when populating company names in lists
+func FetchCompaniesMap(app *pocketbase.PocketBase) (map[string]string, error) {
+	companyRecords, err := app.FindRecordsByFilter(CollectionCompanies, "", "", -1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	companiesMap := make(map[string]string, len(companyRecords))
+	for _, record := range companyRecords {
+		companiesMap[record.Id] = record.GetString("name")
+	}
+
+	return companiesMap, nil
+}
+
+// RoleInfo holds basic role information for map lookups
+type RoleInfo struct {
+	Name      string
+	CompanyID string
+}
+
+// FetchRolesMap fetches all roles and returns them as a map[id]RoleInfo
+// This is used to avoid N+1 queries when populating role names in lists
+func FetchRolesMap(app *pocketbase.PocketBase) (map[string]RoleInfo, error) {
+	roleRecords, err := app.FindRecordsByFilter(CollectionRoles, "", "", -1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	rolesMap := make(map[string]RoleInfo, len(roleRecords))
+	for _, record := range roleRecords {
+		rolesMap[record.Id] = RoleInfo{
+			Name:      record.GetString("name"),
+			CompanyID: record.GetString("company"),
+		}
+	}
+
+	return rolesMap, nil
+}
+
+// FetchContactsForCompany fetches the contacts of a company sorted by name
+// This is used to populate the interview participants select
+func FetchContactsForCompany(app *pocketbase.PocketBase, companyID string) ([]models.Contact, error) {
+	contactRecords, err := app.FindRecordsByFilter(
+		CollectionContacts,
+		"company = {:company}",
+		"first_name,last_name",
+		-1,
+		0,
+		dbx.Params{"company": companyID},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts := make([]models.Contact, len(contactRecords))
+	for i, record := range contactRecords {
+		contacts[i] = models.Contact{
+			ID:        record.Id,
+			CompanyID: record.GetString("company"),
+			FirstName: record.GetString("first_name"),
+			LastName:  record.GetString("last_name"),
+			Role:      record.GetString("role"),
+			Email:     record.GetString("email"),
+		}
+	}
+
+	return contacts, nil
+}
+
+// FetchRolesForDropdown fetches all roles sorted by name with their company names
+// This is used by handlers that need to populate role dropdowns
+func FetchRolesForDropdown(app *pocketbase.PocketBase) ([]models.Role, error) {
+	roleRecords, err := app.FindRecordsByFilter(CollectionRoles, "", "name", -1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	companiesMap, err := FetchCompaniesMap(app)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]models.Role, len(roleRecords))
+	for i, record := range roleRecords {
+		roles[i] = models.Role{
+			ID:          record.Id,
+			Name:        record.GetString("name"),
+			CompanyID:   record.GetString("company"),
+			CompanyName: companiesMap[record.GetString("company")],
+			Status:      record.GetString("status"),
+		}
+	}
+
+	return roles, nil
+}
+
+// FetchContactsForDropdown fetches all contacts sorted by name with their company names
+// This is used by handlers that need to populate contact dropdowns
+func FetchContactsForDropdown(app *pocketbase.PocketBase) ([]models.Contact, error) {
+	contactRecords, err := app.FindRecordsByFilter(CollectionContacts, "", "first_name,last_name", -1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	companiesMap, err := FetchCompaniesMap(app)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts := make([]models.Contact, len(contactRecords))
+	for i, record := range contactRecords {
+		contacts[i] = models.Contact{
+			ID:          record.Id,
+			CompanyID:   record.GetString("company"),
+			CompanyName: companiesMap[record.GetString("company")],
+			FirstName:   record.GetString("first_name"),
+			LastName:    record.GetString("last_name"),
+			Role:        record.GetString("role"),
+		}
+	}
+
+	return contacts, nil
+}
+
+// FetchDocuments fetches the document library sorted by kind and label
+// This is used by the documents page and the role form document selects
+func FetchDocuments(app *pocketbase.PocketBase) ([]models.Document, error) {
+	documentRecords, err := app.FindRecordsByFilter(CollectionDocuments, "", "kind,label", -1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	documents := make([]models.Document, len(documentRecords))
+	for i, record := range documentRecords {
+		documents[i] = models.Document{
+			ID:    record.Id,
+			Label: record.GetString("label"),
+			Kind:  record.GetString("kind"),
+			File:  record.GetString("file"),
+			Notes: record.GetString("notes"),
+		}
+	}
+
+	return documents, nil
+}
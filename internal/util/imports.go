@@ -0,0 +1,18 @@
+package util
+
+import "context"
+
+// importKey marks the context of an import
+type importKey struct{}
+
+// WithImport marks a context as that of an import. Records saved with it
+// don't send webhook events, which would otherwise be one per imported row.
+func WithImport(ctx context.Context) context.Context {
+	return context.WithValue(ctx, importKey{}, true)
+}
+
+// IsImport reports whether a context is marked by WithImport
+func IsImport(ctx context.Context) bool {
+	imported, _ := ctx.Value(importKey{}).(bool)
+	return imported
+}
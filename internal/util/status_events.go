@@ -0,0 +1,74 @@
+package util
+
+import (
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+
+	"reverse-ats/internal/models"
+)
+
+// Sources of a role status change
+const (
+	StatusSourceForm      = "form"
+	StatusSourceInline    = "inline"
+	StatusSourceImport    = "import"
+	StatusSourceMigration = "migration" // The status roles had when the history was added
+	StatusSourceAPI       = "api"
+	StatusSourceCapture   = "capture"
+	StatusSourceBulk      = "bulk"
+)
+
+// RecordRoleStatusChange stores a role_status_events entry for a role.
+// It does nothing when the status did not change.
+// Accepts core.App so it can be called from inside a transaction.
+func RecordRoleStatusChange(app core.App, roleID, fromStatus, toStatus, source string, changedAt time.Time) error {
+	if fromStatus == toStatus {
+		return nil
+	}
+
+	collection, err := app.FindCollectionByNameOrId(CollectionRoleStatusEvents)
+	if err != nil {
+		return err
+	}
+
+	event := core.NewRecord(collection)
+	event.Set("role", roleID)
+	event.Set("from_status", fromStatus)
+	event.Set("to_status", toStatus)
+	event.Set("source", source)
+	event.Set("changed_at", changedAt.UTC())
+
+	return app.Save(event)
+}
+
+// FetchRoleStatusEvents fetches the status history of a role, oldest first
+func FetchRoleStatusEvents(app *pocketbase.PocketBase, roleID string) ([]models.RoleStatusEvent, error) {
+	records, err := app.FindRecordsByFilter(
+		CollectionRoleStatusEvents,
+		"role = {:role}",
+		"changed_at",
+		-1,
+		0,
+		dbx.Params{"role": roleID},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]models.RoleStatusEvent, len(records))
+	for i, record := range records {
+		events[i] = models.RoleStatusEvent{
+			ID:         record.Id,
+			RoleID:     record.GetString("role"),
+			FromStatus: record.GetString("from_status"),
+			ToStatus:   record.GetString("to_status"),
+			Source:     record.GetString("source"),
+			ChangedAt:  record.GetDateTime("changed_at").String(),
+		}
+	}
+
+	return events, nil
+}
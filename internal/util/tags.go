@@ -0,0 +1,80 @@
+package util
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"reverse-ats/internal/models"
+)
+
+// FetchTags fetches all tags sorted by name
+// This is used by the tag filters on the list pages and the tag inputs
+func FetchTags(app core.App) ([]models.Tag, error) {
+	tagRecords, err := app.FindRecordsByFilter(CollectionTags, "", "name", -1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]models.Tag, len(tagRecords))
+	for i, record := range tagRecords {
+		tags[i] = models.Tag{ID: record.Id, Name: record.GetString("name")}
+	}
+
+	return tags, nil
+}
+
+// FindTagByName looks up a tag by name, ignoring case. Returns nil if there is none.
+func FindTagByName(app core.App, name string) (*models.Tag, error) {
+	record := &core.Record{}
+	err := app.RecordQuery(CollectionTags).
+		AndWhere(dbx.NewExp("name = {:name} COLLATE NOCASE", dbx.Params{"name": strings.TrimSpace(name)})).
+		Limit(1).
+		One(record)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Tag{ID: record.Id, Name: record.GetString("name")}, nil
+}
+
+// FindOrCreateTags returns the IDs of the named tags, creating the missing ones.
+// Names are matched ignoring case so "Fintech" and "fintech" share a tag.
+// Accepts core.App so it can be called from inside a transaction.
+func FindOrCreateTags(app core.App, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return []string{}, nil
+	}
+
+	collection, err := app.FindCollectionByNameOrId(CollectionTags)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		tag, err := FindTagByName(app, name)
+		if err != nil {
+			return nil, err
+		}
+		if tag != nil {
+			ids = append(ids, tag.ID)
+			continue
+		}
+
+		record := core.NewRecord(collection)
+		record.Set("name", name)
+		if err := app.Save(record); err != nil {
+			return nil, err
+		}
+		ids = append(ids, record.Id)
+	}
+
+	return ids, nil
+}
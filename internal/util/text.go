@@ -0,0 +1,10 @@
+package util
+
+// Truncate cuts a string to at most max characters, never splitting one
+func Truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
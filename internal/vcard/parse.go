@@ -0,0 +1,265 @@
+package vcard
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"mime/quotedprintable"
+	"strings"
+)
+
+// property is one content line: [group.]NAME;PARAM=value:VALUE
+type property struct {
+	name   string
+	params map[string]string // Repeated parameters are joined with commas
+	value  string
+}
+
+// types returns the lowercase TYPE values of a property
+func (p property) types() []string {
+	return strings.Split(strings.ToLower(p.params["TYPE"]), ",")
+}
+
+// preferred reports whether a property is marked as the preferred one,
+// with PREF (4.0) or TYPE=pref (2.1 and 3.0)
+func (p property) preferred() bool {
+	if _, ok := p.params["PREF"]; ok {
+		return true
+	}
+	for _, t := range p.types() {
+		if t == "pref" {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse reads the cards of a vCard file, as exported by phones and email
+// clients. Versions 3.0 and 4.0 are read, as are the basics of 2.1.
+// Unknown properties, like photos, are ignored.
+func Parse(r io.Reader) ([]Card, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var cards []Card
+	var card *Card
+	depth := 0 // Cards nested inside the current card, like 2.1 agents
+
+	for i, line := range lines {
+		prop, err := parseProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VCARD") && card == nil:
+			card = &Card{}
+			continue
+		case prop.name == "BEGIN" && card != nil:
+			depth++
+			continue
+		case prop.name == "END" && card != nil && depth > 0:
+			depth--
+			continue
+		case prop.name == "END" && strings.EqualFold(prop.value, "VCARD") && card != nil:
+			cards = append(cards, *card)
+			card = nil
+			continue
+		}
+		if card == nil || depth > 0 {
+			continue
+		}
+
+		if strings.EqualFold(prop.params["ENCODING"], "QUOTED-PRINTABLE") {
+			decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(prop.value)))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s: %w", i+1, prop.name, err)
+			}
+			prop.value = string(decoded)
+		}
+
+		switch prop.name {
+		case "UID":
+			card.UID = unescapeText(prop.value)
+		case "N":
+			names := splitValue(prop.value, ';')
+			card.LastName = unescapeText(names[0])
+			if len(names) > 1 {
+				card.FirstName = unescapeText(names[1])
+			}
+		case "FN":
+			card.FullName = unescapeText(prop.value)
+		case "ORG":
+			card.Organization = unescapeText(splitValue(prop.value, ';')[0])
+		case "TITLE":
+			card.Title = unescapeText(prop.value)
+		case "ROLE":
+			if card.Title == "" {
+				card.Title = unescapeText(prop.value)
+			}
+		case "EMAIL":
+			card.Emails = addValue(card.Emails, strings.TrimPrefix(strings.TrimSpace(prop.value), "mailto:"), prop.preferred())
+		case "TEL":
+			card.Phones = addValue(card.Phones, strings.TrimPrefix(unescapeText(strings.TrimSpace(prop.value)), "tel:"), prop.preferred())
+		case "URL", "X-SOCIALPROFILE", "SOCIALPROFILE":
+			url := strings.TrimSpace(unescapeText(prop.value))
+			if card.LinkedIn == "" && isLinkedIn(prop, url) {
+				card.LinkedIn = url
+			} else if prop.name == "URL" && url != "" && url != card.LinkedIn {
+				card.URLs = append(card.URLs, url)
+			}
+		case "NOTE":
+			card.Note = unescapeText(prop.value)
+		case "CATEGORIES":
+			for _, category := range splitValue(prop.value, ',') {
+				if category = strings.TrimSpace(unescapeText(category)); category != "" {
+					card.Categories = append(card.Categories, category)
+				}
+			}
+		}
+	}
+
+	return cards, nil
+}
+
+// isLinkedIn reports whether a URL or social profile is a LinkedIn profile
+func isLinkedIn(prop property, url string) bool {
+	if strings.Contains(strings.ToLower(url), "linkedin.com/") {
+		return true
+	}
+	for _, t := range prop.types() {
+		if t == "linkedin" {
+			return true
+		}
+	}
+	return strings.EqualFold(prop.params["SERVICE-TYPE"], "linkedin")
+}
+
+// addValue adds a value to a list, putting preferred values first
+func addValue(values []string, value string, preferred bool) []string {
+	if value == "" {
+		return values
+	}
+	if preferred {
+		return append([]string{value}, values...)
+	}
+	return append(values, value)
+}
+
+// unfold reads the content lines of a file, joining lines continued on lines
+// that start with a space or tab, and 2.1 quoted-printable soft line breaks
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	var lines []string
+	softBreak := false
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		switch {
+		case softBreak && len(lines) > 0:
+			lines[len(lines)-1] += line
+		case (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0:
+			lines[len(lines)-1] += line[1:]
+		case line != "":
+			lines = append(lines, line)
+		default:
+			continue
+		}
+
+		last := lines[len(lines)-1]
+		softBreak = strings.HasSuffix(last, "=") && strings.Contains(strings.ToUpper(last), "QUOTED-PRINTABLE")
+		if softBreak {
+			lines[len(lines)-1] = strings.TrimSuffix(last, "=")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read vCard: %w", err)
+	}
+	return lines, nil
+}
+
+// parseProperty splits a content line into its name, parameters and value.
+// Quoted parameter values may hold ':' and ';'. Group prefixes, as in
+// "item1.EMAIL", are dropped, and 2.1 parameters without a name are types.
+func parseProperty(line string) (property, error) {
+	prop := property{params: make(map[string]string)}
+	addParam := func(name, value string) {
+		if prop.params[name] != "" {
+			value = prop.params[name] + "," + value
+		}
+		prop.params[name] = value
+	}
+
+	quoted := false
+	start := 0
+	paramName := ""
+	named := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '=' && named && paramName == "":
+			paramName = strings.ToUpper(line[start:i])
+			start = i + 1
+		case c == ';' || c == ':':
+			part := line[start:i]
+			switch {
+			case !named:
+				name := strings.ToUpper(part)
+				if dot := strings.LastIndex(name, "."); dot >= 0 {
+					name = name[dot+1:]
+				}
+				prop.name = name
+				named = true
+			case paramName != "":
+				addParam(paramName, strings.Trim(part, `"`))
+				paramName = ""
+			case part != "":
+				addParam("TYPE", part)
+			}
+			start = i + 1
+			if c == ':' {
+				prop.value = line[start:]
+				return prop, nil
+			}
+		}
+	}
+
+	return prop, fmt.Errorf("invalid content line %q", line)
+}
+
+// splitValue splits a structured or list value on the separators that aren't escaped
+func splitValue(value string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
+
+// textUnescaper reverses escapeText
+var textUnescaper = strings.NewReplacer(
+	`\\`, `\`,
+	`\;`, ";",
+	`\,`, ",",
+	`\:`, ":",
+	`\n`, "\n",
+	`\N`, "\n",
+)
+
+// unescapeText reads a text value
+func unescapeText(s string) string {
+	return textUnescaper.Replace(s)
+}
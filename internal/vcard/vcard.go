@@ -0,0 +1,138 @@
+package vcard
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"reverse-ats/internal/contentline"
+)
+
+// Supported vCard versions. 3.0 is read by nearly every phone and email
+// client; 4.0 is the current standard.
+const (
+	Version3 = "3.0"
+	Version4 = "4.0"
+)
+
+const productID = "-//reverse-ats//Contacts//EN"
+
+// Card is a contact card
+type Card struct {
+	UID          string
+	FirstName    string
+	LastName     string
+	FullName     string // Display name; the first and last name when empty
+	Organization string
+	Title        string
+	Emails       []string // Preferred address first
+	Phones       []string // Preferred number first
+	LinkedIn     string
+	URLs         []string // Other web pages
+	Note         string
+	Categories   []string
+}
+
+// Name returns the display name of a card
+func (c Card) Name() string {
+	if c.FullName != "" {
+		return c.FullName
+	}
+	return strings.TrimSpace(c.FirstName + " " + c.LastName)
+}
+
+// Write writes cards in the given version, 3.0 or 4.0
+func Write(w io.Writer, version string, cards []Card) error {
+	if version != Version3 && version != Version4 {
+		return fmt.Errorf("unsupported vCard version %q", version)
+	}
+
+	cw := contentline.NewWriter(w)
+	for _, card := range cards {
+		cw.Line("BEGIN:VCARD")
+		cw.Line("VERSION:" + version)
+		cw.Line("PRODID:" + productID)
+		if card.UID != "" {
+			cw.Line("UID:" + escapeText(card.UID))
+		}
+		cw.Line("N:" + escapeText(card.LastName) + ";" + escapeText(card.FirstName) + ";;;")
+		cw.Line("FN:" + escapeText(card.Name()))
+		if card.Organization != "" {
+			cw.Line("ORG:" + escapeText(card.Organization))
+		}
+		if card.Title != "" {
+			cw.Line("TITLE:" + escapeText(card.Title))
+		}
+		for i, email := range card.Emails {
+			cw.Line(emailProperty(version, i == 0) + ":" + email)
+		}
+		for i, phone := range card.Phones {
+			cw.Line(phoneProperty(version, i == 0) + ":" + escapeText(phone))
+		}
+		if card.LinkedIn != "" {
+			// URL for every client; X-SOCIALPROFILE shows it as a profile in Apple Contacts
+			cw.Line("URL:" + card.LinkedIn)
+			cw.Line("X-SOCIALPROFILE;TYPE=linkedin:" + card.LinkedIn)
+		}
+		for _, url := range card.URLs {
+			cw.Line("URL:" + url)
+		}
+		if card.Note != "" {
+			cw.Line("NOTE:" + escapeText(card.Note))
+		}
+		if len(card.Categories) > 0 {
+			categories := make([]string, len(card.Categories))
+			for i, category := range card.Categories {
+				categories[i] = escapeText(category)
+			}
+			cw.Line("CATEGORIES:" + strings.Join(categories, ","))
+		}
+		cw.Line("END:VCARD")
+	}
+
+	return cw.Flush()
+}
+
+// emailProperty returns the EMAIL property name and parameters of a version
+func emailProperty(version string, preferred bool) string {
+	switch {
+	case version == Version3 && preferred:
+		return "EMAIL;TYPE=INTERNET,WORK,PREF"
+	case version == Version3:
+		return "EMAIL;TYPE=INTERNET,WORK"
+	case preferred:
+		return "EMAIL;TYPE=work;PREF=1"
+	default:
+		return "EMAIL;TYPE=work"
+	}
+}
+
+// phoneProperty returns the TEL property name and parameters of a version.
+// 4.0 numbers are tel: URIs by default, so free-form numbers are marked as text.
+func phoneProperty(version string, preferred bool) string {
+	switch {
+	case version == Version3 && preferred:
+		return "TEL;TYPE=WORK,VOICE,PREF"
+	case version == Version3:
+		return "TEL;TYPE=WORK,VOICE"
+	case preferred:
+		return "TEL;VALUE=text;TYPE=work,voice;PREF=1"
+	default:
+		return "TEL;VALUE=text;TYPE=work,voice"
+	}
+}
+
+// textEscaper escapes the characters text values can't hold as is
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// escapeText escapes a text value or a component of a structured value
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
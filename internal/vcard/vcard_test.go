@@ -0,0 +1,221 @@
+package vcard_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"reverse-ats/internal/vcard"
+)
+
+// A card exported by Apple Contacts, with grouped and folded lines
+const appleCard = `BEGIN:VCARD
+VERSION:3.0
+PRODID:-//Apple Inc.//iPhone OS 18.1//EN
+N:Recruiter;Pat;;;
+FN:Pat Recruiter
+ORG:Acme Labs\, Inc.;Talent
+TITLE:Senior Technical Recruiter
+item1.EMAIL;type=INTERNET;type=HOME:pat@example.com
+EMAIL;type=INTERNET;type=WORK;type=pref:pat@acme.example.com
+TEL;type=CELL;type=VOICE:+1 555 0100
+TEL;type=WORK;type=VOICE;type=pref:+1 555 0199
+item2.URL;type=pref:https://www.linkedin.com/in/
+ pat-recruiter
+item2.X-ABLabel:_$!<HomePage>!$_
+URL:https://acme.example.com
+NOTE:Met at the meetup\; follows up on Fridays.\nPrefers email\, not calls.
+  Path: C:\\jobs
+CATEGORIES:Recruiters,Bay Area\, CA,myContacts
+PHOTO;ENCODING=b;TYPE=JPEG:/9j/4AAQSkZJRgABAQAAAQABAAD
+ /2wBDAAgGBgcGBQgHBwcJCQgKDBQNDAsLDBkSEw8UHRofHh0aHBwgJC4n
+END:VCARD
+`
+
+// A 2.1 card, with quoted-printable soft line breaks and an agent card
+const card21 = `BEGIN:VCARD
+VERSION:2.1
+N;CHARSET=UTF-8;ENCODING=QUOTED-PRINTABLE:M=C3=BCller;J=C3=
+=BCrgen
+TEL;CELL:555-0123
+TEL;WORK;PREF:555-0124
+EMAIL;INTERNET:jurgen@example.com
+AGENT:
+BEGIN:VCARD
+FN:Assistant
+EMAIL:assistant@example.com
+END:VCARD
+END:VCARD
+`
+
+// parse parses a file with CRLF line endings, as clients write them
+func parse(t *testing.T, data string) []vcard.Card {
+	t.Helper()
+
+	cards, err := vcard.Parse(strings.NewReader(strings.ReplaceAll(data, "\n", "\r\n")))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return cards
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []vcard.Card
+	}{
+		{
+			name: "Apple Contacts",
+			data: appleCard,
+			want: []vcard.Card{{
+				FirstName:    "Pat",
+				LastName:     "Recruiter",
+				FullName:     "Pat Recruiter",
+				Organization: "Acme Labs, Inc.",
+				Title:        "Senior Technical Recruiter",
+				Emails:       []string{"pat@acme.example.com", "pat@example.com"},
+				Phones:       []string{"+1 555 0199", "+1 555 0100"},
+				LinkedIn:     "https://www.linkedin.com/in/pat-recruiter",
+				URLs:         []string{"https://acme.example.com"},
+				Note:         "Met at the meetup; follows up on Fridays.\nPrefers email, not calls. Path: C:\\jobs",
+				Categories:   []string{"Recruiters", "Bay Area, CA", "myContacts"},
+			}},
+		},
+		{
+			name: "version 2.1",
+			data: card21,
+			want: []vcard.Card{{
+				FirstName: "Jürgen",
+				LastName:  "Müller",
+				Emails:    []string{"jurgen@example.com"},
+				Phones:    []string{"555-0124", "555-0123"},
+			}},
+		},
+		{
+			name: "version 4.0",
+			data: `BEGIN:VCARD
+VERSION:4.0
+UID:urn:uuid:4fbe8971-0bc3-424c-9c26-36c3e1eff6b1
+FN:Sam Hiring
+EMAIL;TYPE=work;PREF=1:mailto:sam@globex.example.com
+EMAIL;TYPE=home:sam@example.com
+TEL;VALUE=uri;TYPE=cell:tel:+1-555-0150
+X-SOCIALPROFILE;TYPE=linkedin:https://linkedin.com/in/sam-hiring
+CATEGORIES:Hiring managers
+END:VCARD
+`,
+			want: []vcard.Card{{
+				UID:        "urn:uuid:4fbe8971-0bc3-424c-9c26-36c3e1eff6b1",
+				FullName:   "Sam Hiring",
+				Emails:     []string{"sam@globex.example.com", "sam@example.com"},
+				Phones:     []string{"+1-555-0150"},
+				LinkedIn:   "https://linkedin.com/in/sam-hiring",
+				Categories: []string{"Hiring managers"},
+			}},
+		},
+		{
+			name: "several cards",
+			data: "BEGIN:VCARD\nFN:One\nEND:VCARD\nBEGIN:VCARD\nFN:Two\nEND:VCARD\n",
+			want: []vcard.Card{{FullName: "One"}, {FullName: "Two"}},
+		},
+		{
+			name: "empty",
+			data: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parse(t, tt.data); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInvalidLine(t *testing.T) {
+	_, err := vcard.Parse(strings.NewReader("BEGIN:VCARD\r\nFN Pat\r\nEND:VCARD\r\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Parse() error = %v, want one for line 2", err)
+	}
+}
+
+// testCard has every field, with values that need escaping and lines long enough to fold
+var testCard = vcard.Card{
+	UID:          "contact-1",
+	FirstName:    "Pat",
+	LastName:     "O'Reilly; Jr.",
+	Organization: "Acme Labs, Inc.",
+	Title:        "Senior Technical Recruiter",
+	Emails:       []string{"pat@acme.example.com", "pat@example.com"},
+	Phones:       []string{"+1 555 0199", "+1 555 0100"},
+	LinkedIn:     "https://www.linkedin.com/in/pat-recruiter",
+	URLs:         []string{"https://acme.example.com"},
+	Note:         "Met at the meetup; follows up on Fridays.\nPrefers email, not calls. Keeps notes in C:\\jobs\\acme — ask about the platform team.",
+	Categories:   []string{"Recruiters", "Bay Area, CA"},
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	if err := vcard.Write(&buf, vcard.Version3, []vcard.Card{testCard}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCARD\r\nVERSION:3.0\r\n",
+		"\r\nN:O'Reilly\\; Jr.;Pat;;;\r\n",
+		"\r\nFN:Pat O'Reilly\\; Jr.\r\n",
+		"\r\nORG:Acme Labs\\, Inc.\r\n",
+		"\r\nEMAIL;TYPE=INTERNET,WORK,PREF:pat@acme.example.com\r\n",
+		"\r\nEMAIL;TYPE=INTERNET,WORK:pat@example.com\r\n",
+		"\r\nTEL;TYPE=WORK,VOICE,PREF:+1 555 0199\r\n",
+		"\r\nTEL;TYPE=WORK,VOICE:+1 555 0100\r\n",
+		"\r\nX-SOCIALPROFILE;TYPE=linkedin:https://www.linkedin.com/in/pat-recruiter\r\n",
+		"\r\nCATEGORIES:Recruiters,Bay Area\\, CA\r\n",
+		"\r\nEND:VCARD\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line of %d octets isn't folded: %q", len(line), line)
+		}
+	}
+	if !strings.Contains(out, "\r\n ") {
+		t.Errorf("the note isn't folded:\n%s", out)
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	for _, version := range []string{vcard.Version3, vcard.Version4} {
+		t.Run(version, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := vcard.Write(&buf, version, []vcard.Card{testCard, {FirstName: "Sam", LastName: "Hiring"}}); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+
+			cards, err := vcard.Parse(&buf)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			want := testCard
+			want.FullName = want.Name()
+			second := vcard.Card{FirstName: "Sam", LastName: "Hiring", FullName: "Sam Hiring"}
+			if !reflect.DeepEqual(cards, []vcard.Card{want, second}) {
+				t.Errorf("read back %+v\nwant %+v", cards, []vcard.Card{want, second})
+			}
+		})
+	}
+}
+
+func TestWriteUnsupportedVersion(t *testing.T) {
+	if err := vcard.Write(&bytes.Buffer{}, "2.1", nil); err == nil {
+		t.Error("Write() accepted version 2.1")
+	}
+}
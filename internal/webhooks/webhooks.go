@@ -0,0 +1,475 @@
+// Package webhooks POSTs signed JSON to the webhooks when roles, interviews
+// and contacts change. Every delivery is logged in the webhook_deliveries
+// collection, and failed ones are retried with backoff.
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/util"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderEvent     = "X-Reverse-ATS-Event"
+	HeaderDelivery  = "X-Reverse-ATS-Delivery" // The same on every retry
+	HeaderTimestamp = "X-Reverse-ATS-Timestamp"
+	HeaderSignature = "X-Reverse-ATS-Signature" // Only when the webhook has a secret
+)
+
+// DefaultBackoff is the wait before each retry of a failed delivery
+var DefaultBackoff = []time.Duration{time.Minute, 5 * time.Minute, 30 * time.Minute, 2 * time.Hour, 12 * time.Hour}
+
+// Background sending limits. Deliveries that don't fit in the queue stay
+// pending and are sent by RetryDue.
+const (
+	DefaultWorkers   = 4
+	DefaultQueueSize = 256
+)
+
+// Delivery log limits
+const (
+	maxResponseBody   = 2000
+	maxError          = 1000
+	deliveryRetention = 30 * 24 * time.Hour
+)
+
+// Converter turns a record into what is sent of it, like the model the JSON API returns
+type Converter func(app core.App, record *core.Record) (any, error)
+
+// Dispatcher sends the events of the watched collections to the webhooks subscribed to them
+type Dispatcher struct {
+	app core.App
+
+	Client  *http.Client
+	Backoff []time.Duration // A delivery is failed after len(Backoff)+1 attempts
+
+	Workers   int // Deliveries sent at once in the background
+	QueueSize int // Deliveries waiting for a worker
+
+	mu       sync.Mutex
+	inFlight map[string]bool // Deliveries being sent, by ID
+	stopped  bool            // Queued deliveries are left for RetryDue
+	wg       sync.WaitGroup  // Queued and in-flight background deliveries
+
+	startOnce sync.Once
+	queue     chan string // IDs of the deliveries to send in the background
+}
+
+func NewDispatcher(app core.App) *Dispatcher {
+	return &Dispatcher{
+		app:       app,
+		Client:    &http.Client{Timeout: 10 * time.Second},
+		Backoff:   DefaultBackoff,
+		Workers:   DefaultWorkers,
+		QueueSize: DefaultQueueSize,
+		inFlight:  make(map[string]bool),
+	}
+}
+
+// Start retries due deliveries every minute, including the ones a restart
+// interrupted, and waits for deliveries being sent when the app stops.
+// Deliveries still queued then are sent by RetryDue after the restart.
+func (d *Dispatcher) Start() {
+	d.app.Cron().MustAdd("webhookDeliveries", "* * * * *", func() {
+		if err := d.RetryDue(); err != nil {
+			d.app.Logger().Error("Failed to retry webhook deliveries", "error", err)
+		}
+	})
+	d.app.OnTerminate().BindFunc(func(e *core.TerminateEvent) error {
+		d.Stop()
+		return e.Next()
+	})
+}
+
+// Watch sends the created, updated and deleted events of a collection's
+// records, named after the record, like "role.created". Records with a
+// status also send a status_changed event when it changes. Records saved
+// by an import, marked with util.WithImport, send none.
+func (d *Dispatcher) Watch(collection, name string, convert Converter) {
+	d.app.OnRecordAfterCreateSuccess(collection).BindFunc(func(e *core.RecordEvent) error {
+		if !util.IsImport(e.Context) {
+			d.enqueue(name+".created", e.Record, nil, convert)
+		}
+		return e.Next()
+	})
+	d.app.OnRecordAfterUpdateSuccess(collection).BindFunc(func(e *core.RecordEvent) error {
+		if util.IsImport(e.Context) {
+			return e.Next()
+		}
+		previous := e.Record.Original()
+		d.enqueue(name+".updated", e.Record, previous, convert)
+		if hasStatus(e.Record) && e.Record.GetString("status") != previous.GetString("status") {
+			d.enqueue(name+".status_changed", e.Record, previous, convert)
+		}
+		return e.Next()
+	})
+	d.app.OnRecordAfterDeleteSuccess(collection).BindFunc(func(e *core.RecordEvent) error {
+		d.enqueue(name+".deleted", e.Record, nil, convert)
+		return e.Next()
+	})
+}
+
+// enqueue logs a delivery of an event for each webhook subscribed to it and
+// queues them to be sent in the background. Errors are logged, since the
+// change is saved.
+func (d *Dispatcher) enqueue(event string, record, previous *core.Record, convert Converter) {
+	webhooks, err := d.subscribers(event, record)
+	if err != nil {
+		d.app.Logger().Error("Failed to find webhooks", "event", event, "error", err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	payload := models.WebhookPayload{
+		Event:  event,
+		Time:   time.Now().UTC().Format(time.RFC3339),
+		Record: d.convert(convert, record),
+	}
+	if previous != nil {
+		payload.Previous = d.convert(convert, previous)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		d.app.Logger().Error("Failed to encode webhook payload", "event", event, "error", err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		delivery, err := d.newDelivery(webhook.Id, event, body)
+		if err != nil {
+			d.app.Logger().Error("Failed to log webhook delivery", "event", event, "webhook", webhook.Id, "error", err)
+			continue
+		}
+		d.push(delivery.Id)
+	}
+}
+
+// push queues a pending delivery for the workers. When the queue is full or
+// the dispatcher is stopping it is left pending, for RetryDue to send.
+func (d *Dispatcher) push(id string) {
+	d.startOnce.Do(d.startWorkers)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stopped {
+		return
+	}
+	d.wg.Add(1)
+	select {
+	case d.queue <- id:
+	default:
+		d.wg.Done()
+		d.app.Logger().Warn("Webhook delivery queue is full, leaving the delivery for the next retry", "delivery", id)
+	}
+}
+
+// startWorkers starts the goroutines sending the queued deliveries
+func (d *Dispatcher) startWorkers() {
+	d.queue = make(chan string, max(d.QueueSize, 1))
+	for range max(d.Workers, 1) {
+		go func() {
+			for id := range d.queue {
+				if !d.isStopped() {
+					if err := d.Deliver(id); err != nil {
+						d.app.Logger().Error("Failed to deliver webhook", "delivery", id, "error", err)
+					}
+				}
+				d.wg.Done()
+			}
+		}()
+	}
+}
+
+func (d *Dispatcher) isStopped() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stopped
+}
+
+// subscribers returns the active webhooks subscribed to an event of a
+// record, leaving out those limited to statuses the record isn't in
+func (d *Dispatcher) subscribers(event string, record *core.Record) ([]*core.Record, error) {
+	webhooks, err := d.app.FindRecordsByFilter(
+		util.CollectionWebhooks,
+		"active = true && events:each ?= {:event}",
+		"",
+		0,
+		0,
+		dbx.Params{"event": event},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasStatus(record) {
+		return webhooks, nil
+	}
+	status := record.GetString("status")
+	return slices.DeleteFunc(webhooks, func(webhook *core.Record) bool {
+		statuses := webhook.GetStringSlice("statuses")
+		return len(statuses) > 0 && !slices.Contains(statuses, status)
+	}), nil
+}
+
+// convert converts a record for a payload, falling back to its fields when
+// the converter fails, like when a deleted record's relations are gone
+func (d *Dispatcher) convert(convert Converter, record *core.Record) any {
+	v, err := convert(d.app, record)
+	if err != nil {
+		d.app.Logger().Warn("Failed to convert record for webhook", "collection", record.Collection().Name, "id", record.Id, "error", err)
+		return record.PublicExport()
+	}
+	return v
+}
+
+// newDelivery logs a pending delivery of a payload to a webhook
+func (d *Dispatcher) newDelivery(webhookID, event string, body []byte) (*core.Record, error) {
+	collection, err := d.app.FindCollectionByNameOrId(util.CollectionWebhookDeliveries)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	delivery := core.NewRecord(collection)
+	delivery.Set("webhook", webhookID)
+	delivery.Set("event", event)
+	delivery.Set("payload", string(body))
+	delivery.Set("status", models.WebhookDeliveryPending)
+	delivery.Set("created_at", now)
+	delivery.Set("next_attempt_at", now)
+
+	if err := d.app.Save(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// Ping sends a ping event to a webhook right away and returns its delivery
+func (d *Dispatcher) Ping(webhookID string) (*core.Record, error) {
+	body, err := json.Marshal(models.WebhookPayload{
+		Event: models.WebhookEventPing,
+		Time:  time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := d.newDelivery(webhookID, models.WebhookEventPing, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.Deliver(delivery.Id); err != nil {
+		return nil, err
+	}
+	return d.app.FindRecordById(util.CollectionWebhookDeliveries, delivery.Id)
+}
+
+// Redeliver sends a delivery again right away, even a failed one, and
+// returns it. A failed delivery gets one more attempt.
+func (d *Dispatcher) Redeliver(id string) (*core.Record, error) {
+	delivery, err := d.app.FindRecordById(util.CollectionWebhookDeliveries, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if delivery.GetString("status") != models.WebhookDeliveryPending {
+		delivery.Set("status", models.WebhookDeliveryPending)
+		delivery.Set("next_attempt_at", time.Now().UTC())
+		if err := d.app.Save(delivery); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := d.Deliver(id); err != nil {
+		return nil, err
+	}
+	return d.app.FindRecordById(util.CollectionWebhookDeliveries, id)
+}
+
+// RetryDue sends the pending deliveries whose next attempt is due, and
+// deletes the log of deliveries finished more than 30 days ago
+func (d *Dispatcher) RetryDue() error {
+	now := time.Now().UTC()
+
+	due, err := d.app.FindRecordsByFilter(
+		util.CollectionWebhookDeliveries,
+		"status = {:status} && next_attempt_at <= {:now}",
+		"next_attempt_at",
+		100,
+		0,
+		dbx.Params{"status": models.WebhookDeliveryPending, "now": now.Format(types.DefaultDateLayout)},
+	)
+	if err != nil {
+		return err
+	}
+	for _, delivery := range due {
+		if err := d.Deliver(delivery.Id); err != nil {
+			return err
+		}
+	}
+
+	old, err := d.app.FindRecordsByFilter(
+		util.CollectionWebhookDeliveries,
+		"status != {:status} && created_at < {:before}",
+		"",
+		500,
+		0,
+		dbx.Params{"status": models.WebhookDeliveryPending, "before": now.Add(-deliveryRetention).Format(types.DefaultDateLayout)},
+	)
+	if err != nil {
+		return err
+	}
+	for _, delivery := range old {
+		if err := d.app.Delete(delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Wait blocks until the deliveries queued in the background are sent
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// Stop stops sending queued deliveries and waits for the ones being sent.
+// The rest stay pending, to be sent by RetryDue.
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	d.stopped = true
+	d.mu.Unlock()
+	d.wg.Wait()
+}
+
+// Deliver makes one attempt at sending a pending delivery and logs the
+// outcome: delivered, pending with the next attempt scheduled, or failed
+// once the retries run out. A delivery already being sent is left alone.
+func (d *Dispatcher) Deliver(id string) error {
+	if !d.claim(id) {
+		return nil
+	}
+	defer d.release(id)
+
+	delivery, err := d.app.FindRecordById(util.CollectionWebhookDeliveries, id)
+	if err != nil {
+		return err
+	}
+	if delivery.GetString("status") != models.WebhookDeliveryPending {
+		return nil
+	}
+	webhook, err := d.app.FindRecordById(util.CollectionWebhooks, delivery.GetString("webhook"))
+	if err != nil {
+		return err
+	}
+
+	status, response, sendErr := d.send(webhook, delivery)
+	now := time.Now().UTC()
+	attempts := delivery.GetInt("attempts") + 1
+
+	delivery.Set("attempts", attempts)
+	delivery.Set("response_status", status)
+	delivery.Set("response_body", util.Truncate(response, maxResponseBody))
+	delivery.Set("last_attempt_at", now)
+	switch {
+	case sendErr == nil:
+		delivery.Set("status", models.WebhookDeliveryDelivered)
+		delivery.Set("error", "")
+		delivery.Set("next_attempt_at", "")
+	case attempts > len(d.Backoff):
+		delivery.Set("status", models.WebhookDeliveryFailed)
+		delivery.Set("error", util.Truncate(sendErr.Error(), maxError))
+		delivery.Set("next_attempt_at", "")
+	default:
+		delivery.Set("error", util.Truncate(sendErr.Error(), maxError))
+		delivery.Set("next_attempt_at", now.Add(d.Backoff[attempts-1]))
+	}
+
+	return d.app.Save(delivery)
+}
+
+// send POSTs a delivery's payload to its webhook. It returns the response
+// status and the start of the response body, and an error unless the
+// webhook answered with a 2xx status.
+func (d *Dispatcher) send(webhook, delivery *core.Record) (int, string, error) {
+	body := []byte(delivery.GetString("payload"))
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, webhook.GetString("url"), bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "reverse-ats-webhooks")
+	req.Header.Set(HeaderEvent, delivery.GetString("event"))
+	req.Header.Set(HeaderDelivery, delivery.Id)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	if secret := webhook.GetString("secret"); secret != "" {
+		req.Header.Set(HeaderSignature, Sign(secret, timestamp, body))
+	}
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	response, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, string(response), fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return resp.StatusCode, string(response), nil
+}
+
+// Sign returns the signature header of a delivery: "sha256=" and the hex
+// HMAC-SHA256, keyed with the secret, of the timestamp header, a dot and
+// the body. Receivers compute the same to check the sender and reject old
+// timestamps to stop replays.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// claim marks a delivery as being sent, unless it already is
+func (d *Dispatcher) claim(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.inFlight[id] {
+		return false
+	}
+	d.inFlight[id] = true
+	return true
+}
+
+func (d *Dispatcher) release(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.inFlight, id)
+}
+
+// hasStatus reports whether a record has a status field, like roles
+func hasStatus(record *core.Record) bool {
+	return record.Collection().Fields.GetByName("status") != nil
+}
@@ -0,0 +1,521 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+
+	"reverse-ats/internal/models"
+	"reverse-ats/internal/util"
+	"reverse-ats/internal/webhooks"
+	_ "reverse-ats/pb_migrations"
+)
+
+// delivery is a request the receiver got
+type delivery struct {
+	header  http.Header
+	body    []byte
+	payload struct {
+		Event    string
+		Time     string
+		Record   map[string]any
+		Previous map[string]any
+	}
+}
+
+// receiver is a local webhook endpoint that answers with the given statuses
+// in turn, repeating the last one, and records what it got
+type receiver struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	statuses   []int
+	deliveries []delivery
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	t.Helper()
+
+	rec := &receiver{statuses: statuses}
+	rec.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got := delivery{header: r.Header.Clone(), body: body}
+		if err := json.Unmarshal(body, &got.payload); err != nil {
+			t.Errorf("invalid payload %s: %v", body, err)
+		}
+
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		rec.deliveries = append(rec.deliveries, got)
+		status := rec.statuses[min(len(rec.deliveries), len(rec.statuses))-1]
+		w.WriteHeader(status)
+		io.WriteString(w, http.StatusText(status))
+	}))
+	t.Cleanup(rec.Close)
+
+	return rec
+}
+
+func (rec *receiver) received() []delivery {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]delivery(nil), rec.deliveries...)
+}
+
+// newTestApp creates an empty app with the reverse-ats schema and a
+// dispatcher watching roles, interviews and contacts
+func newTestApp(t *testing.T) (core.App, *webhooks.Dispatcher) {
+	t.Helper()
+
+	app, err := tests.NewTestAppWithConfig(core.BaseAppConfig{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create test app: %v", err)
+	}
+	t.Cleanup(app.Cleanup)
+
+	export := func(app core.App, record *core.Record) (any, error) {
+		return record.PublicExport(), nil
+	}
+	dispatcher := webhooks.NewDispatcher(app)
+	dispatcher.Backoff = []time.Duration{0, 0}
+	dispatcher.Watch(util.CollectionRoles, "role", export)
+	dispatcher.Watch(util.CollectionInterviews, "interview", export)
+	dispatcher.Watch(util.CollectionContacts, "contact", export)
+
+	return app, dispatcher
+}
+
+// create saves a record with the given fields
+func create(t *testing.T, app core.App, collection string, fields map[string]any) *core.Record {
+	t.Helper()
+
+	coll, err := app.FindCollectionByNameOrId(collection)
+	if err != nil {
+		t.Fatalf("failed to find %s: %v", collection, err)
+	}
+
+	record := core.NewRecord(coll)
+	for name, value := range fields {
+		record.Set(name, value)
+	}
+	if err := app.Save(record); err != nil {
+		t.Fatalf("failed to save %s %v: %v", collection, fields, err)
+	}
+	return record
+}
+
+// update saves new values of a record's fields
+func update(t *testing.T, app core.App, record *core.Record, fields map[string]any) {
+	t.Helper()
+
+	for name, value := range fields {
+		record.Set(name, value)
+	}
+	if err := app.Save(record); err != nil {
+		t.Fatalf("failed to update %s: %v", record.Id, err)
+	}
+}
+
+// newRole creates a role at a new company
+func newRole(t *testing.T, app core.App, status string) *core.Record {
+	t.Helper()
+
+	company := create(t, app, util.CollectionCompanies, map[string]any{"name": "Acme"})
+	return create(t, app, util.CollectionRoles, map[string]any{
+		"company": company.Id,
+		"name":    "Staff Engineer",
+		"status":  status,
+	})
+}
+
+// onlyDelivery returns the single delivery logged for a webhook
+func onlyDelivery(t *testing.T, app core.App, webhook *core.Record) *core.Record {
+	t.Helper()
+
+	deliveries, err := app.FindAllRecords(util.CollectionWebhookDeliveries)
+	if err != nil {
+		t.Fatalf("failed to fetch deliveries: %v", err)
+	}
+	var found []*core.Record
+	for _, d := range deliveries {
+		if d.GetString("webhook") == webhook.Id {
+			found = append(found, d)
+		}
+	}
+	if len(found) != 1 {
+		t.Fatalf("got %d deliveries logged, want 1", len(found))
+	}
+	return found[0]
+}
+
+func TestDeliversSignedEvents(t *testing.T) {
+	app, dispatcher := newTestApp(t)
+	rec := newReceiver(t, http.StatusOK)
+
+	webhook := create(t, app, util.CollectionWebhooks, map[string]any{
+		"url":    rec.URL,
+		"secret": "s3cret",
+		"events": []string{models.WebhookEventRoleCreated},
+		"active": true,
+	})
+
+	role := newRole(t, app, models.RoleStatusResearch)
+	dispatcher.Wait()
+
+	got := rec.received()
+	if len(got) != 1 {
+		t.Fatalf("got %d deliveries, want 1", len(got))
+	}
+	d := got[0]
+
+	if d.payload.Event != models.WebhookEventRoleCreated {
+		t.Errorf("Event = %q, want %q", d.payload.Event, models.WebhookEventRoleCreated)
+	}
+	if d.payload.Record["id"] != role.Id || d.payload.Record["name"] != "Staff Engineer" {
+		t.Errorf("Record = %v, want the created role", d.payload.Record)
+	}
+	if d.payload.Previous != nil {
+		t.Errorf("Previous = %v, want none on create", d.payload.Previous)
+	}
+	if _, err := time.Parse(time.RFC3339, d.payload.Time); err != nil {
+		t.Errorf("Time %q isn't RFC 3339: %v", d.payload.Time, err)
+	}
+
+	if got := d.header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := d.header.Get(webhooks.HeaderEvent); got != models.WebhookEventRoleCreated {
+		t.Errorf("%s = %q", webhooks.HeaderEvent, got)
+	}
+	want := webhooks.Sign("s3cret", d.header.Get(webhooks.HeaderTimestamp), d.body)
+	if got := d.header.Get(webhooks.HeaderSignature); got != want {
+		t.Errorf("%s = %q, want %q", webhooks.HeaderSignature, got, want)
+	}
+	if webhooks.Sign("wrong", d.header.Get(webhooks.HeaderTimestamp), d.body) == want {
+		t.Error("signature doesn't depend on the secret")
+	}
+
+	logged := onlyDelivery(t, app, webhook)
+	if got := d.header.Get(webhooks.HeaderDelivery); got != logged.Id {
+		t.Errorf("%s = %q, want the logged delivery %q", webhooks.HeaderDelivery, got, logged.Id)
+	}
+	if logged.GetString("status") != models.WebhookDeliveryDelivered || logged.GetInt("attempts") != 1 || logged.GetInt("response_status") != http.StatusOK {
+		t.Errorf("logged delivery = %s after %d attempts with HTTP %d, want delivered after 1 with HTTP 200",
+			logged.GetString("status"), logged.GetInt("attempts"), logged.GetInt("response_status"))
+	}
+	if logged.GetString("payload") != string(d.body) {
+		t.Errorf("logged payload %s, want the body sent %s", logged.GetString("payload"), d.body)
+	}
+}
+
+func TestStatusChangedFilteredByStatus(t *testing.T) {
+	app, dispatcher := newTestApp(t)
+	rec := newReceiver(t, http.StatusNoContent)
+
+	create(t, app, util.CollectionWebhooks, map[string]any{
+		"url":      rec.URL,
+		"events":   []string{models.WebhookEventRoleStatusChanged},
+		"statuses": []string{models.RoleStatusOffer},
+		"active":   true,
+	})
+
+	role := newRole(t, app, models.RoleStatusApplied)
+	update(t, app, role, map[string]any{"status": models.RoleStatusInterviewing})
+	update(t, app, role, map[string]any{"notes": "Went well"})
+	dispatcher.Wait()
+	if got := rec.received(); len(got) != 0 {
+		t.Fatalf("got %d deliveries before the offer, want 0", len(got))
+	}
+
+	role, err := app.FindRecordById(util.CollectionRoles, role.Id)
+	if err != nil {
+		t.Fatalf("failed to reload role: %v", err)
+	}
+	update(t, app, role, map[string]any{"status": models.RoleStatusOffer})
+	dispatcher.Wait()
+
+	got := rec.received()
+	if len(got) != 1 {
+		t.Fatalf("got %d deliveries, want 1 for the offer", len(got))
+	}
+	if got[0].payload.Event != models.WebhookEventRoleStatusChanged {
+		t.Errorf("Event = %q", got[0].payload.Event)
+	}
+	if got[0].payload.Record["status"] != models.RoleStatusOffer || got[0].payload.Previous["status"] != models.RoleStatusInterviewing {
+		t.Errorf("status went from %v to %v, want INTERVIEWING to OFFER", got[0].payload.Previous["status"], got[0].payload.Record["status"])
+	}
+	if got[0].header.Get(webhooks.HeaderSignature) != "" {
+		t.Error("signed a delivery of a webhook without a secret")
+	}
+}
+
+func TestSubscribedEventsOnly(t *testing.T) {
+	app, dispatcher := newTestApp(t)
+	interviews := newReceiver(t, http.StatusOK)
+	paused := newReceiver(t, http.StatusOK)
+
+	create(t, app, util.CollectionWebhooks, map[string]any{
+		"url":      interviews.URL,
+		"events":   []string{models.WebhookEventInterviewCreated, models.WebhookEventContactDeleted},
+		"statuses": []string{models.RoleStatusOffer}, // Limits role events only
+		"active":   true,
+	})
+	create(t, app, util.CollectionWebhooks, map[string]any{
+		"url":    paused.URL,
+		"events": models.WebhookEvents,
+		"active": false,
+	})
+
+	role := newRole(t, app, models.RoleStatusApplied)
+	create(t, app, util.CollectionInterviews, map[string]any{
+		"role":  role.Id,
+		"date":  "2025-11-03 00:00:00.000Z",
+		"start": "10:00",
+		"end":   "11:00",
+		"type":  models.InterviewTypeTechScreen,
+	})
+	contact := create(t, app, util.CollectionContacts, map[string]any{
+		"company":    role.GetString("company"),
+		"first_name": "Jane",
+		"last_name":  "Doe",
+	})
+	if err := app.Delete(contact); err != nil {
+		t.Fatalf("failed to delete contact: %v", err)
+	}
+	dispatcher.Wait()
+
+	// Deliveries are sent concurrently, so they may arrive in any order
+	var events []string
+	for _, d := range interviews.received() {
+		events = append(events, d.payload.Event)
+	}
+	slices.Sort(events)
+	want := []string{models.WebhookEventContactDeleted, models.WebhookEventInterviewCreated}
+	if !slices.Equal(events, want) {
+		t.Errorf("got events %v, want %v", events, want)
+	}
+	if got := paused.received(); len(got) != 0 {
+		t.Errorf("paused webhook got %d deliveries, want 0", len(got))
+	}
+}
+
+func TestRetriesFailedDeliveries(t *testing.T) {
+	app, dispatcher := newTestApp(t)
+	rec := newReceiver(t, http.StatusInternalServerError, http.StatusOK)
+
+	webhook := create(t, app, util.CollectionWebhooks, map[string]any{
+		"url":    rec.URL,
+		"events": []string{models.WebhookEventRoleCreated},
+		"active": true,
+	})
+
+	newRole(t, app, models.RoleStatusResearch)
+	dispatcher.Wait()
+
+	logged := onlyDelivery(t, app, webhook)
+	if logged.GetString("status") != models.WebhookDeliveryPending || logged.GetInt("response_status") != http.StatusInternalServerError {
+		t.Fatalf("after a 500 the delivery is %s with HTTP %d, want pending with HTTP 500", logged.GetString("status"), logged.GetInt("response_status"))
+	}
+	if logged.GetString("error") == "" || logged.GetDateTime("next_attempt_at").IsZero() {
+		t.Errorf("after a 500 the delivery has error %q and next attempt %q, want both", logged.GetString("error"), logged.GetString("next_attempt_at"))
+	}
+
+	if err := dispatcher.RetryDue(); err != nil {
+		t.Fatalf("RetryDue: %v", err)
+	}
+
+	got := rec.received()
+	if len(got) != 2 {
+		t.Fatalf("got %d requests, want 2", len(got))
+	}
+	if got[0].header.Get(webhooks.HeaderDelivery) != got[1].header.Get(webhooks.HeaderDelivery) || string(got[0].body) != string(got[1].body) {
+		t.Error("the retry isn't the same delivery")
+	}
+
+	logged = onlyDelivery(t, app, webhook)
+	if logged.GetString("status") != models.WebhookDeliveryDelivered || logged.GetInt("attempts") != 2 || logged.GetString("error") != "" {
+		t.Errorf("after the retry the delivery is %s after %d attempts with error %q, want delivered after 2 with none",
+			logged.GetString("status"), logged.GetInt("attempts"), logged.GetString("error"))
+	}
+}
+
+func TestGivesUpAfterBackoff(t *testing.T) {
+	app, dispatcher := newTestApp(t)
+	rec := newReceiver(t, http.StatusBadGateway)
+
+	webhook := create(t, app, util.CollectionWebhooks, map[string]any{
+		"url":    rec.URL,
+		"events": []string{models.WebhookEventRoleDeleted},
+		"active": true,
+	})
+
+	role := newRole(t, app, models.RoleStatusResearch)
+	if err := app.Delete(role); err != nil {
+		t.Fatalf("failed to delete role: %v", err)
+	}
+	dispatcher.Wait()
+
+	// One attempt, then one retry per backoff step
+	for range 4 {
+		if err := dispatcher.RetryDue(); err != nil {
+			t.Fatalf("RetryDue: %v", err)
+		}
+	}
+
+	if got := rec.received(); len(got) != 3 {
+		t.Errorf("got %d requests, want 3", len(got))
+	}
+	if got := rec.received(); len(got) > 0 && got[0].payload.Record["id"] != role.Id {
+		t.Errorf("Record = %v, want the deleted role", got[0].payload.Record)
+	}
+	logged := onlyDelivery(t, app, webhook)
+	if logged.GetString("status") != models.WebhookDeliveryFailed || logged.GetInt("attempts") != 3 {
+		t.Fatalf("delivery is %s after %d attempts, want failed after 3", logged.GetString("status"), logged.GetInt("attempts"))
+	}
+	if !logged.GetDateTime("next_attempt_at").IsZero() {
+		t.Errorf("failed delivery still has a next attempt at %s", logged.GetString("next_attempt_at"))
+	}
+
+	// Sending it again by hand makes one more attempt
+	redelivered, err := dispatcher.Redeliver(logged.Id)
+	if err != nil {
+		t.Fatalf("Redeliver: %v", err)
+	}
+	if got := rec.received(); len(got) != 4 {
+		t.Errorf("got %d requests after resending, want 4", len(got))
+	}
+	if redelivered.GetString("status") != models.WebhookDeliveryFailed || redelivered.GetInt("attempts") != 4 {
+		t.Errorf("resent delivery is %s after %d attempts, want failed after 4", redelivered.GetString("status"), redelivered.GetInt("attempts"))
+	}
+}
+
+func TestBoundedBackgroundSending(t *testing.T) {
+	app, dispatcher := newTestApp(t)
+	dispatcher.Workers = 2
+	dispatcher.QueueSize = 2
+
+	// The receiver counts the requests it answers at once
+	var mu sync.Mutex
+	var sending, most, got int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		sending++
+		most = max(most, sending)
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		sending--
+		got++
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	create(t, app, util.CollectionWebhooks, map[string]any{
+		"url":    server.URL,
+		"events": []string{models.WebhookEventRoleCreated},
+		"active": true,
+	})
+
+	const roles = 10
+	for range roles {
+		newRole(t, app, models.RoleStatusResearch)
+	}
+	dispatcher.Wait()
+
+	// The deliveries that didn't fit in the queue are sent by the retries
+	if err := dispatcher.RetryDue(); err != nil {
+		t.Fatalf("RetryDue: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got != roles {
+		t.Errorf("got %d requests, want %d", got, roles)
+	}
+	if most > dispatcher.Workers {
+		t.Errorf("got %d requests at once, want at most %d", most, dispatcher.Workers)
+	}
+
+	pending, err := app.CountRecords(util.CollectionWebhookDeliveries, dbx.HashExp{"status": models.WebhookDeliveryPending})
+	if err != nil {
+		t.Fatalf("failed to count deliveries: %v", err)
+	}
+	if pending != 0 {
+		t.Errorf("%d deliveries still pending, want 0", pending)
+	}
+}
+
+func TestImportsSendNoEvents(t *testing.T) {
+	app, dispatcher := newTestApp(t)
+	rec := newReceiver(t, http.StatusOK)
+
+	create(t, app, util.CollectionWebhooks, map[string]any{
+		"url":    rec.URL,
+		"events": []string{models.WebhookEventRoleCreated, models.WebhookEventRoleUpdated},
+		"active": true,
+	})
+	company := create(t, app, util.CollectionCompanies, map[string]any{"name": "Acme"})
+	roles, err := app.FindCollectionByNameOrId(util.CollectionRoles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Like the importers, in a transaction
+	ctx := util.WithImport(context.Background())
+	err = app.RunInTransaction(func(txApp core.App) error {
+		role := core.NewRecord(roles)
+		role.Set("company", company.Id)
+		role.Set("name", "Staff Engineer")
+		role.Set("status", models.RoleStatusResearch)
+		if err := txApp.SaveWithContext(ctx, role); err != nil {
+			return err
+		}
+		role.Set("status", models.RoleStatusApplied)
+		return txApp.SaveWithContext(ctx, role)
+	})
+	if err != nil {
+		t.Fatalf("failed to import role: %v", err)
+	}
+	dispatcher.Wait()
+
+	if got := rec.received(); len(got) != 0 {
+		t.Errorf("got %d deliveries for an import, want 0", len(got))
+	}
+	if n, _ := app.CountRecords(util.CollectionWebhookDeliveries); n != 0 {
+		t.Errorf("logged %d deliveries for an import, want 0", n)
+	}
+}
+
+func TestPing(t *testing.T) {
+	app, dispatcher := newTestApp(t)
+	rec := newReceiver(t, http.StatusOK)
+
+	webhook := create(t, app, util.CollectionWebhooks, map[string]any{
+		"url":    rec.URL,
+		"events": []string{models.WebhookEventRoleCreated},
+		"active": false, // Tests can be sent before a webhook is turned on
+	})
+
+	delivery, err := dispatcher.Ping(webhook.Id)
+	if err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	if delivery.GetString("status") != models.WebhookDeliveryDelivered {
+		t.Errorf("ping delivery is %s, want delivered", delivery.GetString("status"))
+	}
+	if got := rec.received(); len(got) != 1 || got[0].payload.Event != models.WebhookEventPing {
+		t.Errorf("got %v, want one ping", got)
+	}
+}
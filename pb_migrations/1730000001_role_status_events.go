@@ -0,0 +1,88 @@
+package pb_migrations
+
+import (
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		roles, err := app.FindCollectionByNameOrId("roles")
+		if err != nil {
+			return err
+		}
+
+		// Create role_status_events collection
+		events := core.NewBaseCollection("role_status_events")
+
+		fromStatusField := &core.TextField{Name: "from_status"}
+		fromStatusField.Max = 100
+
+		toStatusField := &core.TextField{Name: "to_status"}
+		toStatusField.Max = 100
+
+		events.Fields.Add(
+			&core.RelationField{
+				Name:          "role",
+				Required:      true,
+				CollectionId:  roles.Id,
+				CascadeDelete: true,
+				MaxSelect:     1,
+			},
+			fromStatusField,
+			toStatusField,
+			&core.SelectField{
+				Name:      "source",
+				MaxSelect: 1,
+				Values:    []string{"form", "inline", "import", "migration"},
+			},
+			&core.DateField{Name: "changed_at", Required: true},
+		)
+		events.AddIndex("idx_role_status_events_role", false, "role, changed_at", "")
+
+		if err := app.Save(events); err != nil {
+			return err
+		}
+
+		// Start the history of the existing roles with the status they are in,
+		// as of when they were applied to, or now when they weren't
+		existing, err := app.FindAllRecords(roles)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		for _, role := range existing {
+			status := role.GetString("status")
+			if status == "" {
+				continue
+			}
+
+			changedAt := now
+			if applied := role.GetDateTime("applied_date"); !applied.IsZero() {
+				changedAt = applied.Time()
+			}
+
+			event := core.NewRecord(events)
+			event.Set("role", role.Id)
+			event.Set("from_status", "")
+			event.Set("to_status", status)
+			event.Set("source", "migration")
+			event.Set("changed_at", changedAt)
+			if err := app.Save(event); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func(app core.App) error {
+		// Down migration - drop the events collection
+		collection, err := app.FindCollectionByNameOrId("role_status_events")
+		if err == nil {
+			return app.Delete(collection)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,189 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// Values are spelled out here (instead of using internal/models) so the
+// migration keeps doing the same thing if the enums change later.
+var (
+	canonicalStatuses = []string{
+		"RESEARCH", "APPLIED", "INTERVIEWING", "OFFER", "ACCEPTED",
+		"REJECTED", "GHOSTED", "FREEZE", "WITHDREW",
+	}
+	legacyStatuses = map[string]string{
+		"RESEARCHING":   "RESEARCH",
+		"INTERVIEW":     "INTERVIEWING",
+		"OFFERED":       "OFFER",
+		"ACCEPT":        "ACCEPTED",
+		"REJECT":        "REJECTED",
+		"GHOST":         "GHOSTED",
+		"FROZEN":        "FREEZE",
+		"HIRING_FREEZE": "FREEZE",
+		"WITHDRAW":      "WITHDREW",
+		"WITHDRAWN":     "WITHDREW",
+	}
+	canonicalLocations = []string{"REMOTE", "HYBRID", "ONSITE"}
+	legacyLocations    = map[string]string{
+		"WFH":       "REMOTE",
+		"ON_SITE":   "ONSITE",
+		"IN_OFFICE": "ONSITE",
+		"IN_PERSON": "ONSITE",
+		"OFFICE":    "ONSITE",
+	}
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// Rewrite legacy values in roles and in the status history
+		if err := normalizeColumn(app, "roles", "status", canonicalStatuses, legacyStatuses); err != nil {
+			return err
+		}
+		if err := normalizeColumn(app, "roles", "location", canonicalLocations, legacyLocations); err != nil {
+			return err
+		}
+		for _, column := range []string{"from_status", "to_status"} {
+			if err := normalizeEventColumn(app, column); err != nil {
+				return err
+			}
+		}
+		// Spellings of the same status are no change
+		if _, err := app.DB().Delete("role_status_events", dbx.NewExp("[[from_status]] = [[to_status]]")).Execute(); err != nil {
+			return err
+		}
+
+		// Turn status and location into select fields
+		if err := replaceRoleField(app, &core.SelectField{
+			Name:      "status",
+			MaxSelect: 1,
+			Values:    canonicalStatuses,
+		}); err != nil {
+			return err
+		}
+
+		return replaceRoleField(app, &core.SelectField{
+			Name:      "location",
+			MaxSelect: 1,
+			Values:    canonicalLocations,
+		})
+	}, func(app core.App) error {
+		// Down migration - turn status and location back into text fields
+		statusField := &core.TextField{Name: "status"}
+		statusField.Max = 100
+		if err := replaceRoleField(app, statusField); err != nil {
+			return err
+		}
+
+		locationField := &core.TextField{Name: "location"}
+		locationField.Max = 100
+		return replaceRoleField(app, locationField)
+	})
+}
+
+// normalizeColumn upper-cases and trims a column, maps legacy spellings to
+// canonical values and moves anything still unknown into the role notes
+// so no information is lost when the column becomes a select field.
+func normalizeColumn(app core.App, table, column string, canonical []string, aliases map[string]string) error {
+	if err := canonicalizeColumn(app, table, column, aliases); err != nil {
+		return err
+	}
+
+	unknown := unknownValues(column, canonical)
+	_, err := app.DB().Update(table, dbx.Params{
+		"notes": dbx.NewExp("TRIM(COALESCE([[notes]], '') || char(10) || {:label} || [["+column+"]])", dbx.Params{"label": "Legacy " + column + ": "}),
+	}, unknown).Execute()
+	if err != nil {
+		return err
+	}
+
+	_, err = app.DB().Update(table, dbx.Params{column: ""}, unknown).Execute()
+	return err
+}
+
+// normalizeEventColumn spells a status column of the status history the way
+// normalizeColumn spells roles.status, so the history agrees with the roles.
+// Statuses still unknown are cleared, as they are in roles.
+func normalizeEventColumn(app core.App, column string) error {
+	if err := canonicalizeColumn(app, "role_status_events", column, legacyStatuses); err != nil {
+		return err
+	}
+
+	_, err := app.DB().Update("role_status_events", dbx.Params{column: ""}, unknownValues(column, canonicalStatuses)).Execute()
+	return err
+}
+
+// canonicalizeColumn upper-cases and trims a column, with underscores for
+// spaces and dashes, and maps legacy spellings to canonical values
+func canonicalizeColumn(app core.App, table, column string, aliases map[string]string) error {
+	_, err := app.DB().NewQuery(
+		"UPDATE {{" + table + "}} SET [[" + column + "]] = REPLACE(REPLACE(UPPER(TRIM([[" + column + "]])), ' ', '_'), '-', '_')" +
+			" WHERE [[" + column + "]] IS NOT NULL",
+	).Execute()
+	if err != nil {
+		return err
+	}
+
+	return rewriteAliases(app, table, column, aliases)
+}
+
+// unknownValues matches the rows of a column holding a value that isn't canonical
+func unknownValues(column string, canonical []string) dbx.Expression {
+	values := make([]any, len(canonical))
+	for i, v := range canonical {
+		values[i] = v
+	}
+	return dbx.And(
+		dbx.NewExp("[["+column+"]] != ''"),
+		dbx.NotIn(column, values...),
+	)
+}
+
+// rewriteAliases replaces every legacy value of a column with its canonical value
+func rewriteAliases(app core.App, table, column string, aliases map[string]string) error {
+	for legacy, canonical := range aliases {
+		_, err := app.DB().Update(table, dbx.Params{column: canonical}, dbx.HashExp{column: legacy}).Execute()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaceRoleField swaps a roles field for one of a different type while
+// keeping its data. PocketBase doesn't allow changing a field's type, so the
+// old field is renamed, the new one added, the data copied and the old dropped.
+func replaceRoleField(app core.App, newField core.Field) error {
+	name := newField.GetName()
+	legacyName := name + "_legacy"
+
+	roles, err := app.FindCollectionByNameOrId("roles")
+	if err != nil {
+		return err
+	}
+
+	oldField := roles.Fields.GetByName(name)
+	if oldField == nil {
+		return nil
+	}
+	oldField.SetName(legacyName)
+	if err := app.Save(roles); err != nil {
+		return err
+	}
+
+	roles.Fields.Add(newField)
+	if err := app.Save(roles); err != nil {
+		return err
+	}
+
+	_, err = app.DB().NewQuery(
+		"UPDATE {{roles}} SET [[" + name + "]] = COALESCE([[" + legacyName + "]], '')",
+	).Execute()
+	if err != nil {
+		return err
+	}
+
+	roles.Fields.RemoveByName(legacyName)
+	return app.Save(roles)
+}
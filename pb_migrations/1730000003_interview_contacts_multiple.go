@@ -0,0 +1,33 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// The initial schema left MaxSelect unset on interviews.contacts, which
+// PocketBase treats as a single relation. Existing values are converted
+// to a list by PocketBase when the field becomes multiple.
+func init() {
+	m.Register(func(app core.App) error {
+		return setInterviewContactsMaxSelect(app, 100)
+	}, func(app core.App) error {
+		// Down migration - back to a single relation
+		return setInterviewContactsMaxSelect(app, 1)
+	})
+}
+
+func setInterviewContactsMaxSelect(app core.App, maxSelect int) error {
+	interviews, err := app.FindCollectionByNameOrId("interviews")
+	if err != nil {
+		return err
+	}
+
+	field, ok := interviews.Fields.GetByName("contacts").(*core.RelationField)
+	if !ok {
+		return nil
+	}
+	field.MaxSelect = maxSelect
+
+	return app.Save(interviews)
+}
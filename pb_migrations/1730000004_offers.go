@@ -0,0 +1,59 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		roles, err := app.FindCollectionByNameOrId("roles")
+		if err != nil {
+			return err
+		}
+
+		// Create offers collection
+		offers := core.NewBaseCollection("offers")
+
+		vestingScheduleField := &core.TextField{Name: "vesting_schedule"}
+		vestingScheduleField.Max = 200
+
+		benefitsNotesField := &core.TextField{Name: "benefits_notes"}
+		benefitsNotesField.Max = 50000
+
+		negotiationNotesField := &core.TextField{Name: "negotiation_notes"}
+		negotiationNotesField.Max = 50000
+
+		offers.Fields.Add(
+			&core.RelationField{
+				Name:          "role",
+				Required:      true,
+				CollectionId:  roles.Id,
+				CascadeDelete: true,
+				MaxSelect:     1,
+			},
+			&core.NumberField{Name: "base_salary", OnlyInt: true},
+			&core.NumberField{Name: "sign_on_bonus", OnlyInt: true},
+			&core.NumberField{Name: "bonus_target_percent"},
+			&core.NumberField{Name: "equity_shares", OnlyInt: true},
+			&core.NumberField{Name: "equity_value", OnlyInt: true},
+			vestingScheduleField,
+			&core.NumberField{Name: "cliff_months", OnlyInt: true},
+			benefitsNotesField,
+			&core.DateField{Name: "deadline"},
+			&core.NumberField{Name: "negotiation_rounds", OnlyInt: true},
+			negotiationNotesField,
+		)
+		offers.AddIndex("idx_offers_role", false, "role", "")
+
+		return app.Save(offers)
+	}, func(app core.App) error {
+		// Down migration - drop the offers collection
+		collection, err := app.FindCollectionByNameOrId("offers")
+		if err == nil {
+			return app.Delete(collection)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,58 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// Create tasks collection
+		tasks := core.NewBaseCollection("tasks")
+
+		titleField := &core.TextField{Name: "title", Required: true}
+		titleField.Max = 500
+
+		taskNotesField := &core.TextField{Name: "notes"}
+		taskNotesField.Max = 50000
+
+		tasks.Fields.Add(
+			titleField,
+			&core.DateField{Name: "due_date"},
+			&core.BoolField{Name: "done"},
+			&core.DateField{Name: "completed_at"},
+			taskNotesField,
+		)
+
+		// Tasks can point at any of these; deleting the target just clears the link
+		relations := []struct{ field, collection string }{
+			{"company", "companies"},
+			{"role", "roles"},
+			{"contact", "contacts"},
+			{"interview", "interviews"},
+		}
+		for _, relation := range relations {
+			collection, err := app.FindCollectionByNameOrId(relation.collection)
+			if err != nil {
+				return err
+			}
+			tasks.Fields.Add(&core.RelationField{
+				Name:          relation.field,
+				CollectionId:  collection.Id,
+				CascadeDelete: false,
+				MaxSelect:     1,
+			})
+		}
+		tasks.AddIndex("idx_tasks_done_due_date", false, "done, due_date", "")
+
+		return app.Save(tasks)
+	}, func(app core.App) error {
+		// Down migration - drop the tasks collection
+		collection, err := app.FindCollectionByNameOrId("tasks")
+		if err == nil {
+			return app.Delete(collection)
+		}
+
+		return nil
+	})
+}
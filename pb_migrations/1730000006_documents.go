@@ -0,0 +1,86 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// documentMaxSize is the largest resume or cover letter file accepted (10MB)
+const documentMaxSize = 10 << 20
+
+func init() {
+	m.Register(func(app core.App) error {
+		// Create documents collection - a library of resume and cover letter versions
+		documents := core.NewBaseCollection("documents")
+
+		labelField := &core.TextField{Name: "label", Required: true}
+		labelField.Max = 200
+
+		documentNotesField := &core.TextField{Name: "notes"}
+		documentNotesField.Max = 50000
+
+		documents.Fields.Add(
+			labelField,
+			&core.SelectField{
+				Name:      "kind",
+				Required:  true,
+				MaxSelect: 1,
+				Values:    []string{"RESUME", "COVER_LETTER", "OTHER"},
+			},
+			&core.FileField{
+				Name:      "file",
+				Required:  true,
+				MaxSelect: 1,
+				MaxSize:   documentMaxSize,
+			},
+			documentNotesField,
+		)
+		if err := app.Save(documents); err != nil {
+			return err
+		}
+
+		// Roles keep the exact files submitted and/or reference library documents
+		roles, err := app.FindCollectionByNameOrId("roles")
+		if err != nil {
+			return err
+		}
+
+		roles.Fields.Add(
+			&core.FileField{Name: "resume_file", MaxSelect: 1, MaxSize: documentMaxSize},
+			&core.FileField{Name: "cover_letter_file", MaxSelect: 1, MaxSize: documentMaxSize},
+			&core.RelationField{
+				Name:          "resume_document",
+				CollectionId:  documents.Id,
+				CascadeDelete: false,
+				MaxSelect:     1,
+			},
+			&core.RelationField{
+				Name:          "cover_letter_document",
+				CollectionId:  documents.Id,
+				CascadeDelete: false,
+				MaxSelect:     1,
+			},
+		)
+
+		return app.Save(roles)
+	}, func(app core.App) error {
+		// Down migration - drop the role fields, then the documents collection
+		roles, err := app.FindCollectionByNameOrId("roles")
+		if err != nil {
+			return err
+		}
+		for _, name := range []string{"resume_file", "cover_letter_file", "resume_document", "cover_letter_document"} {
+			roles.Fields.RemoveByName(name)
+		}
+		if err := app.Save(roles); err != nil {
+			return err
+		}
+
+		collection, err := app.FindCollectionByNameOrId("documents")
+		if err == nil {
+			return app.Delete(collection)
+		}
+
+		return nil
+	})
+}
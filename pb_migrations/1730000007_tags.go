@@ -0,0 +1,64 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// taggedCollections are the collections that can be tagged
+var taggedCollections = []string{"companies", "roles", "contacts", "interviews"}
+
+func init() {
+	m.Register(func(app core.App) error {
+		// Create tags collection
+		tags := core.NewBaseCollection("tags")
+
+		nameField := &core.TextField{Name: "name", Required: true}
+		nameField.Max = 100
+
+		tags.Fields.Add(nameField)
+		tags.AddIndex("idx_tags_name", true, "name COLLATE NOCASE", "")
+		if err := app.Save(tags); err != nil {
+			return err
+		}
+
+		// Add a tags multi-relation to each tagged collection.
+		// Deleting a tag just removes it from the records.
+		for _, name := range taggedCollections {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				return err
+			}
+			collection.Fields.Add(&core.RelationField{
+				Name:          "tags",
+				CollectionId:  tags.Id,
+				CascadeDelete: false,
+				MaxSelect:     100,
+			})
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func(app core.App) error {
+		// Down migration - drop the tags fields, then the tags collection
+		for _, name := range taggedCollections {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				return err
+			}
+			collection.Fields.RemoveByName("tags")
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+		}
+
+		collection, err := app.FindCollectionByNameOrId("tags")
+		if err == nil {
+			return app.Delete(collection)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,48 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// importedCollections are the collections filled from CSV imports
+var importedCollections = []string{"companies", "roles", "contacts", "interviews"}
+
+func init() {
+	m.Register(func(app core.App) error {
+		// Add an external_id holding the ID a record had in the imported CSV,
+		// so re-importing the same files updates records instead of duplicating them
+		for _, name := range importedCollections {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				return err
+			}
+
+			externalIDField := &core.TextField{Name: "external_id"}
+			externalIDField.Max = 200
+
+			collection.Fields.Add(externalIDField)
+			collection.AddIndex("idx_"+name+"_external_id", true, "external_id", "external_id != ''")
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func(app core.App) error {
+		// Down migration - drop the external_id fields and their indexes
+		for _, name := range importedCollections {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				return err
+			}
+			collection.RemoveIndex("idx_" + name + "_external_id")
+			collection.Fields.RemoveByName("external_id")
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
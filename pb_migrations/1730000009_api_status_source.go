@@ -0,0 +1,36 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// Status changes made through the JSON API are recorded as such
+		return setStatusSources(app, []string{"form", "inline", "import", "migration", "api"})
+	}, func(app core.App) error {
+		// Down migration - count API changes as form changes again
+		_, err := app.DB().NewQuery("UPDATE role_status_events SET source = 'form' WHERE source = 'api'").Execute()
+		if err != nil {
+			return err
+		}
+		return setStatusSources(app, []string{"form", "inline", "import", "migration"})
+	})
+}
+
+// setStatusSources sets the values the source of a role status event can take
+func setStatusSources(app core.App, values []string) error {
+	collection, err := app.FindCollectionByNameOrId("role_status_events")
+	if err != nil {
+		return err
+	}
+
+	field, ok := collection.Fields.GetByName("source").(*core.SelectField)
+	if !ok {
+		return nil
+	}
+	field.Values = values
+
+	return app.Save(collection)
+}
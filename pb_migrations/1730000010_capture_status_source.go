@@ -0,0 +1,20 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// Roles saved from a job posting with the bookmarklet are recorded as such
+		return setStatusSources(app, []string{"form", "inline", "import", "migration", "api", "capture"})
+	}, func(app core.App) error {
+		// Down migration - count captured roles as created through the API
+		_, err := app.DB().NewQuery("UPDATE role_status_events SET source = 'api' WHERE source = 'capture'").Execute()
+		if err != nil {
+			return err
+		}
+		return setStatusSources(app, []string{"form", "inline", "import", "migration", "api"})
+	})
+}
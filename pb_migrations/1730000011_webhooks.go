@@ -0,0 +1,101 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// Create webhooks collection - URLs told about changes to roles, interviews and contacts
+		webhooks := core.NewBaseCollection("webhooks")
+
+		webhookNameField := &core.TextField{Name: "name"}
+		webhookNameField.Max = 200
+
+		secretField := &core.TextField{Name: "secret"}
+		secretField.Max = 200
+
+		webhooks.Fields.Add(
+			webhookNameField,
+			&core.URLField{Name: "url", Required: true},
+			secretField,
+			&core.SelectField{
+				Name:      "events",
+				Required:  true,
+				MaxSelect: 10,
+				Values: []string{
+					"role.created", "role.updated", "role.status_changed", "role.deleted",
+					"interview.created", "interview.updated", "interview.deleted",
+					"contact.created", "contact.updated", "contact.deleted",
+				},
+			},
+			&core.SelectField{
+				Name:      "statuses",
+				MaxSelect: 9,
+				Values:    []string{"RESEARCH", "APPLIED", "INTERVIEWING", "OFFER", "ACCEPTED", "REJECTED", "GHOSTED", "FREEZE", "WITHDREW"},
+			},
+			&core.BoolField{Name: "active"},
+		)
+		if err := app.Save(webhooks); err != nil {
+			return err
+		}
+
+		// Create webhook_deliveries collection - the delivery log, one record per event and webhook
+		deliveries := core.NewBaseCollection("webhook_deliveries")
+
+		eventField := &core.TextField{Name: "event", Required: true}
+		eventField.Max = 100
+
+		payloadField := &core.TextField{Name: "payload", Required: true}
+		payloadField.Max = 1000000
+
+		responseBodyField := &core.TextField{Name: "response_body"}
+		responseBodyField.Max = 2000
+
+		errorField := &core.TextField{Name: "error"}
+		errorField.Max = 1000
+
+		deliveries.Fields.Add(
+			&core.RelationField{
+				Name:          "webhook",
+				Required:      true,
+				CollectionId:  webhooks.Id,
+				CascadeDelete: true,
+				MaxSelect:     1,
+			},
+			eventField,
+			payloadField,
+			&core.SelectField{
+				Name:      "status",
+				Required:  true,
+				MaxSelect: 1,
+				Values:    []string{"pending", "delivered", "failed"},
+			},
+			&core.NumberField{Name: "attempts", OnlyInt: true},
+			&core.NumberField{Name: "response_status", OnlyInt: true},
+			responseBodyField,
+			errorField,
+			&core.DateField{Name: "created_at", Required: true},
+			&core.DateField{Name: "last_attempt_at"},
+			&core.DateField{Name: "next_attempt_at"},
+		)
+		deliveries.AddIndex("idx_webhook_deliveries_webhook", false, "webhook, created_at", "")
+		deliveries.AddIndex("idx_webhook_deliveries_status", false, "status, next_attempt_at", "")
+
+		return app.Save(deliveries)
+	}, func(app core.App) error {
+		// Down migration - drop the delivery log, then the webhooks
+		for _, name := range []string{"webhook_deliveries", "webhooks"} {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				continue
+			}
+			if err := app.Delete(collection); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
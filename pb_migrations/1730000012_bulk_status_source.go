@@ -0,0 +1,20 @@
+package pb_migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// Status changes made to many roles at once from the Roles list are recorded as such
+		return setStatusSources(app, []string{"form", "inline", "import", "migration", "api", "capture", "bulk"})
+	}, func(app core.App) error {
+		// Down migration - count bulk changes as made through the edit form
+		_, err := app.DB().NewQuery("UPDATE role_status_events SET source = 'form' WHERE source = 'bulk'").Execute()
+		if err != nil {
+			return err
+		}
+		return setStatusSources(app, []string{"form", "inline", "import", "migration", "api", "capture"})
+	})
+}